@@ -0,0 +1,109 @@
+package unilog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// LoggerConfig is a snapshot of a logger's configuration, taken with
+// Snapshot and reapplied with Apply. Every field but Output can be
+// serialized; empty Format and Separator and nil Output mean the handler
+// does not expose that setting.
+type LoggerConfig struct {
+	Level     LogLevel  `json:"level"`
+	Format    string    `json:"format,omitempty"`
+	Separator string    `json:"separator,omitempty"`
+	Caller    bool      `json:"caller"`
+	Trace     bool      `json:"trace"`
+	Output    io.Writer `json:"-"`
+}
+
+// Snapshot returns the current configuration of l, e.g. to restore it after
+// a test or to roll back a bad runtime reconfiguration with Apply. The level
+// and the caller and trace flags are always captured; format, separator and
+// output are captured when the handler state exposes them, as BaseHandler
+// does. The boolean is false if l is nil or was not created by this package.
+func Snapshot(l Logger) (LoggerConfig, bool) {
+	ll, ok := l.(*logger)
+	if !ok || ll == nil {
+		return LoggerConfig{}, false
+	}
+
+	cfg := LoggerConfig{Level: ll.Level()}
+
+	state := ll.Handler().HandlerState()
+	if state == nil {
+		return cfg, true
+	}
+
+	cfg.Caller = state.CallerEnabled()
+	cfg.Trace = state.TraceEnabled()
+	if s, ok := state.(interface{ Format() string }); ok {
+		cfg.Format = s.Format()
+	}
+	if s, ok := state.(interface{ Separator() string }); ok {
+		cfg.Separator = s.Separator()
+	}
+	if s, ok := state.(interface{ Output() io.Writer }); ok {
+		cfg.Output = s.Output()
+	}
+
+	return cfg, true
+}
+
+// Apply reconfigures l in place to match cfg, as taken with Snapshot.
+// The level and, if cfg.Output is not nil, the output are set when the
+// handler supports runtime reconfiguration (see MutableLogger). The other
+// settings cannot be changed in place; any of them that differs from the
+// current configuration is reported rather than ignored.
+//
+// Every setting that can be applied is, and the returned error joins one
+// error wrapping handler.ErrNotSupported per setting that could not.
+func Apply(l Logger, cfg LoggerConfig) error {
+	current, ok := Snapshot(l)
+	if !ok {
+		return fmt.Errorf("apply configuration: %w", handler.ErrNotSupported)
+	}
+
+	ll := l.(*logger)
+	ll.mu.RLock()
+	mcfg := ll.mcfg
+	ll.mu.RUnlock()
+
+	var errs []error
+	unsupported := func(setting string) {
+		errs = append(errs, fmt.Errorf("%s cannot be changed in place: %w", setting, handler.ErrNotSupported))
+	}
+
+	if cfg.Level != current.Level {
+		if mcfg == nil {
+			unsupported("level")
+		} else if err := mcfg.SetLevel(cfg.Level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.Output != nil {
+		if mcfg == nil {
+			unsupported("output")
+		} else if err := mcfg.SetOutput(cfg.Output); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.Caller != current.Caller {
+		unsupported("caller")
+	}
+	if cfg.Trace != current.Trace {
+		unsupported("trace")
+	}
+	if cfg.Format != "" && cfg.Format != current.Format {
+		unsupported("format")
+	}
+	if cfg.Separator != "" && cfg.Separator != current.Separator {
+		unsupported("separator")
+	}
+
+	return errors.Join(errs...)
+}
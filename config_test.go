@@ -0,0 +1,108 @@
+package unilog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/balinomad/go-unilog"
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// baseHandler is a minimal mutable handler whose state is a BaseHandler.
+type baseHandler struct{ *handler.BaseHandler }
+
+func (h baseHandler) Handle(context.Context, *handler.Record) error { return nil }
+func (h baseHandler) HandlerState() handler.HandlerState            { return h.BaseHandler }
+func (h baseHandler) Features() handler.HandlerFeatures             { return handler.HandlerFeatures{} }
+
+func newBaseLogger(t *testing.T, opts ...handler.BaseOption) unilog.Logger {
+	t.Helper()
+
+	base, err := handler.NewBaseHandlerFromOptions(nil, opts...)
+	if err != nil {
+		t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+	}
+	l, err := unilog.NewLogger(baseHandler{base})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	return l
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := newBaseLogger(t,
+		handler.WithOutput(&buf),
+		handler.WithLevel(handler.WarnLevel),
+		handler.WithFormat("json"),
+		handler.WithCaller(true),
+	)
+
+	cfg, ok := unilog.Snapshot(l)
+	if !ok {
+		t.Fatal("Snapshot() ok = false, want true")
+	}
+	if cfg.Level != unilog.WarnLevel || cfg.Format != "json" || !cfg.Caller || cfg.Trace || cfg.Output != &buf {
+		t.Errorf("Snapshot() = %+v, want WARN, json, caller, no trace, the output buffer", cfg)
+	}
+
+	if _, ok := unilog.Snapshot(nil); ok {
+		t.Error("Snapshot(nil) ok = true, want false")
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restores level and output", func(t *testing.T) {
+		t.Parallel()
+		var orig, other bytes.Buffer
+		l := newBaseLogger(t, handler.WithOutput(&orig), handler.WithLevel(handler.InfoLevel))
+		saved, _ := unilog.Snapshot(l)
+
+		ml := l.(unilog.MutableLogger)
+		_ = ml.SetLevel(unilog.ErrorLevel)
+		_ = ml.SetOutput(&other)
+
+		if err := unilog.Apply(l, saved); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if got, _ := unilog.Snapshot(l); got != saved {
+			t.Errorf("Snapshot() after Apply = %+v, want %+v", got, saved)
+		}
+	})
+
+	t.Run("reports unsupported settings", func(t *testing.T) {
+		t.Parallel()
+		l := newBaseLogger(t, handler.WithOutput(&bytes.Buffer{}), handler.WithFormat("text"))
+		cfg, _ := unilog.Snapshot(l)
+		cfg.Level = unilog.DebugLevel
+		cfg.Format = "json"
+		cfg.Trace = true
+
+		err := unilog.Apply(l, cfg)
+		if !errors.Is(err, handler.ErrNotSupported) {
+			t.Fatalf("Apply() error = %v, want %v", err, handler.ErrNotSupported)
+		}
+		if got := l.Level(); got != unilog.DebugLevel {
+			t.Errorf("Level() = %v, want %v applied despite the error", got, unilog.DebugLevel)
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		t.Parallel()
+		h, _ := handler.NewMemoryHandler(1024)
+		l, _ := unilog.NewLogger(h)
+		cfg, _ := unilog.Snapshot(l)
+		cfg.Level = unilog.ErrorLevel
+
+		if err := unilog.Apply(l, cfg); !errors.Is(err, handler.ErrNotSupported) {
+			t.Errorf("Apply() error = %v, want %v", err, handler.ErrNotSupported)
+		}
+	})
+}
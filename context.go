@@ -2,8 +2,13 @@ package unilog
 
 import (
 	"context"
+
+	"github.com/balinomad/go-unilog/handler"
 )
 
+// ctxLoggerKey is the context key for the logger. Being an unexported type,
+// it cannot collide with keys defined by any other package, even ones with
+// the same name; use WithLogger and LoggerFromContext to access the value.
 type ctxLoggerKey struct{}
 
 var loggerKey = ctxLoggerKey{}
@@ -43,3 +48,41 @@ func LoggerFromContextOrDefault(ctx context.Context) Logger {
 
 	return Default()
 }
+
+// LoggerFromContextOrNil retrieves the logger from the context,
+// returning nil if none is present. It suits libraries that use a
+// caller-provided logger when there is one and their own otherwise:
+//
+//	if l := unilog.LoggerFromContextOrNil(ctx); l != nil {
+//	    l.Info(ctx, "using caller's logger")
+//	}
+func LoggerFromContextOrNil(ctx context.Context) Logger {
+	logger, _ := LoggerFromContext(ctx)
+	return logger
+}
+
+// LoggerFromContextOr retrieves the logger from the context, falling back
+// to the given logger, never to the default one, if none is present. It
+// suits libraries that take an explicit logger but let a request-scoped
+// logger take precedence:
+//
+//	logger := unilog.LoggerFromContextOr(ctx, c.logger)
+func LoggerFromContextOr(ctx context.Context, fallback Logger) Logger {
+	if logger, ok := LoggerFromContext(ctx); ok {
+		return logger
+	}
+
+	return fallback
+}
+
+// WithLogLevel returns a context whose records are logged at level and above
+// when the handler is wrapped with handler.NewContextLevelHandler, enabling
+// per-request verbosity:
+//
+//	ctx = unilog.WithLogLevel(ctx, unilog.DebugLevel)
+//	logger.Debug(ctx, "only logged for this request")
+//
+// Other handlers ignore the context level.
+func WithLogLevel(ctx context.Context, level LogLevel) context.Context {
+	return handler.WithContextLevel(ctx, level)
+}
@@ -75,6 +75,102 @@ func TestContext(t *testing.T) {
 	}
 }
 
+func TestLoggerFromContextOrNil(t *testing.T) {
+	t.Parallel()
+
+	stored := newMockLogger()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want unilog.Logger
+	}{
+		{"context with logger", unilog.WithLogger(context.Background(), stored), stored},
+		{"empty context", context.Background(), nil},
+		{"nil context", nil, nil},
+		{"wrong type", context.WithValue(context.Background(), unilog.XLoggerKey, "not a logger"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := unilog.LoggerFromContextOrNil(tt.ctx)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("LoggerFromContextOrNil() = %v, want nil", got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("LoggerFromContextOrNil() = %v, want stored logger", got)
+			}
+		})
+	}
+}
+
+func TestLoggerFromContextOr(t *testing.T) {
+	t.Parallel()
+
+	stored := newMockLogger()
+	fallback := newMockLogger()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		fallback unilog.Logger
+		want     unilog.Logger
+	}{
+		{"context with logger", unilog.WithLogger(context.Background(), stored), fallback, stored},
+		{"empty context", context.Background(), fallback, fallback},
+		{"nil context", nil, fallback, fallback},
+		{"wrong type", context.WithValue(context.Background(), unilog.XLoggerKey, "not a logger"), fallback, fallback},
+		{"nil fallback", context.Background(), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := unilog.LoggerFromContextOr(tt.ctx, tt.fallback); got != tt.want {
+				t.Errorf("LoggerFromContextOr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ctxLoggerKey mirrors the name of the package's private key type.
+type ctxLoggerKey struct{}
+
+func TestLoggerFromContext_KeyCollision(t *testing.T) {
+	t.Parallel()
+
+	stored := newMockLogger()
+	foreign := newMockLogger()
+
+	t.Run("foreign keys are not visible", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.WithValue(context.Background(), ctxLoggerKey{}, foreign)
+		ctx = context.WithValue(ctx, testKey("logger"), foreign)
+
+		if logger, ok := unilog.LoggerFromContext(ctx); ok || logger != nil {
+			t.Errorf("LoggerFromContext() = %v, %v; want nil, false", logger, ok)
+		}
+	})
+
+	t.Run("foreign keys do not shadow stored logger", func(t *testing.T) {
+		t.Parallel()
+		ctx := unilog.WithLogger(context.Background(), stored)
+		ctx = context.WithValue(ctx, ctxLoggerKey{}, foreign)
+
+		logger, ok := unilog.LoggerFromContext(ctx)
+		if !ok || logger != stored {
+			t.Errorf("LoggerFromContext() = %v, %v; want stored logger", logger, ok)
+		}
+		if got := ctx.Value(ctxLoggerKey{}); got != foreign {
+			t.Errorf("foreign key value = %v, want foreign logger", got)
+		}
+	})
+}
+
 func TestWithLogger(t *testing.T) {
 	tests := []struct {
 		name      string
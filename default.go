@@ -66,11 +66,42 @@ func Default() Logger {
 	return global.logger
 }
 
+// WrapperLogger returns the default logger with caller reporting adjusted by
+// skip frames, so that it points at the caller of a logging wrapper rather
+// than at the wrapper itself. skip is the number of wrapper frames between
+// the code to report and the call into the returned logger. For a
+// single-level wrapper it is 1:
+//
+//	// Package mylog
+//	func Info(ctx context.Context, msg string, keyValues ...any) {
+//	    unilog.WrapperLogger(1).Info(ctx, msg, keyValues...)
+//	}
+//
+// A helper called from such a wrapper adds one more frame and uses 2.
+// Zero or negative skip returns the default logger unchanged, as does a
+// default logger that cannot adjust caller reporting (see Logger.Skip).
+//
+// For wrappers whose call depth varies, see WithCallerSkipPackages.
+func WrapperLogger(skip int) Logger {
+	l := Default()
+	if skip <= 0 {
+		return l
+	}
+
+	return l.Skip(skip)
+}
+
+// skipLogger is implemented by loggers that can report a caller further up
+// the stack than their own caller: AdvancedLogger and the fallback logger.
+type skipLogger interface {
+	LogWithSkip(ctx context.Context, level LogLevel, msg string, delta int, keyValues ...any)
+}
+
 // logWithDefault logs a message at the given level using the global default logger.
 func logWithDefault(ctx context.Context, level LogLevel, msg string, skip int, keyValues ...any) {
 	dl := Default()
-	if adv, ok := dl.(AdvancedLogger); ok {
-		adv.LogWithSkip(ctx, level, msg, skip+packageAdditionalSkipFrame, keyValues...)
+	if sl, ok := dl.(skipLogger); ok {
+		sl.LogWithSkip(ctx, level, msg, skip+packageAdditionalSkipFrame, keyValues...)
 		return
 	}
 	dl.Log(ctx, level, msg, keyValues...)
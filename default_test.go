@@ -2,6 +2,7 @@ package unilog_test
 
 import (
 	"context"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -319,6 +320,40 @@ func TestCallerSkipperPath(t *testing.T) {
 	}
 }
 
+// wrapperInfo is a single-level logging wrapper as a library would write it.
+// It returns the logger it used so the test can inspect its handler.
+func wrapperInfo(ctx context.Context, msg string) unilog.Logger {
+	l := unilog.WrapperLogger(1)
+	l.Info(ctx, msg)
+	return l
+}
+
+func TestWrapperLogger(t *testing.T) {
+	resetDefault()
+	defer resetDefault()
+
+	h := newMockHandler()
+	h.state = &mockHandlerState{caller: true}
+	l, err := unilog.NewLogger(h)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	unilog.SetDefault(l)
+
+	used := wrapperInfo(context.Background(), "msg")
+
+	frame, _ := runtime.CallersFrames([]uintptr{getMockHandler(t, used).LastRecord().PC}).Next()
+	if !strings.HasSuffix(frame.Function, ".TestWrapperLogger") {
+		t.Errorf("caller = %s, want TestWrapperLogger", frame.Function)
+	}
+
+	for _, skip := range []int{0, -1} {
+		if got := unilog.WrapperLogger(skip); got != l {
+			t.Errorf("WrapperLogger(%d) should return the default logger unchanged", skip)
+		}
+	}
+}
+
 // TestGlobalLogFunctions covers all global logging functions.
 func TestGlobalLogFunctions(t *testing.T) {
 	resetDefault()
@@ -0,0 +1,67 @@
+package unilog
+
+import (
+	"os"
+	"sort"
+)
+
+// defaultEnvMapping maps common deployment environment variables to field
+// names. The Kubernetes pod variables are not set by Kubernetes itself; they
+// follow the usual names given to them with the downward API.
+var defaultEnvMapping = map[string]string{
+	"HOSTNAME":          "host",
+	"POD_NAME":          "k8s_pod",
+	"POD_NAMESPACE":     "k8s_namespace",
+	"NODE_NAME":         "k8s_node",
+	"OTEL_SERVICE_NAME": "service",
+	"APP_VERSION":       "app_version",
+	"GIT_COMMIT":        "git_commit",
+}
+
+// AttrsFromEnv reads the environment variables named by the keys of mapping
+// and returns their values as key-value pairs under the mapped field names,
+// sorted by field name. Unset variables are skipped; set but empty ones are
+// kept. The environment is read once, when AttrsFromEnv is called, so the
+// result is meant to seed a logger at startup:
+//
+//	logger = logger.With(unilog.AttrsFromEnv(map[string]string{
+//	    "HOSTNAME":    "host",
+//	    "APP_VERSION": "version",
+//	})...)
+func AttrsFromEnv(mapping map[string]string) []any {
+	type field struct{ key, value string }
+
+	fields := make([]field, 0, len(mapping))
+	for env, key := range mapping {
+		if value, ok := os.LookupEnv(env); ok {
+			fields = append(fields, field{key, value})
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].key != fields[j].key {
+			return fields[i].key < fields[j].key
+		}
+		return fields[i].value < fields[j].value
+	})
+
+	keyValues := make([]any, 0, 2*len(fields))
+	for _, f := range fields {
+		keyValues = append(keyValues, f.key, f.value)
+	}
+
+	return keyValues
+}
+
+// DefaultEnvAttrs returns AttrsFromEnv for common deployment variables:
+//
+//	HOSTNAME           host
+//	POD_NAME           k8s_pod
+//	POD_NAMESPACE      k8s_namespace
+//	NODE_NAME          k8s_node
+//	OTEL_SERVICE_NAME  service
+//	APP_VERSION        app_version
+//	GIT_COMMIT         git_commit
+func DefaultEnvAttrs() []any {
+	return AttrsFromEnv(defaultEnvMapping)
+}
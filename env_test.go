@@ -0,0 +1,38 @@
+package unilog_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/balinomad/go-unilog"
+)
+
+func TestAttrsFromEnv(t *testing.T) {
+	// Not parallel: t.Setenv changes the process environment
+	t.Setenv("UNILOG_TEST_VERSION", "1.2.3")
+	t.Setenv("UNILOG_TEST_REGION", "")
+
+	got := unilog.AttrsFromEnv(map[string]string{
+		"UNILOG_TEST_VERSION": "version",
+		"UNILOG_TEST_REGION":  "region",
+		"UNILOG_TEST_UNSET":   "unset",
+	})
+
+	want := []any{"region", "", "version", "1.2.3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("AttrsFromEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestDefaultEnvAttrs(t *testing.T) {
+	t.Setenv("HOSTNAME", "web-1")
+	t.Setenv("POD_NAMESPACE", "prod")
+
+	got := unilog.DefaultEnvAttrs()
+	for _, want := range [][2]string{{"host", "web-1"}, {"k8s_namespace", "prod"}} {
+		i := slices.Index(got, any(want[0]))
+		if i < 0 || i+1 >= len(got) || got[i+1] != want[1] {
+			t.Errorf("DefaultEnvAttrs() = %v, want %s=%s", got, want[0], want[1])
+		}
+	}
+}
@@ -21,6 +21,9 @@ type XFallbackLogger = fallbackLogger
 // XNewLogger exposes the internal newLogger constructor for invariant testing.
 var XNewLogger = newLogger
 
+// XValidateKeyValues exposes the strict mode key-value check.
+var XValidateKeyValues = validateKeyValues
+
 // XInternalSkipFrames exposes the call stack depth constant to ensure tests
 // remain robust if internal implementation depth changes.
 const XInternalSkipFrames = internalSkipFrames
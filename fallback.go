@@ -19,12 +19,21 @@ import (
 //
 // Not meant for production use. Applications should configure a proper handler-backed logger.
 type fallbackLogger struct {
-	mu  sync.Mutex
-	w   io.Writer
-	l   *log.Logger
-	lvl handler.LogLevel
+	mu     sync.Mutex
+	w      io.Writer
+	l      *log.Logger
+	lvl    handler.LogLevel
+	fields string // Pre-rendered constant fields, emitted on every line
+	skip   int    // Extra frames skipped when reporting the caller, set by Skip
 }
 
+// fallbackCallDepth is the log.Logger.Output call depth of the code calling
+// a fallbackLogger method:
+//  1. fallbackLogger.log()
+//  2. fallbackLogger.Info() / Log() / LogWithSkip()
+//  3. caller                              ← Reported
+const fallbackCallDepth = 3
+
 // Ensure fallbackLogger implements Logger.
 var _ Logger = (*fallbackLogger)(nil)
 
@@ -40,11 +49,31 @@ func newFallbackLogger(w io.Writer, level handler.LogLevel) (*fallbackLogger, er
 
 	return &fallbackLogger{
 		w:   w,
-		l:   log.New(w, "[FALLBACK] ", log.LstdFlags),
+		l:   log.New(w, "[FALLBACK] ", log.LstdFlags|log.Lshortfile),
 		lvl: level,
 	}, nil
 }
 
+// NewFallbackLoggerWithFields creates a minimal logger writing to w that emits
+// the given key-value pairs on every line, using the same key=value rendering
+// as the record's own pairs. It is intended for bootstrap logging before the
+// main logger is configured (e.g., with "phase", "bootstrap").
+// Like the default fallback logger, With and WithGroup are no-ops, so these
+// constant fields are the only way to attach context to it.
+// Returns error if writer is nil or level is invalid.
+func NewFallbackLoggerWithFields(w io.Writer, level LogLevel, keyValues ...any) (Logger, error) {
+	l, err := newFallbackLogger(w, level)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	writeKeyValues(&sb, keyValues)
+	l.fields = sb.String()
+
+	return l, nil
+}
+
 // newSimpleFallbackLogger creates a fallback logger with stderr output and InfoLevel.
 // Never returns error; panics only if os.Stderr is nil (should never happen).
 func newSimpleFallbackLogger() *fallbackLogger {
@@ -60,6 +89,24 @@ func newSimpleFallbackLogger() *fallbackLogger {
 
 // Log prints a log message if the given level is enabled.
 func (l *fallbackLogger) Log(_ context.Context, level LogLevel, msg string, keyValues ...any) {
+	l.log(level, msg, 0, keyValues...)
+}
+
+// LogWithSkip logs a message like Log, reporting the caller delta frames
+// above the caller of LogWithSkip.
+func (l *fallbackLogger) LogWithSkip(_ context.Context, level LogLevel, msg string, delta int, keyValues ...any) {
+	l.log(level, msg, delta, keyValues...)
+}
+
+// CallerSkip returns the number of extra frames skipped when reporting the caller.
+func (l *fallbackLogger) CallerSkip() int {
+	return l.skip
+}
+
+// log prints a log message if the given level is enabled, reporting the
+// caller of the public method delta frames up. It must be called directly
+// by a public method to keep fallbackCallDepth accurate.
+func (l *fallbackLogger) log(level LogLevel, msg string, delta int, keyValues ...any) {
 	if !l.Enabled(level) {
 		return
 	}
@@ -71,14 +118,10 @@ func (l *fallbackLogger) Log(_ context.Context, level LogLevel, msg string, keyV
 	sb.WriteString(level.String())
 	sb.WriteString(": ")
 	sb.WriteString(msg)
-	for i := 0; i < len(keyValues)-1; i += 2 {
-		sb.WriteString(" ")
-		sb.WriteString(fmt.Sprint(keyValues[i]))
-		sb.WriteString("=")
-		sb.WriteString(fmt.Sprint(keyValues[i+1]))
-	}
+	sb.WriteString(l.fields)
+	writeKeyValues(&sb, keyValues)
 
-	l.l.Println(sb.String())
+	_ = l.l.Output(max(fallbackCallDepth+l.skip+delta, 1), sb.String())
 
 	// Handle termination levels
 	switch level {
@@ -97,6 +140,14 @@ func (l *fallbackLogger) Enabled(level LogLevel) bool {
 	return level >= l.lvl
 }
 
+// Level returns the minimum level that is logged.
+func (l *fallbackLogger) Level() LogLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lvl
+}
+
 // With is a no-op for the fallback logger. It returns itself unchanged.
 func (l *fallbackLogger) With(keyValues ...any) Logger {
 	return l
@@ -107,42 +158,77 @@ func (l *fallbackLogger) WithGroup(name string) Logger {
 	return l
 }
 
+// WithGroupf is a no-op for the fallback logger. It returns itself unchanged.
+func (l *fallbackLogger) WithGroupf(format string, args ...any) Logger {
+	return l
+}
+
+// Skip returns a logger sharing l's output that reports the caller n frames
+// further up the stack. It returns l if n is zero.
+func (l *fallbackLogger) Skip(n int) Logger {
+	if n == 0 {
+		return l
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return &fallbackLogger{
+		w:      l.w,
+		l:      l.l,
+		lvl:    l.lvl,
+		fields: l.fields,
+		skip:   max(l.skip+n, 0),
+	}
+}
+
 // Trace logs a message at the trace level.
-func (l *fallbackLogger) Trace(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, TraceLevel, msg, keyValues...)
+func (l *fallbackLogger) Trace(_ context.Context, msg string, keyValues ...any) {
+	l.log(TraceLevel, msg, 0, keyValues...)
 }
 
 // Debug logs a message at the debug level.
-func (l *fallbackLogger) Debug(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, DebugLevel, msg, keyValues...)
+func (l *fallbackLogger) Debug(_ context.Context, msg string, keyValues ...any) {
+	l.log(DebugLevel, msg, 0, keyValues...)
 }
 
 // Info logs a message at the info level.
-func (l *fallbackLogger) Info(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, InfoLevel, msg, keyValues...)
+func (l *fallbackLogger) Info(_ context.Context, msg string, keyValues ...any) {
+	l.log(InfoLevel, msg, 0, keyValues...)
 }
 
 // Warn logs a message at the warn level.
-func (l *fallbackLogger) Warn(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, WarnLevel, msg, keyValues...)
+func (l *fallbackLogger) Warn(_ context.Context, msg string, keyValues ...any) {
+	l.log(WarnLevel, msg, 0, keyValues...)
 }
 
 // Error logs a message at the error level.
-func (l *fallbackLogger) Error(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, ErrorLevel, msg, keyValues...)
+func (l *fallbackLogger) Error(_ context.Context, msg string, keyValues ...any) {
+	l.log(ErrorLevel, msg, 0, keyValues...)
 }
 
 // Critical logs a message at the critical level.
-func (l *fallbackLogger) Critical(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, CriticalLevel, msg, keyValues...)
+func (l *fallbackLogger) Critical(_ context.Context, msg string, keyValues ...any) {
+	l.log(CriticalLevel, msg, 0, keyValues...)
 }
 
 // Fatal logs a message at the fatal level and exits the process.
-func (l *fallbackLogger) Fatal(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, FatalLevel, msg, keyValues...)
+func (l *fallbackLogger) Fatal(_ context.Context, msg string, keyValues ...any) {
+	l.log(FatalLevel, msg, 0, keyValues...)
 }
 
 // Panic logs a message at the panic level and panics.
-func (l *fallbackLogger) Panic(ctx context.Context, msg string, keyValues ...any) {
-	l.Log(ctx, PanicLevel, msg, keyValues...)
+func (l *fallbackLogger) Panic(_ context.Context, msg string, keyValues ...any) {
+	l.log(PanicLevel, msg, 0, keyValues...)
+}
+
+// writeKeyValues writes key-value pairs as " key=value" to sb.
+// A trailing key without value is ignored.
+func writeKeyValues(sb *strings.Builder, keyValues []any) {
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		sb.WriteString(" ")
+		sb.WriteString(fmt.Sprint(keyValues[i]))
+		sb.WriteString("=")
+		sb.WriteString(handler.FormatValue(keyValues[i+1]))
+	}
 }
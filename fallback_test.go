@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -176,6 +177,114 @@ func TestFallbackLogger_Immutability(t *testing.T) {
 	if l.WithGroup("g") != l {
 		t.Error("WithGroup should return same instance")
 	}
+	if l.Skip(0) != l {
+		t.Error("Skip(0) should return same instance")
+	}
+	if l.Skip(1) == l {
+		t.Error("Skip(1) should return a new instance")
+	}
+	if got := l.Skip(2).Skip(-1).(*unilog.XFallbackLogger).CallerSkip(); got != 1 {
+		t.Errorf("CallerSkip() = %d, want 1", got)
+	}
+}
+
+// fallbackLogHelper logs through l from one frame below its caller.
+func fallbackLogHelper(l unilog.Logger, msg string) {
+	l.Skip(1).Info(context.Background(), msg)
+}
+
+func TestFallbackLogger_Caller(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		logOp func(*unilog.XFallbackLogger) int // Returns the expected line
+	}{
+		{
+			name: "level method",
+			logOp: func(l *unilog.XFallbackLogger) int {
+				_, _, line, _ := runtime.Caller(0)
+				l.Info(context.Background(), "msg")
+				return line + 1
+			},
+		},
+		{
+			name: "log with skip",
+			logOp: func(l *unilog.XFallbackLogger) int {
+				_, _, line, _ := runtime.Caller(0)
+				l.LogWithSkip(context.Background(), unilog.InfoLevel, "msg", 0)
+				return line + 1
+			},
+		},
+		{
+			name: "skip",
+			logOp: func(l *unilog.XFallbackLogger) int {
+				_, _, line, _ := runtime.Caller(0)
+				fallbackLogHelper(l, "msg")
+				return line + 1
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			l, _ := unilog.XNewFallbackLogger(&buf, unilog.InfoLevel)
+
+			want := fmt.Sprintf("fallback_test.go:%d: ", tt.logOp(l))
+			if got := buf.String(); !strings.Contains(got, want) {
+				t.Errorf("got %q, want caller %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNewFallbackLoggerWithFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fields on every line", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		l, err := unilog.NewFallbackLoggerWithFields(&buf, unilog.InfoLevel, "phase", "bootstrap", "pid", 7)
+		if err != nil {
+			t.Fatalf("NewFallbackLoggerWithFields() error = %v", err)
+		}
+
+		l.Info(context.Background(), "starting", "step", 1)
+		l.Warn(context.Background(), "slow")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+		}
+		if want := "INFO: starting phase=bootstrap pid=7 step=1"; !strings.HasSuffix(lines[0], want) {
+			t.Errorf("line 1 = %q, want suffix %q", lines[0], want)
+		}
+		if want := "WARN: slow phase=bootstrap pid=7"; !strings.HasSuffix(lines[1], want) {
+			t.Errorf("line 2 = %q, want suffix %q", lines[1], want)
+		}
+	})
+
+	t.Run("odd fields", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		l, _ := unilog.NewFallbackLoggerWithFields(&buf, unilog.InfoLevel, "phase", "bootstrap", "dangling")
+		l.Info(context.Background(), "msg")
+		if got := buf.String(); strings.Contains(got, "dangling") {
+			t.Errorf("output %q contains unpaired key", got)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+		if _, err := unilog.NewFallbackLoggerWithFields(nil, unilog.InfoLevel); err == nil {
+			t.Error("nil writer: error = nil, want error")
+		}
+		if _, err := unilog.NewFallbackLoggerWithFields(io.Discard, unilog.LogLevel(100)); err == nil {
+			t.Error("invalid level: error = nil, want error")
+		}
+	})
 }
 
 func TestFallbackLogger_Enabled(t *testing.T) {
@@ -238,6 +347,17 @@ func TestFallbackLogger_Enabled(t *testing.T) {
 	}
 }
 
+func TestFallbackLogger_Level(t *testing.T) {
+	logger, err := unilog.XNewFallbackLogger(io.Discard, unilog.WarnLevel)
+	if err != nil {
+		t.Fatalf("NewFallbackLogger() error = %v", err)
+	}
+
+	if got := logger.Level(); got != unilog.WarnLevel {
+		t.Errorf("Level() = %v, want %v", got, unilog.WarnLevel)
+	}
+}
+
 func TestFallbackLogger_With(t *testing.T) {
 	logger, err := unilog.XNewFallbackLogger(io.Discard, unilog.InfoLevel)
 	if err != nil {
@@ -264,6 +384,17 @@ func TestFallbackLogger_WithGroup(t *testing.T) {
 	}
 }
 
+func TestFallbackLogger_WithGroupf(t *testing.T) {
+	logger, err := unilog.XNewFallbackLogger(io.Discard, unilog.InfoLevel)
+	if err != nil {
+		t.Fatalf("NewFallbackLogger() error = %v", err)
+	}
+
+	if logger.WithGroupf("shard-%d", 1) != logger {
+		t.Error("WithGroupf() should return the same instance for fallbackLogger")
+	}
+}
+
 func TestFallbackLogger_LevelMethods(t *testing.T) {
 	tests := []struct {
 		name      string
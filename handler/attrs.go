@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+)
+
+// attrState accumulates attributes and group prefixes for handlers that keep
+// records as flat key-value lists (e.g., RingBufferHandler), emulating
+// WithAttrs and WithGroup by key prefixing with DefaultKeySeparator.
+// It is immutable: the with* methods return modified copies.
+type attrState struct {
+	keyValues []any  // Accumulated attributes, keys already prefixed
+	keyPrefix string // Accumulated group prefix
+}
+
+// withAttrs returns a copy with keyValues appended under the current prefix.
+func (s attrState) withAttrs(keyValues []any) attrState {
+	s.keyValues = appendPrefixed(slices.Clip(s.keyValues), s.keyPrefix, keyValues)
+	return s
+}
+
+// withGroup returns a copy that qualifies subsequent keys with name.
+func (s attrState) withGroup(name string) attrState {
+	if s.keyPrefix == "" {
+		s.keyPrefix = name
+	} else {
+		s.keyPrefix = s.keyPrefix + DefaultKeySeparator + name
+	}
+	return s
+}
+
+// merge returns a new slice holding the accumulated attributes followed by
+// keyValues qualified with the current prefix.
+func (s attrState) merge(keyValues []any) []any {
+	kv := make([]any, 0, len(s.keyValues)+len(keyValues))
+	kv = append(kv, s.keyValues...)
+
+	return appendPrefixed(kv, s.keyPrefix, keyValues)
+}
+
+// appendPrefixed appends keyValues to dst, qualifying keys with prefix.
+// A trailing key without value is dropped.
+func appendPrefixed(dst []any, prefix string, keyValues []any) []any {
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		key := fmt.Sprint(keyValues[i])
+		if prefix != "" {
+			key = prefix + DefaultKeySeparator + key
+		}
+		dst = append(dst, key, keyValues[i+1])
+	}
+
+	return dst
+}
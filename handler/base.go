@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"maps"
+	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"text/template"
 
 	"github.com/balinomad/go-atomicwriter"
 )
@@ -16,12 +23,20 @@ type StateFlag uint32
 
 const (
 	FlagCaller StateFlag = 1 << iota // Enable caller location reporting
-	FlagTrace                        // Enable stack trace reporting for ERROR and above
+	FlagTrace                        // Enable stack trace reporting at or above the trace level
 )
 
+// DefaultTraceLevel is the default minimum level that carries a stack trace
+// when tracing is enabled.
+const DefaultTraceLevel = ErrorLevel
+
 // DefaultKeySeparator is the default separator for group key prefixes.
 const DefaultKeySeparator = "_"
 
+// DefaultHumanMessageKey is the default key of the rendered message that
+// structured outputs add when a human message template is set.
+const DefaultHumanMessageKey = "human_msg"
+
 // BaseOptions holds configuration common to most handlers.
 type BaseOptions struct {
 	Level  LogLevel  // Minimum log level
@@ -41,6 +56,83 @@ type BaseOptions struct {
 	WithTrace  bool   // True if stack traces should be included
 	CallerSkip int    // User-specified caller skip frames
 	Separator  string // Key prefix separator (default: "_")
+
+	// TraceLevel is the minimum level that carries a stack trace when
+	// tracing is enabled. The zero value (DebugLevel) means unset and uses
+	// DefaultTraceLevel; set DebugLevel with WithTraceLevel.
+	TraceLevel    LogLevel
+	traceLevelSet bool // TraceLevel was set with an option
+
+	// MaxStackDepth limits the number of frames in captured stack traces.
+	// Zero uses DefaultMaxStackDepth.
+	MaxStackDepth int
+
+	// MetricsProvider is notified of every handled record and handling error.
+	// Nil disables metrics.
+	MetricsProvider MetricsProvider
+
+	// OutputSwapHook is called after SetOutput replaced the output writer.
+	// Nil disables the notification.
+	OutputSwapHook func(old, new io.Writer)
+
+	// FieldValidator checks every field key before the record is formatted.
+	// Nil disables validation.
+	FieldValidator func(key string) error
+
+	// LevelNames overrides the rendered name of individual levels.
+	// Levels without an entry fall back to LogLevel.String().
+	LevelNames map[LogLevel]string
+
+	// JSONValues renders map, slice and array values as compact JSON in
+	// text output (see FormatJSONValue).
+	JSONValues bool
+
+	// HumanMessageTemplate renders a readable message from the record's
+	// attributes. Nil disables rendering.
+	HumanMessageTemplate *template.Template
+
+	// HumanMessageKey is the key of the rendered message in structured output.
+	// Empty uses DefaultHumanMessageKey.
+	HumanMessageKey string
+
+	// FailFast makes NewBaseHandler probe Output with an empty write and
+	// fail if it returns an error.
+	FailFast bool
+
+	// EnabledLevels lists the only levels the handler processes, overriding
+	// the Level threshold. Empty uses the threshold.
+	EnabledLevels []LogLevel
+
+	// CallerShortPath trims the caller file to its last two path elements
+	// (see ShortCallerPath).
+	CallerShortPath bool
+
+	// RedactPatterns are applied, in order, to messages and string values.
+	RedactPatterns []RedactPattern
+
+	// MaxLineBytes caps every write to the output (see TruncateLine).
+	// Zero disables the cap.
+	MaxLineBytes int
+
+	// IndependentOutput gives every clone its own AtomicWriter, so that
+	// SetOutput on a clone leaves the original's output unchanged.
+	IndependentOutput bool
+
+	// CallerLevel is the minimum level that carries caller information when
+	// caller reporting is enabled. MinLevel reports it at every level. The
+	// zero value (DebugLevel) means unset and uses MinLevel; set DebugLevel
+	// with WithCallerLevel.
+	CallerLevel    LogLevel
+	callerLevelSet bool // CallerLevel was set with an option
+
+	// KeyCase normalizes the casing of attribute keys (see KeyCase.Convert).
+	KeyCase KeyCase
+}
+
+// RedactPattern replaces the matches of a regular expression in log output.
+type RedactPattern struct {
+	Regexp      *regexp.Regexp
+	Replacement string // Expanded as in regexp.Regexp.ReplaceAllString
 }
 
 // BaseOption configures the BaseHandler.
@@ -99,7 +191,36 @@ func WithCaller(enabled bool) BaseOption {
 	}
 }
 
-// WithTrace enabless or disables stack traces for ERROR and above.
+// WithCallerShortPath trims the file of the reported caller to its package
+// directory and file name, e.g. "handler/base.go" instead of the absolute
+// path, which keeps build machine paths out of the logs.
+// It has no effect unless WithCaller is enabled.
+// The default value is false.
+func WithCallerShortPath(enabled bool) BaseOption {
+	return func(o *BaseOptions) error {
+		o.CallerShortPath = enabled
+		return nil
+	}
+}
+
+// WithCallerLevel sets the minimum level that carries caller information
+// when caller reporting is enabled, e.g. WarnLevel to skip the cost of
+// resolving callers for routine records.
+// It has no effect unless WithCaller is enabled.
+// The default value is MinLevel.
+func WithCallerLevel(level LogLevel) BaseOption {
+	return func(o *BaseOptions) error {
+		if err := ValidateLogLevel(level); err != nil {
+			return NewOptionApplyError("WithCallerLevel", err)
+		}
+		o.CallerLevel = level
+		o.callerLevelSet = true
+		return nil
+	}
+}
+
+// WithTrace enabless or disables stack traces for records at or above the
+// trace level (ERROR unless changed with WithTraceLevel).
 // If enabled, the handler will include the stack trace of the log
 // call site in the log record. This can be useful for debugging, but may
 // incur a performance hit due to the additional stack frame analysis.
@@ -111,6 +232,319 @@ func WithTrace(enabled bool) BaseOption {
 	}
 }
 
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. Setting it to PanicLevel limits stacks to panics,
+// which keeps services with noisy but non-fatal errors compact.
+// It has no effect unless WithTrace is enabled.
+// The default value is DefaultTraceLevel.
+func WithTraceLevel(level LogLevel) BaseOption {
+	return func(o *BaseOptions) error {
+		if err := ValidateLogLevel(level); err != nil {
+			return NewOptionApplyError("WithTraceLevel", err)
+		}
+		o.TraceLevel = level
+		o.traceLevelSet = true
+		return nil
+	}
+}
+
+// WithMaxStackDepth limits captured stack traces to the top n frames
+// closest to the log call site, after runtime and unilog frames are removed.
+// It keeps logs compact while preserving the most relevant frames.
+// Backends that capture stack traces natively and have no depth setting
+// (e.g., zap) ignore this value. The default value is DefaultMaxStackDepth.
+func WithMaxStackDepth(n int) BaseOption {
+	return func(o *BaseOptions) error {
+		if n <= 0 {
+			return NewOptionApplyError("WithMaxStackDepth", fmt.Errorf("stack depth must be positive, got %d", n))
+		}
+		o.MaxStackDepth = n
+		return nil
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record
+// and every handling error. A nil provider disables metrics.
+// The default value is nil.
+func WithMetricsProvider(p MetricsProvider) BaseOption {
+	return func(o *BaseOptions) error {
+		o.MetricsProvider = p
+		return nil
+	}
+}
+
+// WithOutputSwapHook registers fn to be called whenever SetOutput replaces
+// the output writer, with the previous and the new writer. It runs after the
+// swap has completed, so writes made from then on go to the new writer and
+// fn can safely close the old one, e.g. to release its file descriptor.
+// The default value is nil.
+func WithOutputSwapHook(fn func(old, new io.Writer)) BaseOption {
+	return func(o *BaseOptions) error {
+		o.OutputSwapHook = fn
+		return nil
+	}
+}
+
+// WithFieldValidator registers fn to check every field key, both on records
+// and on attributes added with WithAttrs, to enforce a naming convention at
+// log sites. A key that fn rejects is replaced by a sanitized snake_case form
+// (e.g. "userID" becomes "user_id") and the original key is kept in an extra
+// "_invalid_field_<n>" field, n counting the rejected keys from 1.
+// The record is always logged. The default value is nil.
+func WithFieldValidator(fn func(key string) error) BaseOption {
+	return func(o *BaseOptions) error {
+		o.FieldValidator = fn
+		return nil
+	}
+}
+
+// WithLevelNames overrides the names used when rendering the level field,
+// for systems that expect e.g. "WARNING" or "ERR" instead of the canonical
+// names. Levels missing from names keep their LogLevel.String() form.
+// The map is copied, so later changes by the caller have no effect.
+func WithLevelNames(names map[LogLevel]string) BaseOption {
+	return func(o *BaseOptions) error {
+		for level := range names {
+			if err := ValidateLogLevel(level); err != nil {
+				return NewOptionApplyError("WithLevelNames", err)
+			}
+		}
+		o.LevelNames = maps.Clone(names)
+		return nil
+	}
+}
+
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR without the
+// DEBUG to WARN in between. The minimum level is set to the lowest enabled
+// level, so backends that filter natively let every enabled level through.
+//
+// While the set is in place, SetLevel and WithLevel no longer decide which
+// levels are enabled, but still change the threshold of native filters.
+// Returns error if no level is given or a level is invalid.
+func WithEnabledLevels(levels ...LogLevel) BaseOption {
+	return func(o *BaseOptions) error {
+		if len(levels) == 0 {
+			return NewOptionApplyError("WithEnabledLevels", errors.New("at least one level is required"))
+		}
+		for _, level := range levels {
+			if err := ValidateLogLevel(level); err != nil {
+				return NewOptionApplyError("WithEnabledLevels", err)
+			}
+		}
+		o.EnabledLevels = slices.Clone(levels)
+		return nil
+	}
+}
+
+// WithFailFast makes handler construction fail if the output is not
+// writable, surfacing a misconfigured sink at startup rather than on the
+// first log. The output is probed with an empty write, which reaches the
+// operating system for files but writes no data. Writers that do not check
+// empty writes pass the probe. The default value is false.
+func WithFailFast(enabled bool) BaseOption {
+	return func(o *BaseOptions) error {
+		o.FailFast = enabled
+		return nil
+	}
+}
+
+// WithJSONValues makes text-based handlers render map, slice and array
+// values as compact JSON (e.g. {"a":1}) instead of Go syntax (map[a:1]).
+// Handlers with structured output already nest such values and ignore it.
+// The default value is false.
+func WithJSONValues(enabled bool) BaseOption {
+	return func(o *BaseOptions) error {
+		o.JSONValues = enabled
+		return nil
+	}
+}
+
+// WithHumanMessageTemplate sets a text/template that renders a readable
+// message from each record, for pipelines read by both humans and machines.
+// The template is executed with a map of the record's key-value pairs, in
+// which the original message is available as .msg unless an attribute uses
+// that key:
+//
+//	"{{.user}} logged in from {{.ip}}"
+//
+// Text output shows the rendered message instead of the original one.
+// Structured output keeps the original message and adds the rendered one
+// under the key set with WithHumanMessageKey. Attributes added with
+// WithAttrs are not available to the template.
+// Returns error if tmpl cannot be parsed.
+func WithHumanMessageTemplate(tmpl string) BaseOption {
+	return func(o *BaseOptions) error {
+		t, err := template.New("message").Parse(tmpl)
+		if err != nil {
+			return NewOptionApplyError("WithHumanMessageTemplate", err)
+		}
+		o.HumanMessageTemplate = t
+		return nil
+	}
+}
+
+// WithHumanMessageKey sets the key under which structured output adds the
+// message rendered with WithHumanMessageTemplate.
+// The default value is DefaultHumanMessageKey.
+func WithHumanMessageKey(key string) BaseOption {
+	return func(o *BaseOptions) error {
+		if key == "" {
+			return NewOptionApplyError("WithHumanMessageKey", errors.New("key cannot be empty"))
+		}
+		o.HumanMessageKey = key
+		return nil
+	}
+}
+
+// WithDebugFriendlyDefaults applies a common production preset: records
+// below INFO are dropped, WARN and above carry caller information and ERROR
+// and above carry a stack trace. It is equivalent to
+//
+//	WithLevel(InfoLevel),
+//	WithCaller(true), WithCallerLevel(WarnLevel),
+//	WithTrace(true), WithTraceLevel(ErrorLevel)
+//
+// Options are applied in order, so any of these can be overridden by
+// passing the individual option after this one.
+func WithDebugFriendlyDefaults() BaseOption {
+	return func(o *BaseOptions) error {
+		o.Level = InfoLevel
+		o.WithCaller = true
+		o.CallerLevel = WarnLevel
+		o.callerLevelSet = true
+		o.WithTrace = true
+		o.TraceLevel = ErrorLevel
+		o.traceLevelSet = true
+		return nil
+	}
+}
+
+// WithRedactPattern replaces every match of re in messages and string
+// values with replacement, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString, e.g. to mask card numbers regardless of
+// the attribute key:
+//
+//	handler.WithRedactPattern(regexp.MustCompile(`\b(\d{4})\d{8,11}(\d{4})\b`), "$1****$2")
+//
+// Patterns are applied in the order they were added. Every pattern runs
+// over every message and string value of every record, so keep them few
+// and simple where throughput matters. Values that are not strings are
+// only redacted by handlers rendering them as text (see
+// BaseHandler.FormatValue). Returns error if re is nil.
+func WithRedactPattern(re *regexp.Regexp, replacement string) BaseOption {
+	return func(o *BaseOptions) error {
+		if re == nil {
+			return NewOptionApplyError("WithRedactPattern", errors.New("pattern cannot be nil"))
+		}
+		o.RedactPatterns = append(o.RedactPatterns, RedactPattern{Regexp: re, Replacement: replacement})
+		return nil
+	}
+}
+
+// WithMaxLineBytes caps every rendered record at n bytes, for sinks that
+// reject longer lines. JSON records keep as many leading fields as fit
+// followed by "_truncated":true, so they stay valid JSON; text records are
+// cut and end with _truncated=true. See TruncateLine.
+// It is a safety net: the cap applies to each write to the output, which
+// handlers make once per record. Returns error if n is not positive.
+func WithMaxLineBytes(n int) BaseOption {
+	return func(o *BaseOptions) error {
+		if n <= 0 {
+			return NewOptionApplyError("WithMaxLineBytes", errors.New("max line bytes must be positive"))
+		}
+		o.MaxLineBytes = n
+		return nil
+	}
+}
+
+// WithKeyCase normalizes attribute keys to the given case, so that a field
+// has the same key however the call site spelled it, e.g. "request_id" for
+// both "requestID" and "request-id" with KeyCaseSnake. The backends convert
+// group names as well. The default value is KeyCaseAsIs.
+// Returns error if the key case is unknown.
+func WithKeyCase(c KeyCase) BaseOption {
+	return func(o *BaseOptions) error {
+		if !c.isValid() {
+			return NewOptionApplyError("WithKeyCase", fmt.Errorf("unknown key case %d", c))
+		}
+		o.KeyCase = c
+		return nil
+	}
+}
+
+// WithIndependentOutput makes handlers derived with the With* builders (see
+// Clone) get their own AtomicWriter around the same underlying writer, so
+// that SetOutput on a derived handler changes the output of that handler
+// and the ones derived from it only. The default value is false: derived
+// handlers share the output, and SetOutput on any of them affects all.
+func WithIndependentOutput(enabled bool) BaseOption {
+	return func(o *BaseOptions) error {
+		o.IndependentOutput = enabled
+		return nil
+	}
+}
+
+// ApplyOptions applies opts to target in order and stops at the first error.
+// Nil options are skipped.
+func ApplyOptions(opts []BaseOption, target *BaseOptions) error {
+	for err := range applyAll(opts, target) {
+		return err
+	}
+
+	return nil
+}
+
+// MustApplyOptions is like ApplyOptions but panics if any option fails.
+// It is intended for package-level initialization (e.g., in init functions)
+// where a misconfiguration is a programming error.
+func MustApplyOptions(opts []BaseOption, target *BaseOptions) {
+	if err := ApplyOptions(opts, target); err != nil {
+		panic(err)
+	}
+}
+
+// ValidateOptions performs a dry run of opts against a copy of target and
+// returns all errors joined together, not just the first one.
+// The target itself is never modified.
+func ValidateOptions(opts []BaseOption, target *BaseOptions) error {
+	dryRun := target.clone()
+
+	var errs []error
+	for err := range applyAll(opts, &dryRun) {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// clone returns a copy of o that shares no slices or maps with it, so options
+// applied to the copy, such as WithRedactPattern, never write to o.
+func (o *BaseOptions) clone() BaseOptions {
+	c := *o
+	c.ValidFormats = slices.Clone(o.ValidFormats)
+	c.RedactPatterns = slices.Clone(o.RedactPatterns)
+	c.LevelNames = maps.Clone(o.LevelNames)
+	c.EnabledLevels = slices.Clone(o.EnabledLevels)
+
+	return c
+}
+
+// applyAll returns an iterator that applies each option to target in order
+// and yields every error encountered. Iteration stops when the consumer stops.
+func applyAll(opts []BaseOption, target *BaseOptions) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		for _, opt := range opts {
+			if opt == nil {
+				continue
+			}
+			if err := opt(target); err != nil && !yield(err) {
+				return
+			}
+		}
+	}
+}
+
 // BaseHandler provides shared functionality for handler implementations.
 // Handlers that embed BaseHandler can use its optional helpers or ignore them
 // in favor of their own optimized implementations.
@@ -162,12 +596,41 @@ type BaseHandler struct {
 	flags      atomic.Uint32 // StateFlag bitmask (lock-free)
 	level      atomic.Int32  // LogLevel (lock-free for Enabled())
 	out        *atomicwriter.AtomicWriter
+	outState   *outputState // Shared with every instance sharing out
+	ownOutput  bool         // Immutable after initialization
 	callerSkip int
 	format     string
 	keyPrefix  string
 	separator  string
+
+	maxStackDepth int                 // Immutable after initialization
+	traceLevel    LogLevel            // Immutable after initialization
+	metrics       MetricsProvider     // Immutable after initialization, may be nil
+	levelNames    map[LogLevel]string // Immutable after initialization, may be nil
+	validateKey   func(string) error  // Immutable after initialization, may be nil
+	jsonValues    bool                // Immutable after initialization
+	humanTmpl     *template.Template  // Immutable after initialization, may be nil
+	humanKey      string              // Immutable after initialization
+	shortCaller   bool                // Immutable after initialization
+	redact        []RedactPattern     // Immutable after initialization, may be nil
+	maxLineBytes  int                 // Immutable after initialization
+	callerLevel   LogLevel            // Immutable after initialization
+	keyCase       KeyCase             // Immutable after initialization
+
+	enabled atomic.Pointer[levelSet] // Overrides level if non-nil (lock-free for Enabled())
 }
 
+// outputState tracks the writer behind an AtomicWriter, which does not
+// expose it, so that SetOutput can report the replaced writer.
+type outputState struct {
+	mu      sync.Mutex // Serializes swaps so each hook sees the right old writer
+	current io.Writer
+	hook    func(old, new io.Writer) // Immutable after initialization, may be nil
+}
+
+// levelSet holds the levels enabled with WithEnabledLevels, indexed from MinLevel.
+type levelSet [MaxLevel - MinLevel + 1]bool
+
 // maxKeyPrefixLength is the maximum total length of accumulated key prefixes.
 // Prevents pathological cases with deep nesting or long key names.
 // 10,000 characters should handle reasonable nesting (e.g., 100 levels * 100 chars each).
@@ -193,7 +656,13 @@ func NewBaseHandler(opts *BaseOptions) (*BaseHandler, error) {
 		}
 	}
 
-	aw, err := atomicwriter.NewAtomicWriter(opts.Output)
+	if opts.FailFast {
+		if _, err := opts.Output.Write(nil); err != nil {
+			return nil, NewOutputProbeError(err)
+		}
+	}
+
+	aw, err := atomicwriter.NewAtomicWriter(capLines(opts.Output, opts.MaxLineBytes))
 	if err != nil {
 		return nil, NewAtomicWriterError(err)
 	}
@@ -203,14 +672,61 @@ func NewBaseHandler(opts *BaseOptions) (*BaseHandler, error) {
 		separator = DefaultKeySeparator
 	}
 
+	humanKey := opts.HumanMessageKey
+	if humanKey == "" {
+		humanKey = DefaultHumanMessageKey
+	}
+
+	maxStackDepth := opts.MaxStackDepth
+	if maxStackDepth <= 0 {
+		maxStackDepth = DefaultMaxStackDepth
+	}
+
+	traceLevel := opts.TraceLevel
+	if traceLevel == DebugLevel && !opts.traceLevelSet {
+		traceLevel = DefaultTraceLevel
+	}
+
+	callerLevel := opts.CallerLevel
+	if callerLevel == DebugLevel && !opts.callerLevelSet {
+		callerLevel = MinLevel
+	}
+
 	h := &BaseHandler{
-		out:        aw,
-		format:     opts.Format,
-		callerSkip: opts.CallerSkip,
-		separator:  separator,
+		out:           aw,
+		outState:      &outputState{current: opts.Output, hook: opts.OutputSwapHook},
+		format:        opts.Format,
+		callerSkip:    opts.CallerSkip,
+		traceLevel:    traceLevel,
+		separator:     separator,
+		maxStackDepth: maxStackDepth,
+		metrics:       opts.MetricsProvider,
+		levelNames:    maps.Clone(opts.LevelNames),
+		validateKey:   opts.FieldValidator,
+		jsonValues:    opts.JSONValues,
+		humanTmpl:     opts.HumanMessageTemplate,
+		humanKey:      humanKey,
+		shortCaller:   opts.CallerShortPath,
+		redact:        slices.Clone(opts.RedactPatterns),
+		maxLineBytes:  opts.MaxLineBytes,
+		ownOutput:     opts.IndependentOutput,
+		callerLevel:   callerLevel,
+		keyCase:       opts.KeyCase,
 	}
 	h.level.Store(int32(opts.Level))
 
+	if len(opts.EnabledLevels) > 0 {
+		var set levelSet
+		for _, level := range opts.EnabledLevels {
+			if err := ValidateLogLevel(level); err != nil {
+				return nil, err
+			}
+			set[level-MinLevel] = true
+		}
+		h.enabled.Store(&set)
+		h.level.Store(int32(slices.Min(opts.EnabledLevels)))
+	}
+
 	// Initialize flags
 	var flags uint32
 	if opts.WithCaller {
@@ -224,13 +740,45 @@ func NewBaseHandler(opts *BaseOptions) (*BaseHandler, error) {
 	return h, nil
 }
 
+// NewBaseHandlerFromOptions applies opts on top of a copy of defaults and
+// initializes a new BaseHandler from the result, so defaults can be shared
+// by several handlers. It stops at the first failing option.
+// If defaults is nil, an empty BaseOptions with DefaultLevel and
+// DefaultTraceLevel is used.
+func NewBaseHandlerFromOptions(defaults *BaseOptions, opts ...BaseOption) (*BaseHandler, error) {
+	o := BaseOptions{Level: DefaultLevel, TraceLevel: DefaultTraceLevel, CallerLevel: MinLevel}
+	if defaults != nil {
+		o = defaults.clone()
+	}
+
+	if err := ApplyOptions(opts, &o); err != nil {
+		return nil, err
+	}
+
+	return NewBaseHandler(&o)
+}
+
 // --- Thread-Safe State Access ---
 
-// Enabled reports whether the handler processes records at the given level.
+// Enabled reports whether the handler processes records at the given level:
+// whether level is one of the levels set with WithEnabledLevels if any, or
+// whether it is at or above the minimum level otherwise.
 func (h *BaseHandler) Enabled(level LogLevel) bool {
+	if set := h.enabled.Load(); set != nil {
+		return IsValidLogLevel(level) && set[level-MinLevel]
+	}
+
 	return level >= LogLevel(h.level.Load())
 }
 
+// EnabledContext is Enabled for a record logged with ctx: if ctx carries a
+// level set with WithContextLevel, it reports whether level is at or above
+// it instead. Backends check records with it in Handle, so that a lower
+// context level also reaches them.
+func (h *BaseHandler) EnabledContext(ctx context.Context, level LogLevel) bool {
+	return enabledContext(ctx, h, level)
+}
+
 // Level returns the current minimum log level.
 func (h *BaseHandler) Level() LogLevel {
 	return LogLevel(h.level.Load())
@@ -249,11 +797,42 @@ func (h *BaseHandler) CallerEnabled() bool {
 	return h.HasFlag(FlagCaller)
 }
 
-// TraceEnabled returns whether stack traces should be included for error-level logs.
+// CallerPath returns file as it should be reported in the caller field:
+// trimmed with ShortCallerPath if WithCallerShortPath is enabled, or
+// unchanged otherwise.
+func (h *BaseHandler) CallerPath(file string) string {
+	if h.shortCaller {
+		return ShortCallerPath(file)
+	}
+
+	return file
+}
+
+// CallerShortPath returns whether caller files are trimmed with ShortCallerPath.
+// Handlers with native caller reporting use it to select the backend's
+// own short path mode.
+func (h *BaseHandler) CallerShortPath() bool {
+	return h.shortCaller
+}
+
+// CallerLevel returns the minimum level that carries caller information
+// when caller reporting is enabled.
+func (h *BaseHandler) CallerLevel() LogLevel {
+	return h.callerLevel
+}
+
+// TraceEnabled returns whether stack traces should be included for records
+// at or above TraceLevel.
 func (h *BaseHandler) TraceEnabled() bool {
 	return h.HasFlag(FlagTrace)
 }
 
+// TraceLevel returns the minimum level that carries a stack trace when
+// tracing is enabled.
+func (h *BaseHandler) TraceLevel() LogLevel {
+	return h.traceLevel
+}
+
 // CallerSkip returns the number of stack frames to skip for caller reporting.
 // Handlers should add their internal skip constant to this value.
 //
@@ -283,12 +862,298 @@ func (h *BaseHandler) Separator() string {
 	return h.separator
 }
 
+// MaxStackDepth returns the maximum number of frames in captured stack traces.
+// Handlers pass it to CaptureStack when trace logging is enabled.
+func (h *BaseHandler) MaxStackDepth() int {
+	return h.maxStackDepth
+}
+
+// LevelName returns the rendered name of level, honoring any override set
+// with WithLevelNames. Handlers that format the level field themselves
+// should use it instead of calling level.String() directly.
+func (h *BaseHandler) LevelName(level LogLevel) string {
+	if name, ok := h.levelNames[level]; ok {
+		return name
+	}
+
+	return level.String()
+}
+
+// FormatValue renders v for text output: with FormatJSONValue if enabled
+// with WithJSONValues, and with the package-level FormatValue otherwise.
+func (h *BaseHandler) FormatValue(v any) string {
+	if h.jsonValues {
+		return h.RedactString(FormatJSONValue(v))
+	}
+
+	return h.RedactString(FormatValue(v))
+}
+
+// RedactString applies the patterns set with WithRedactPattern to s.
+// Handlers call it on the record message.
+func (h *BaseHandler) RedactString(s string) string {
+	for _, p := range h.redact {
+		s = p.Regexp.ReplaceAllString(s, p.Replacement)
+	}
+
+	return s
+}
+
+// RedactValues applies the patterns set with WithRedactPattern to the string
+// values of keyValues. It returns keyValues itself if nothing was redacted,
+// or a copy otherwise. Handlers passing values to a backend natively, rather
+// than rendering them with FormatValue, call it on record and WithAttrs
+// key-values.
+func (h *BaseHandler) RedactValues(keyValues []any) []any {
+	if len(h.redact) == 0 {
+		return keyValues
+	}
+
+	var out []any
+	for i := 1; i < len(keyValues); i += 2 {
+		s, ok := keyValues[i].(string)
+		if !ok {
+			continue
+		}
+		if r := h.RedactString(s); r != s {
+			if out == nil {
+				out = slices.Clone(keyValues)
+			}
+			out[i] = r
+		}
+	}
+	if out == nil {
+		return keyValues
+	}
+
+	return out
+}
+
+// HumanMessage renders the template set with WithHumanMessageTemplate for
+// a record with message msg and attributes keyValues. It reports false if no
+// template is set. If the template fails, msg is returned instead. Either is
+// redacted with RedactString.
+func (h *BaseHandler) HumanMessage(msg string, keyValues []any) (string, bool) {
+	if h.humanTmpl == nil {
+		return "", false
+	}
+
+	data := make(map[string]any, len(keyValues)/2+1)
+	data["msg"] = msg
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyValues[i])
+		}
+		data[key] = keyValues[i+1]
+	}
+
+	var sb strings.Builder
+	if err := h.humanTmpl.Execute(&sb, data); err != nil {
+		return h.RedactString(msg), true
+	}
+
+	return h.RedactString(sb.String()), true
+}
+
+// HumanMessageKey returns the key under which structured output adds the
+// message rendered by HumanMessage.
+func (h *BaseHandler) HumanMessageKey() string {
+	return h.humanKey
+}
+
+// ValidateKeys converts the keys of keyValues to the case set with
+// WithKeyCase, then applies the field validator set with WithFieldValidator
+// to them. It returns keyValues itself if every key is in the key case and
+// valid; otherwise it returns a copy with the keys converted, the rejected
+// keys sanitized and their originals appended as "_invalid_field_<n>" fields.
+// Handlers call it on record key-values and in WithAttrs before formatting.
+func (h *BaseHandler) ValidateKeys(keyValues []any) []any {
+	keyValues = h.convertKeys(keyValues)
+	if h.validateKey == nil {
+		return keyValues
+	}
+
+	var out []any
+	invalid := 0
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyValues[i])
+		}
+		if h.validateKey(key) == nil {
+			continue
+		}
+
+		if out == nil {
+			out = slices.Clone(keyValues[:len(keyValues)&^1])
+		}
+		invalid++
+		out[i] = sanitizeKey(key)
+		out = append(out, invalidFieldKeyPrefix+strconv.Itoa(invalid), key)
+	}
+
+	if out == nil {
+		return keyValues
+	}
+
+	return out
+}
+
+// ConvertKey returns key in the key case set with WithKeyCase.
+// Backends pass group names through it.
+func (h *BaseHandler) ConvertKey(key string) string {
+	return h.keyCase.Convert(key)
+}
+
+// convertKeys returns keyValues with its string keys in the handler's key
+// case, copying it only if a key changes.
+func (h *BaseHandler) convertKeys(keyValues []any) []any {
+	if h.keyCase == KeyCaseAsIs {
+		return keyValues
+	}
+
+	var out []any
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok {
+			continue
+		}
+		if converted := h.keyCase.Convert(key); converted != key {
+			if out == nil {
+				out = slices.Clone(keyValues)
+			}
+			out[i] = converted
+		}
+	}
+
+	if out == nil {
+		return keyValues
+	}
+
+	return out
+}
+
+// invalidFieldKeyPrefix prefixes the fields that keep the original of a key
+// rejected by the field validator.
+const invalidFieldKeyPrefix = "_invalid_field_"
+
+// sanitizeKey converts key to snake_case, dropping every character other
+// than ASCII letters, digits and underscores. It returns "_invalid" if
+// nothing is left.
+func sanitizeKey(key string) string {
+	var sb strings.Builder
+	sb.Grow(len(key) + 4)
+
+	var prev byte
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			// Start a new word on a case change ("userId") or at the end of
+			// an acronym ("HTTPServer" -> "http_server")
+			lowerNext := i+1 < len(key) && key[i+1] >= 'a' && key[i+1] <= 'z'
+			upperPrev := prev >= 'A' && prev <= 'Z'
+			if sb.Len() > 0 && prev != '_' && (!upperPrev || lowerNext) {
+				sb.WriteByte('_')
+			}
+			sb.WriteByte(c + 'a' - 'A')
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_':
+			sb.WriteByte(c)
+		default:
+			continue
+		}
+		prev = c
+	}
+
+	if sb.Len() == 0 {
+		return "_invalid"
+	}
+
+	return sb.String()
+}
+
+// RecordHandled notifies the configured MetricsProvider that a record at the
+// given level was handled. It is a no-op if no provider is configured.
+// Handlers call it after the backend accepted the record.
+func (h *BaseHandler) RecordHandled(level LogLevel) {
+	if h.metrics != nil {
+		h.metrics.RecordHandled(level)
+	}
+}
+
+// RecordError notifies the configured MetricsProvider that handling a record
+// failed. It is a no-op if no provider is configured.
+func (h *BaseHandler) RecordError() {
+	if h.metrics != nil {
+		h.metrics.RecordError()
+	}
+}
+
+// WriteRaw writes payload to the output as is if level is enabled, as
+// required by RawWriter. Handlers implement RawWriter by delegating to it.
+func (h *BaseHandler) WriteRaw(level LogLevel, payload []byte) error {
+	if !h.Enabled(level) {
+		return nil
+	}
+
+	if _, err := h.out.Write(payload); err != nil {
+		h.RecordError()
+		return err
+	}
+	h.RecordHandled(level)
+
+	return nil
+}
+
 // AtomicWriter returns the underlying atomic writer.
 // Handlers use this to get the thread-safe writer for backend initialization.
 func (h *BaseHandler) AtomicWriter() *atomicwriter.AtomicWriter {
 	return h.out
 }
 
+// ErrorRecordingWriter returns the atomic writer wrapped to notify the
+// configured MetricsProvider of every failed write with RecordError. Backends
+// whose logging calls do not return write errors use it as their output so
+// that the errors are still counted.
+func (h *BaseHandler) ErrorRecordingWriter() io.Writer {
+	if h.metrics == nil {
+		return h.out
+	}
+
+	return &errorRecordingWriter{out: h.out, h: h}
+}
+
+// errorRecordingWriter reports failed writes to out with RecordError.
+type errorRecordingWriter struct {
+	out *atomicwriter.AtomicWriter
+	h   *BaseHandler
+}
+
+// Write writes p to the output, recording an error if it fails.
+func (w *errorRecordingWriter) Write(p []byte) (int, error) {
+	n, err := w.out.Write(p)
+	if err != nil {
+		w.h.RecordError()
+	}
+
+	return n, err
+}
+
+// Sync flushes the output if it supports it.
+func (w *errorRecordingWriter) Sync() error {
+	return w.out.Sync()
+}
+
+// Output returns the writer the handler currently writes to, as last set
+// with the Output option, SetOutput or WithOutput.
+func (h *BaseHandler) Output() io.Writer {
+	h.outState.mu.Lock()
+	defer h.outState.mu.Unlock()
+
+	return h.outState.current
+}
+
 // --- Flag Management (Lock-Free) ---
 
 // HasFlag checks if flag is set (lock-free).
@@ -329,14 +1194,25 @@ func (h *BaseHandler) SetLevel(level LogLevel) error {
 
 // SetOutput changes the destination for log output.
 // Affects all instances sharing this base.
+// If an output swap hook is configured, it is called after the swap.
 func (h *BaseHandler) SetOutput(w io.Writer) error {
 	if w == nil {
 		return ErrNilWriter
 	}
 
-	if err := h.out.Swap(w); err != nil {
+	st := h.outState
+	st.mu.Lock()
+	old := st.current
+	if err := h.out.Swap(capLines(w, h.maxLineBytes)); err != nil {
+		st.mu.Unlock()
 		return NewAtomicWriterError(err)
 	}
+	st.current = w
+	st.mu.Unlock()
+
+	if st.hook != nil {
+		st.hook(old, w)
+	}
 
 	return nil
 }
@@ -355,29 +1231,86 @@ func (h *BaseHandler) SetCallerSkip(skip int) error {
 	return nil
 }
 
+// SetCallerSkipDelta adjusts the caller skip value by delta in a single
+// atomic read-modify-write. If the new skip would be negative, it returns
+// ErrInvalidSourceSkip and leaves the value unchanged.
+// Affects all instances sharing this base.
+func (h *BaseHandler) SetCallerSkipDelta(delta int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	skip := h.callerSkip + delta
+	if skip < 0 {
+		return ErrInvalidSourceSkip
+	}
+	h.callerSkip = skip
+
+	return nil
+}
+
 // --- Immutable Builders (Return New Instances) ---
 
 // Clone returns a shallow copy of BaseHandler with independent mutex.
 // The new instance shares the AtomicWriter but has separate state locks.
-// This means SetOutput() on the clone affects the original's output destination.
+// This means SetOutput() on the clone affects the original's output destination,
+// unless the handler was created with WithIndependentOutput(true), in which case
+// the clone gets its own AtomicWriter wrapping the current underlying writer.
 // For fully independent handlers, create separate handler instances with different writers.
 func (h *BaseHandler) Clone() *BaseHandler {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	out, outState := h.out, h.outState // Shared writer - SetOutput() affects original
+	if h.ownOutput {
+		out, outState = h.ownWriter()
+	}
+
 	clone := &BaseHandler{
-		out:        h.out, // Shared writer - SetOutput() affects original
-		format:     h.format,
-		callerSkip: h.callerSkip,
-		keyPrefix:  h.keyPrefix,
-		separator:  h.separator,
+		out:           out,
+		outState:      outState,
+		ownOutput:     h.ownOutput,
+		format:        h.format,
+		callerSkip:    h.callerSkip,
+		traceLevel:    h.traceLevel,
+		keyPrefix:     h.keyPrefix,
+		separator:     h.separator,
+		maxStackDepth: h.maxStackDepth,
+		metrics:       h.metrics,
+		levelNames:    h.levelNames,
+		validateKey:   h.validateKey,
+		jsonValues:    h.jsonValues,
+		humanTmpl:     h.humanTmpl,
+		humanKey:      h.humanKey,
+		shortCaller:   h.shortCaller,
+		redact:        h.redact,
+		maxLineBytes:  h.maxLineBytes,
+		callerLevel:   h.callerLevel,
+		keyCase:       h.keyCase,
 	}
 	clone.level.Store(h.level.Load())
+	clone.enabled.Store(h.enabled.Load())
 	clone.flags.Store(h.flags.Load())
 
 	return clone
 }
 
+// ownWriter returns a new AtomicWriter and output state around the writer
+// h currently writes to, keeping the output swap hook.
+func (h *BaseHandler) ownWriter() (*atomicwriter.AtomicWriter, *outputState) {
+	st := h.outState
+	st.mu.Lock()
+	current := st.current
+	st.mu.Unlock()
+
+	aw, err := atomicwriter.NewAtomicWriter(capLines(current, h.maxLineBytes))
+	if err != nil {
+		// Unreachable: current is never nil. Keep sharing rather than fail.
+		return h.out, h.outState
+	}
+
+	return aw, &outputState{current: current, hook: st.hook}
+}
+
 // WithLevel returns a shallow copy of BaseHandler with level set.
 // If the level is already set, returns the original instance.
 func (h *BaseHandler) WithLevel(level LogLevel) (*BaseHandler, error) {
@@ -499,13 +1432,14 @@ func (h *BaseHandler) WithOutput(w io.Writer) (*BaseHandler, error) {
 		return nil, ErrNilWriter
 	}
 
-	aw, err := atomicwriter.NewAtomicWriter(w)
+	aw, err := atomicwriter.NewAtomicWriter(capLines(w, h.maxLineBytes))
 	if err != nil {
 		return nil, NewAtomicWriterError(err)
 	}
 
 	clone := h.Clone()
 	clone.out = aw
+	clone.outState = &outputState{current: w, hook: h.outState.hook}
 
 	return clone, nil
 }
@@ -2,8 +2,14 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -367,6 +373,628 @@ func TestBaseOption_WithTrace(t *testing.T) {
 	})
 }
 
+func TestBaseOption_WithMaxStackDepth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{}
+		if err := handler.WithMaxStackDepth(8)(opts); err != nil {
+			t.Fatalf("WithMaxStackDepth(8) error = %v, want nil", err)
+		}
+		if opts.MaxStackDepth != 8 {
+			t.Errorf("MaxStackDepth = %d, want 8", opts.MaxStackDepth)
+		}
+	})
+
+	for _, n := range []int{0, -1} {
+		t.Run(fmt.Sprintf("invalid %d", n), func(t *testing.T) {
+			t.Parallel()
+			err := handler.WithMaxStackDepth(n)(&handler.BaseOptions{})
+			if !errors.Is(err, handler.ErrOptionApplyFailed) {
+				t.Errorf("WithMaxStackDepth(%d) error = %v, want %v", n, err, handler.ErrOptionApplyFailed)
+			}
+		})
+	}
+}
+
+func TestBaseHandler_MaxStackDepth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Output: io.Discard})
+		if got := h.MaxStackDepth(); got != handler.DefaultMaxStackDepth {
+			t.Errorf("MaxStackDepth() = %d, want %d", got, handler.DefaultMaxStackDepth)
+		}
+	})
+
+	t.Run("configured and cloned", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Output: io.Discard, MaxStackDepth: 5})
+		if got := h.MaxStackDepth(); got != 5 {
+			t.Errorf("MaxStackDepth() = %d, want 5", got)
+		}
+		if got := h.Clone().MaxStackDepth(); got != 5 {
+			t.Errorf("Clone().MaxStackDepth() = %d, want 5", got)
+		}
+	})
+}
+
+// countingMetrics is a MetricsProvider that counts notifications.
+type countingMetrics struct {
+	handled map[handler.LogLevel]int
+	errors  int
+}
+
+func (m *countingMetrics) RecordHandled(level handler.LogLevel) { m.handled[level]++ }
+func (m *countingMetrics) RecordError()                         { m.errors++ }
+
+func TestBaseHandler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no provider", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Output: io.Discard})
+		// Must not panic
+		h.RecordHandled(handler.InfoLevel)
+		h.RecordError()
+	})
+
+	t.Run("forwards to provider and clones", func(t *testing.T) {
+		t.Parallel()
+		m := &countingMetrics{handled: make(map[handler.LogLevel]int)}
+		opts := &handler.BaseOptions{Output: io.Discard}
+		if err := handler.WithMetricsProvider(m)(opts); err != nil {
+			t.Fatalf("WithMetricsProvider() error = %v", err)
+		}
+		h := newHandler(t, opts)
+
+		h.RecordHandled(handler.InfoLevel)
+		h.Clone().RecordHandled(handler.ErrorLevel)
+		h.RecordError()
+
+		if m.handled[handler.InfoLevel] != 1 || m.handled[handler.ErrorLevel] != 1 {
+			t.Errorf("handled = %v, want one Info and one Error", m.handled)
+		}
+		if m.errors != 1 {
+			t.Errorf("errors = %d, want 1", m.errors)
+		}
+	})
+
+	t.Run("error recording writer", func(t *testing.T) {
+		t.Parallel()
+		m := &countingMetrics{handled: make(map[handler.LogLevel]int)}
+		opts := &handler.BaseOptions{Output: failingWriter{}}
+		if err := handler.WithMetricsProvider(m)(opts); err != nil {
+			t.Fatalf("WithMetricsProvider() error = %v", err)
+		}
+		h := newHandler(t, opts)
+
+		if _, err := h.ErrorRecordingWriter().Write([]byte("record\n")); err == nil {
+			t.Error("Write() error = nil, want the output's error")
+		}
+		if m.errors != 1 {
+			t.Errorf("errors = %d, want 1", m.errors)
+		}
+	})
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestBaseHandler_ValidateKeys(t *testing.T) {
+	t.Parallel()
+
+	snakeCase := regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+	opts := &handler.BaseOptions{Output: io.Discard}
+	if err := handler.WithFieldValidator(func(key string) error {
+		if !snakeCase.MatchString(key) {
+			return fmt.Errorf("key %q is not snake_case", key)
+		}
+		return nil
+	})(opts); err != nil {
+		t.Fatalf("WithFieldValidator() error = %v", err)
+	}
+	h := newHandler(t, opts)
+
+	tests := []struct {
+		name string
+		in   []any
+		want []any
+	}{
+		{"all valid", []any{"user_id", 1, "status", "ok"}, []any{"user_id", 1, "status", "ok"}},
+		{"camelCase", []any{"userId", 1, "ok", true}, []any{"user_id", 1, "ok", true, "_invalid_field_1", "userId"}},
+		{"acronyms", []any{"HTTPServer", 1, "requestID", 2}, []any{"http_server", 1, "request_id", 2, "_invalid_field_1", "HTTPServer", "_invalid_field_2", "requestID"}},
+		{"illegal characters", []any{"user.name", "x", "-", "y"}, []any{"username", "x", "_invalid", "y", "_invalid_field_1", "user.name", "_invalid_field_2", "-"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			in := slices.Clone(tt.in)
+			got := h.ValidateKeys(in)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateKeys(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+			if !slices.Equal(in, tt.in) {
+				t.Errorf("ValidateKeys() modified its input: %v", in)
+			}
+		})
+	}
+
+	t.Run("no validator", func(t *testing.T) {
+		t.Parallel()
+		plain := newHandler(t, &handler.BaseOptions{Output: io.Discard})
+		in := []any{"userId", 1}
+		if got := plain.ValidateKeys(in); &got[0] != &in[0] {
+			t.Error("ValidateKeys() without validator should return its input")
+		}
+	})
+}
+
+func TestBaseHandler_CallerPath(t *testing.T) {
+	t.Parallel()
+
+	const file = "/home/ci/src/app/server/http.go"
+
+	tests := []struct {
+		name  string
+		short bool
+		want  string
+	}{
+		{"full path by default", false, file},
+		{"short path", true, "server/http.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := handler.NewBaseHandlerFromOptions(nil,
+				handler.WithOutput(io.Discard),
+				handler.WithCallerShortPath(tt.short),
+			)
+			if err != nil {
+				t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+			}
+			if got := h.CallerShortPath(); got != tt.short {
+				t.Errorf("CallerShortPath() = %v, want %v", got, tt.short)
+			}
+			if got := h.CallerPath(file); got != tt.want {
+				t.Errorf("CallerPath() = %q, want %q", got, tt.want)
+			}
+			if got := h.WithCaller(true).CallerPath(file); got != tt.want {
+				t.Errorf("WithCaller(true).CallerPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseHandler_Redact(t *testing.T) {
+	t.Parallel()
+
+	card := regexp.MustCompile(`\b(\d{4})\d{8}(\d{4})\b`)
+	ssn := regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+
+	if err := handler.WithRedactPattern(nil, "x")(&handler.BaseOptions{}); err == nil {
+		t.Error("WithRedactPattern(nil) error = nil, want error")
+	}
+
+	h, err := handler.NewBaseHandlerFromOptions(nil,
+		handler.WithOutput(io.Discard),
+		handler.WithRedactPattern(card, "$1****$2"),
+		handler.WithRedactPattern(ssn, "[SSN]"),
+		handler.WithHumanMessageTemplate("paid with {{.card}}"),
+	)
+	if err != nil {
+		t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+	}
+
+	if got, want := h.RedactString("card 4111111111111111, ssn 123-45-6789"), "card 4111****1111, ssn [SSN]"; got != want {
+		t.Errorf("RedactString() = %q, want %q", got, want)
+	}
+	if got, want := h.FormatValue([]string{"123-45-6789"}), "[[SSN]]"; got != want {
+		t.Errorf("FormatValue() = %q, want %q", got, want)
+	}
+	if got, _ := h.HumanMessage("msg", []any{"card", "4111111111111111"}); got != "paid with 4111****1111" {
+		t.Errorf("HumanMessage() = %q, want redacted card", got)
+	}
+
+	kv := []any{"id", 42, "ssn", "123-45-6789", "name", "jane"}
+	got := h.RedactValues(kv)
+	if want := []any{"id", 42, "ssn", "[SSN]", "name", "jane"}; !slices.Equal(got, want) {
+		t.Errorf("RedactValues() = %v, want %v", got, want)
+	}
+	if kv[3] != "123-45-6789" {
+		t.Error("RedactValues() modified its argument")
+	}
+
+	clean := []any{"name", "jane"}
+	if got := h.RedactValues(clean); &got[0] != &clean[0] {
+		t.Error("RedactValues() copied key-values with nothing to redact")
+	}
+	if got := h.WithCaller(true).RedactString("123-45-6789"); got != "[SSN]" {
+		t.Errorf("cloned RedactString() = %q, want [SSN]", got)
+	}
+}
+
+func TestBaseHandler_TraceLevel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default from options constructor", func(t *testing.T) {
+		t.Parallel()
+		h, err := handler.NewBaseHandlerFromOptions(nil, handler.WithOutput(io.Discard))
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+		}
+		if got := h.TraceLevel(); got != handler.DefaultTraceLevel {
+			t.Errorf("TraceLevel() = %v, want %v", got, handler.DefaultTraceLevel)
+		}
+	})
+
+	t.Run("default from options literal", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Level: handler.InfoLevel, Output: io.Discard})
+		if got := h.TraceLevel(); got != handler.ErrorLevel {
+			t.Errorf("TraceLevel() = %v, want %v", got, handler.ErrorLevel)
+		}
+	})
+
+	t.Run("debug level set with option", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{Output: io.Discard}
+		if err := handler.WithTraceLevel(handler.DebugLevel)(opts); err != nil {
+			t.Fatalf("WithTraceLevel() error = %v", err)
+		}
+		if got := newHandler(t, opts).TraceLevel(); got != handler.DebugLevel {
+			t.Errorf("TraceLevel() = %v, want %v", got, handler.DebugLevel)
+		}
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		t.Parallel()
+		if err := handler.WithTraceLevel(handler.MaxLevel + 1)(&handler.BaseOptions{}); err == nil {
+			t.Error("WithTraceLevel() error = nil, want error for invalid level")
+		}
+	})
+
+	t.Run("set and cloned", func(t *testing.T) {
+		t.Parallel()
+		h, err := handler.NewBaseHandlerFromOptions(nil,
+			handler.WithOutput(io.Discard),
+			handler.WithTraceLevel(handler.PanicLevel),
+		)
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+		}
+		if got := h.TraceLevel(); got != handler.PanicLevel {
+			t.Errorf("TraceLevel() = %v, want %v", got, handler.PanicLevel)
+		}
+		if got := h.WithTrace(true).TraceLevel(); got != handler.PanicLevel {
+			t.Errorf("WithTrace(true).TraceLevel() = %v, want %v", got, handler.PanicLevel)
+		}
+	})
+}
+
+func TestBaseHandler_LevelName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid level", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{}
+		if err := handler.WithLevelNames(map[handler.LogLevel]string{handler.MaxLevel + 1: "X"})(opts); err == nil {
+			t.Error("WithLevelNames() error = nil, want error for invalid level")
+		}
+	})
+
+	t.Run("overrides and falls back", func(t *testing.T) {
+		t.Parallel()
+		names := map[handler.LogLevel]string{handler.WarnLevel: "WARNING", handler.ErrorLevel: "ERR"}
+		opts := &handler.BaseOptions{Output: io.Discard}
+		if err := handler.WithLevelNames(names)(opts); err != nil {
+			t.Fatalf("WithLevelNames() error = %v", err)
+		}
+		names[handler.InfoLevel] = "changed" // Must not leak into the handler
+		h := newHandler(t, opts)
+
+		tests := []struct {
+			level handler.LogLevel
+			want  string
+		}{
+			{handler.WarnLevel, "WARNING"},
+			{handler.ErrorLevel, "ERR"},
+			{handler.InfoLevel, "INFO"},
+		}
+		for _, tt := range tests {
+			if got := h.LevelName(tt.level); got != tt.want {
+				t.Errorf("LevelName(%v) = %q, want %q", tt.level, got, tt.want)
+			}
+			if got := h.Clone().LevelName(tt.level); got != tt.want {
+				t.Errorf("Clone().LevelName(%v) = %q, want %q", tt.level, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestBaseHandler_HumanMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{}
+		if err := handler.WithHumanMessageTemplate("{{.user")(opts); err == nil {
+			t.Error("WithHumanMessageTemplate() error = nil, want parse error")
+		}
+		if err := handler.WithHumanMessageKey("")(opts); err == nil {
+			t.Error("WithHumanMessageKey(\"\") error = nil, want error")
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Output: io.Discard})
+		if _, ok := h.HumanMessage("msg", nil); ok {
+			t.Error("HumanMessage() reported a template without one set")
+		}
+		if got := h.HumanMessageKey(); got != handler.DefaultHumanMessageKey {
+			t.Errorf("HumanMessageKey() = %q, want %q", got, handler.DefaultHumanMessageKey)
+		}
+	})
+
+	t.Run("renders attributes", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{Output: io.Discard}
+		if err := handler.ApplyOptions([]handler.BaseOption{
+			handler.WithHumanMessageTemplate("{{.msg}}: {{.user}} from {{.ip}}"),
+			handler.WithHumanMessageKey("human"),
+		}, opts); err != nil {
+			t.Fatalf("ApplyOptions() error = %v", err)
+		}
+		h := newHandler(t, opts).Clone()
+
+		got, ok := h.HumanMessage("login", []any{"user", "jane", "ip", "10.0.0.1"})
+		if want := "login: jane from 10.0.0.1"; !ok || got != want {
+			t.Errorf("HumanMessage() = %q, %v, want %q, true", got, ok, want)
+		}
+		if got := h.HumanMessageKey(); got != "human" {
+			t.Errorf("HumanMessageKey() = %q, want %q", got, "human")
+		}
+	})
+
+	t.Run("execution error keeps message", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{Output: io.Discard}
+		if err := handler.WithHumanMessageTemplate("{{.user.Name}}")(opts); err != nil {
+			t.Fatalf("WithHumanMessageTemplate() error = %v", err)
+		}
+		h := newHandler(t, opts)
+
+		if got, ok := h.HumanMessage("msg", []any{"user", 42}); !ok || got != "msg" {
+			t.Errorf("HumanMessage() = %q, %v, want %q, true", got, ok, "msg")
+		}
+	})
+}
+
+// --- Test Option Application ---
+
+func TestApplyOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies all in order", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{}
+		err := handler.ApplyOptions([]handler.BaseOption{
+			handler.WithLevel(handler.WarnLevel),
+			nil,
+			handler.WithSeparator("."),
+			handler.WithSeparator("::"),
+		}, opts)
+		if err != nil {
+			t.Fatalf("ApplyOptions() error = %v, want nil", err)
+		}
+		if opts.Level != handler.WarnLevel {
+			t.Errorf("Level = %v, want %v", opts.Level, handler.WarnLevel)
+		}
+		if opts.Separator != "::" {
+			t.Errorf("Separator = %q, want %q", opts.Separator, "::")
+		}
+	})
+
+	t.Run("stops on first error", func(t *testing.T) {
+		t.Parallel()
+		var calls int
+		counter := func(*handler.BaseOptions) error {
+			calls++
+			return nil
+		}
+		opts := &handler.BaseOptions{}
+		err := handler.ApplyOptions([]handler.BaseOption{
+			counter,
+			handler.WithOutput(nil),
+			handler.WithLevel(handler.MaxLevel + 1),
+			counter,
+		}, opts)
+		if !errors.Is(err, handler.ErrNilWriter) {
+			t.Fatalf("ApplyOptions() error = %v, want %v", err, handler.ErrNilWriter)
+		}
+		if errors.Is(err, handler.ErrInvalidLogLevel) {
+			t.Errorf("ApplyOptions() error = %v, should not contain later errors", err)
+		}
+		if calls != 1 {
+			t.Errorf("options applied after error: calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		if err := handler.ApplyOptions(nil, &handler.BaseOptions{}); err != nil {
+			t.Errorf("ApplyOptions(nil) error = %v, want nil", err)
+		}
+	})
+}
+
+func TestMustApplyOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{}
+		handler.MustApplyOptions([]handler.BaseOption{handler.WithCaller(true)}, opts)
+		if !opts.WithCaller {
+			t.Error("WithCaller = false, want true")
+		}
+	})
+
+	t.Run("panics on error", func(t *testing.T) {
+		t.Parallel()
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("MustApplyOptions() did not panic")
+			}
+			err, ok := r.(error)
+			if !ok || !errors.Is(err, handler.ErrOptionApplyFailed) {
+				t.Errorf("panic value = %v, want error wrapping %v", r, handler.ErrOptionApplyFailed)
+			}
+		}()
+		handler.MustApplyOptions([]handler.BaseOption{handler.WithOutput(nil)}, &handler.BaseOptions{})
+	})
+}
+
+func TestValidateOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("collects all errors", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{ValidFormats: []string{"json"}}
+		err := handler.ValidateOptions([]handler.BaseOption{
+			handler.WithOutput(nil),
+			handler.WithLevel(handler.MaxLevel + 1),
+			handler.WithFormat("xml"),
+			handler.WithFormat("json"),
+		}, opts)
+		for _, want := range []error{handler.ErrNilWriter, handler.ErrInvalidLogLevel, handler.ErrInvalidFormat} {
+			if !errors.Is(err, want) {
+				t.Errorf("ValidateOptions() error = %v, want it to contain %v", err, want)
+			}
+		}
+	})
+
+	t.Run("does not modify target", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{Level: handler.InfoLevel}
+		err := handler.ValidateOptions([]handler.BaseOption{
+			handler.WithLevel(handler.ErrorLevel),
+			handler.WithOutput(io.Discard),
+		}, opts)
+		if err != nil {
+			t.Fatalf("ValidateOptions() error = %v, want nil", err)
+		}
+		if opts.Level != handler.InfoLevel || opts.Output != nil {
+			t.Errorf("target modified: Level = %v, Output = %v", opts.Level, opts.Output)
+		}
+	})
+}
+
+func TestNewBaseHandlerFromOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		h, err := handler.NewBaseHandlerFromOptions(
+			&handler.BaseOptions{Level: handler.InfoLevel, ValidFormats: []string{"json", "text"}},
+			handler.WithOutput(io.Discard),
+			handler.WithLevel(handler.DebugLevel),
+			handler.WithFormat("text"),
+		)
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v, want nil", err)
+		}
+		if h.Level() != handler.DebugLevel {
+			t.Errorf("Level() = %v, want %v", h.Level(), handler.DebugLevel)
+		}
+		if h.Format() != "text" {
+			t.Errorf("Format() = %q, want %q", h.Format(), "text")
+		}
+	})
+
+	t.Run("nil defaults", func(t *testing.T) {
+		t.Parallel()
+		h, err := handler.NewBaseHandlerFromOptions(nil, handler.WithOutput(io.Discard))
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v, want nil", err)
+		}
+		if h.Level() != handler.DefaultLevel {
+			t.Errorf("Level() = %v, want %v", h.Level(), handler.DefaultLevel)
+		}
+	})
+
+	t.Run("shared defaults", func(t *testing.T) {
+		t.Parallel()
+		defaults := &handler.BaseOptions{Level: handler.InfoLevel, Output: io.Discard}
+
+		first, err := handler.NewBaseHandlerFromOptions(defaults,
+			handler.WithLevel(handler.ErrorLevel),
+			handler.WithCaller(true),
+		)
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v, want nil", err)
+		}
+		second, err := handler.NewBaseHandlerFromOptions(defaults)
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v, want nil", err)
+		}
+
+		if first.Level() != handler.ErrorLevel || !first.CallerEnabled() {
+			t.Errorf("first: Level() = %v, CallerEnabled() = %v, want %v, true", first.Level(), first.CallerEnabled(), handler.ErrorLevel)
+		}
+		if second.Level() != handler.InfoLevel || second.CallerEnabled() {
+			t.Errorf("second: Level() = %v, CallerEnabled() = %v, want %v, false", second.Level(), second.CallerEnabled(), handler.InfoLevel)
+		}
+		if defaults.Level != handler.InfoLevel || defaults.WithCaller {
+			t.Errorf("defaults modified: %+v", defaults)
+		}
+	})
+
+	t.Run("shared redact patterns", func(t *testing.T) {
+		t.Parallel()
+		// Spare capacity lets a plain append write into the shared array
+		patterns := make([]handler.RedactPattern, 1, 2)
+		patterns[0] = handler.RedactPattern{Regexp: regexp.MustCompile(`secret`), Replacement: "***"}
+		defaults := &handler.BaseOptions{Output: io.Discard, RedactPatterns: patterns}
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				re := regexp.MustCompile(fmt.Sprintf("token%d", i))
+				if _, err := handler.NewBaseHandlerFromOptions(defaults, handler.WithRedactPattern(re, "***")); err != nil {
+					t.Errorf("NewBaseHandlerFromOptions() error = %v, want nil", err)
+				}
+				if err := handler.ValidateOptions([]handler.BaseOption{handler.WithRedactPattern(re, "***")}, defaults); err != nil {
+					t.Errorf("ValidateOptions() error = %v, want nil", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(defaults.RedactPatterns) != 1 || patterns[:2][1].Regexp != nil {
+			t.Errorf("defaults modified: %+v", patterns[:2])
+		}
+	})
+
+	t.Run("option error", func(t *testing.T) {
+		t.Parallel()
+		_, err := handler.NewBaseHandlerFromOptions(nil, handler.WithOutput(nil))
+		if !errors.Is(err, handler.ErrOptionApplyFailed) {
+			t.Errorf("NewBaseHandlerFromOptions() error = %v, want %v", err, handler.ErrOptionApplyFailed)
+		}
+	})
+}
+
 // --- Test State Accessors ---
 
 // TestBaseHandler_StateAccessors verifies getters (Level, Format, etc.).
@@ -447,6 +1075,118 @@ func TestBaseHandler_Enabled(t *testing.T) {
 	}
 }
 
+func TestBaseHandler_EnabledContext(t *testing.T) {
+	t.Parallel()
+
+	h := newHandler(t, &handler.BaseOptions{Level: handler.InfoLevel, Output: io.Discard})
+	debug := handler.WithContextLevel(context.Background(), handler.DebugLevel)
+	warn := handler.WithContextLevel(context.Background(), handler.WarnLevel)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		level handler.LogLevel
+		want  bool
+	}{
+		{"no context level", context.Background(), handler.DebugLevel, false},
+		{"nil context", nil, handler.InfoLevel, true},
+		{"lower context level", debug, handler.DebugLevel, true},
+		{"below lower context level", debug, handler.TraceLevel, false},
+		{"higher context level", warn, handler.InfoLevel, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := h.EnabledContext(tt.ctx, tt.level); got != tt.want {
+				t.Errorf("EnabledContext(%v) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseHandler_FailFast(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	readOnly, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("os.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = readOnly.Close() })
+
+	tests := []struct {
+		name     string
+		out      io.Writer
+		failFast bool
+		wantErr  bool
+	}{
+		{"writable output", io.Discard, true, false},
+		{"read-only file", readOnly, true, true},
+		{"read-only file without fail fast", readOnly, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := handler.NewBaseHandlerFromOptions(nil,
+				handler.WithOutput(tt.out),
+				handler.WithFailFast(tt.failFast),
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBaseHandlerFromOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, handler.ErrOutputProbeFailed) {
+				t.Errorf("error = %v, want ErrOutputProbeFailed", err)
+			}
+		})
+	}
+}
+
+func TestBaseHandler_EnabledLevels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+		if err := handler.WithEnabledLevels()(&handler.BaseOptions{}); err == nil {
+			t.Error("WithEnabledLevels() error = nil, want error for no levels")
+		}
+		if err := handler.WithEnabledLevels(handler.MaxLevel + 1)(&handler.BaseOptions{}); err == nil {
+			t.Error("WithEnabledLevels() error = nil, want error for invalid level")
+		}
+	})
+
+	t.Run("set overrides threshold", func(t *testing.T) {
+		t.Parallel()
+		h, err := handler.NewBaseHandlerFromOptions(nil,
+			handler.WithOutput(io.Discard),
+			handler.WithEnabledLevels(handler.ErrorLevel, handler.TraceLevel),
+		)
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+		}
+		if got := h.Level(); got != handler.TraceLevel {
+			t.Errorf("Level() = %v, want lowest enabled level %v", got, handler.TraceLevel)
+		}
+
+		clone, _ := h.WithLevel(handler.WarnLevel)
+		for _, b := range []*handler.BaseHandler{h, clone} {
+			for level := handler.MinLevel; level <= handler.MaxLevel; level++ {
+				want := level == handler.TraceLevel || level == handler.ErrorLevel
+				if got := b.Enabled(level); got != want {
+					t.Errorf("Enabled(%v) = %v, want %v", level, got, want)
+				}
+			}
+			if b.Enabled(handler.MaxLevel + 1) {
+				t.Error("Enabled() = true for an invalid level")
+			}
+		}
+	})
+}
+
 // TestBaseHandler_FlagManagement verifies HasFlag and SetFlag.
 func TestBaseHandler_FlagManagement(t *testing.T) {
 	t.Parallel()
@@ -613,6 +1353,60 @@ func TestBaseHandler_SetOutput(t *testing.T) {
 	})
 }
 
+func TestBaseHandler_OutputSwapHook(t *testing.T) {
+	t.Parallel()
+
+	type swap struct{ old, new io.Writer }
+
+	var buf1, buf2, buf3 bytes.Buffer
+	var swaps []swap
+	var h *handler.BaseHandler
+	opts := &handler.BaseOptions{Output: &buf1}
+	if err := handler.WithOutputSwapHook(func(old, new io.Writer) {
+		swaps = append(swaps, swap{old, new})
+		// The swap is complete: writes already reach the new writer
+		_, _ = h.AtomicWriter().Write([]byte("in hook"))
+	})(opts); err != nil {
+		t.Fatalf("WithOutputSwapHook() error = %v", err)
+	}
+	h = newHandler(t, opts)
+
+	if err := h.SetOutput(&buf2); err != nil {
+		t.Fatalf("SetOutput() error = %v", err)
+	}
+	// Clones share the output and report the writer set through the original
+	if err := h.Clone().SetOutput(&buf3); err != nil {
+		t.Fatalf("Clone().SetOutput() error = %v", err)
+	}
+
+	want := []swap{{&buf1, &buf2}, {&buf2, &buf3}}
+	if len(swaps) != len(want) {
+		t.Fatalf("hook called %d times, want %d", len(swaps), len(want))
+	}
+	for i := range want {
+		if swaps[i] != want[i] {
+			t.Errorf("swap %d = %p -> %p, want %p -> %p", i, swaps[i].old, swaps[i].new, want[i].old, want[i].new)
+		}
+	}
+	if buf1.Len() != 0 || buf2.String() != "in hook" || buf3.String() != "in hook" {
+		t.Errorf("writes from hook went to the wrong writer: %q, %q, %q", buf1.String(), buf2.String(), buf3.String())
+	}
+
+	// WithOutput starts a separate output that keeps the hook
+	var buf4, buf5 bytes.Buffer
+	independent, err := h.WithOutput(&buf4)
+	if err != nil {
+		t.Fatalf("WithOutput() error = %v", err)
+	}
+	h = independent
+	if err := independent.SetOutput(&buf5); err != nil {
+		t.Fatalf("SetOutput() error = %v", err)
+	}
+	if last := swaps[len(swaps)-1]; last.old != &buf4 || last.new != &buf5 {
+		t.Errorf("last swap = %p -> %p, want %p -> %p", last.old, last.new, &buf4, &buf5)
+	}
+}
+
 func TestBaseHandler_SetCallerSkip(t *testing.T) {
 	t.Parallel()
 
@@ -643,6 +1437,74 @@ func TestBaseHandler_SetCallerSkip(t *testing.T) {
 	})
 }
 
+func TestBaseHandler_SetCallerSkipDelta(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		initial int
+		delta   int
+		want    int
+		wantErr error
+	}{
+		{"positive delta", 1, 2, 3, nil},
+		{"zero delta", 2, 0, 2, nil},
+		{"negative delta within range", 3, -3, 0, nil},
+		{"negative delta underflow", 1, -2, 1, handler.ErrInvalidSourceSkip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newHandler(t, &handler.BaseOptions{Output: io.Discard, CallerSkip: tt.initial})
+			err := h.SetCallerSkipDelta(tt.delta)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetCallerSkipDelta(%d) error = %v, want %v", tt.delta, err, tt.wantErr)
+			}
+			if got := h.CallerSkip(); got != tt.want {
+				t.Errorf("CallerSkip() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("clone unaffected", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Output: io.Discard, CallerSkip: 1})
+		clone := h.Clone()
+		if err := h.SetCallerSkipDelta(1); err != nil {
+			t.Fatalf("SetCallerSkipDelta(1) error = %v", err)
+		}
+		if got := clone.CallerSkip(); got != 1 {
+			t.Errorf("clone.CallerSkip() = %d, want 1 (clones are independent)", got)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Output: io.Discard, CallerSkip: 1000})
+
+		var wg sync.WaitGroup
+		for i := range 200 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				delta := 3
+				if i%2 == 1 {
+					delta = -2
+				}
+				if err := h.SetCallerSkipDelta(delta); err != nil {
+					t.Errorf("SetCallerSkipDelta(%d) error = %v", delta, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if got, want := h.CallerSkip(), 1000+100*3-100*2; got != want {
+			t.Errorf("CallerSkip() = %d, want %d", got, want)
+		}
+	})
+}
+
 // TestBaseHandler_MutableSetters_Concurrent verifies setters are thread-safe.
 func TestBaseHandler_MutableSetters_Concurrent(t *testing.T) {
 	t.Parallel()
@@ -1020,3 +1882,93 @@ func TestBaseHandler_WithOutput(t *testing.T) {
 		}
 	})
 }
+
+func TestBaseHandler_WithIndependentOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		independent bool
+		wantParent  string
+	}{
+		{"shared by default", false, ""},
+		{"independent", true, "parent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var orig, swapped bytes.Buffer
+			opts := &handler.BaseOptions{Output: &orig}
+			if err := handler.WithIndependentOutput(tt.independent)(opts); err != nil {
+				t.Fatalf("WithIndependentOutput() error = %v", err)
+			}
+			parent := newHandler(t, opts)
+			child := parent.WithCaller(true)
+
+			if err := child.SetOutput(&swapped); err != nil {
+				t.Fatalf("SetOutput() error = %v", err)
+			}
+			_, _ = parent.AtomicWriter().Write([]byte("parent"))
+			_, _ = child.AtomicWriter().Write([]byte("child"))
+
+			if got := orig.String(); got != tt.wantParent {
+				t.Errorf("original output = %q, want %q", got, tt.wantParent)
+			}
+			if got := swapped.String(); !strings.HasSuffix(got, "child") {
+				t.Errorf("swapped output = %q, want it to end with %q", got, "child")
+			}
+		})
+	}
+}
+
+func TestBaseHandler_CallerLevel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default from options literal", func(t *testing.T) {
+		t.Parallel()
+		h := newHandler(t, &handler.BaseOptions{Level: handler.InfoLevel, Output: io.Discard, WithCaller: true})
+		if got := h.CallerLevel(); got != handler.MinLevel {
+			t.Errorf("CallerLevel() = %v, want %v", got, handler.MinLevel)
+		}
+	})
+
+	t.Run("debug level set with option", func(t *testing.T) {
+		t.Parallel()
+		opts := &handler.BaseOptions{Output: io.Discard}
+		if err := handler.WithCallerLevel(handler.DebugLevel)(opts); err != nil {
+			t.Fatalf("WithCallerLevel() error = %v", err)
+		}
+		if got := newHandler(t, opts).CallerLevel(); got != handler.DebugLevel {
+			t.Errorf("CallerLevel() = %v, want %v", got, handler.DebugLevel)
+		}
+	})
+}
+
+func TestWithDebugFriendlyDefaults(t *testing.T) {
+	t.Parallel()
+
+	opts := &handler.BaseOptions{Output: io.Discard}
+	err := handler.ApplyOptions([]handler.BaseOption{
+		handler.WithDebugFriendlyDefaults(),
+		handler.WithTraceLevel(handler.CriticalLevel),
+	}, opts)
+	if err != nil {
+		t.Fatalf("ApplyOptions() error = %v", err)
+	}
+	h := newHandler(t, opts)
+
+	if got := h.Level(); got != handler.InfoLevel {
+		t.Errorf("Level() = %v, want %v", got, handler.InfoLevel)
+	}
+	if !h.CallerEnabled() || h.CallerLevel() != handler.WarnLevel {
+		t.Errorf("caller = %v from %v, want true from %v", h.CallerEnabled(), h.CallerLevel(), handler.WarnLevel)
+	}
+	if !h.TraceEnabled() || h.TraceLevel() != handler.CriticalLevel {
+		t.Errorf("trace = %v from %v, want true from the overriding %v", h.TraceEnabled(), h.TraceLevel(), handler.CriticalLevel)
+	}
+
+	if err := handler.WithCallerLevel(handler.LogLevel(99))(opts); err == nil {
+		t.Error("WithCallerLevel(99) error = nil, want error")
+	}
+}
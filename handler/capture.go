@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Output formats supported by NewTestCapture.
+const (
+	CaptureFormatText = "text"
+	CaptureFormatJSON = "json"
+)
+
+// goldenUpdateEnv is the environment variable that makes TestCapture.Golden
+// (re)write golden files instead of comparing against them.
+const goldenUpdateEnv = "UPDATE_GOLDEN"
+
+// TestCapture accumulates the serialized output of its handler for
+// snapshot-style assertions in tests. It is safe for concurrent use.
+type TestCapture struct {
+	mu     sync.Mutex
+	out    strings.Builder
+	format string
+}
+
+// captureHandler is the Handler returned by NewTestCapture.
+type captureHandler struct {
+	capture *TestCapture
+	attrs   attrState
+}
+
+// Ensure captureHandler implements the handler interfaces.
+var (
+	_ Handler      = (*captureHandler)(nil)
+	_ Chainer      = (*captureHandler)(nil)
+	_ HandlerState = (*captureHandler)(nil)
+)
+
+// NewTestCapture returns a capture and a handler that serializes every record
+// into it using format (CaptureFormatText or CaptureFormatJSON).
+// The handler is enabled at all levels and omits timestamps so the output is
+// deterministic. It panics if format is not supported.
+//
+// Text lines look like:
+//
+//	INFO user created id=42 name="Jane Doe"
+//
+// JSON lines look like:
+//
+//	{"level":"INFO","msg":"user created","id":42,"name":"Jane Doe"}
+func NewTestCapture(format string) (*TestCapture, Handler) {
+	if format != CaptureFormatText && format != CaptureFormatJSON {
+		panic(NewInvalidFormatError(format, []string{CaptureFormatText, CaptureFormatJSON}))
+	}
+
+	c := &TestCapture{format: format}
+
+	return c, &captureHandler{capture: c}
+}
+
+// Output returns all output accumulated so far.
+func (c *TestCapture) Output() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.out.String()
+}
+
+// Golden compares the accumulated output with testdata/<name>.golden and
+// reports a test error on mismatch. If the UPDATE_GOLDEN environment variable
+// is set to "1", the golden file is (re)written with the current output instead.
+func (c *TestCapture) Golden(t *testing.T, name string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	got := c.Output()
+
+	if os.Getenv(goldenUpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s (run with %s=1 to create it): %v", path, goldenUpdateEnv, err)
+	}
+
+	if got != string(want) {
+		t.Errorf("output does not match golden file %s (run with %s=1 to update)\n--- got:\n%s--- want:\n%s",
+			path, goldenUpdateEnv, got, want)
+	}
+}
+
+// write serializes r into the capture.
+func (c *TestCapture) write(r *Record) {
+	var buf bytes.Buffer
+	switch c.format {
+	case CaptureFormatJSON:
+		buf.WriteByte('{')
+		appendJSONFields(&buf, r)
+		buf.WriteString("}\n")
+	default:
+		appendRecordText(&buf, r)
+	}
+
+	c.mu.Lock()
+	c.out.Write(buf.Bytes())
+	c.mu.Unlock()
+}
+
+// Handle serializes the record with the handler's attributes and group prefix applied.
+func (h *captureHandler) Handle(_ context.Context, r *Record) error {
+	if r.IsZero() {
+		return ErrZeroRecord
+	}
+	h.capture.write(&Record{Level: r.Level, Message: r.Message, KeyValues: h.attrs.merge(r.KeyValues)})
+
+	return nil
+}
+
+// Enabled always returns true.
+func (h *captureHandler) Enabled(LogLevel) bool { return true }
+
+// HandlerState returns the handler itself; caller and trace reporting are disabled.
+func (h *captureHandler) HandlerState() HandlerState { return h }
+
+// Features reports no native features.
+func (h *captureHandler) Features() HandlerFeatures { return HandlerFeatures{} }
+
+// CallerEnabled returns false.
+func (h *captureHandler) CallerEnabled() bool { return false }
+
+// TraceEnabled returns false.
+func (h *captureHandler) TraceEnabled() bool { return false }
+
+// CallerSkip returns 0.
+func (h *captureHandler) CallerSkip() int { return 0 }
+
+// WithAttrs returns a new handler with the key-value pairs added.
+func (h *captureHandler) WithAttrs(keyValues []any) Chainer {
+	if len(keyValues) < 2 {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = h.attrs.withAttrs(keyValues)
+
+	return &clone
+}
+
+// WithGroup returns a new handler that prefixes subsequent keys with name
+// using DefaultKeySeparator.
+func (h *captureHandler) WithGroup(name string) Chainer {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = h.attrs.withGroup(name)
+
+	return &clone
+}
+
+// appendRecordText encodes r as "LEVEL msg key=value ...\n".
+// Values containing spaces, quotes or '=' are quoted.
+func appendRecordText(buf *bytes.Buffer, r *Record) {
+	buf.WriteString(r.Level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(r.Message)
+
+	for i := 0; i < len(r.KeyValues)-1; i += 2 {
+		buf.WriteByte(' ')
+		buf.WriteString(fmt.Sprint(r.KeyValues[i]))
+		buf.WriteByte('=')
+
+		v := FormatValue(r.KeyValues[i+1])
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			v = strconv.Quote(v)
+		}
+		buf.WriteString(v)
+	}
+
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// logSample writes a fixed set of records through h.
+func logSample(t *testing.T, h handler.Handler) {
+	t.Helper()
+
+	ctx := context.Background()
+	ch := h.(handler.Chainer)
+	records := []struct {
+		h handler.Handler
+		r *handler.Record
+	}{
+		{h, &handler.Record{Level: handler.InfoLevel, Message: "user created", KeyValues: []any{"id", 42, "name", "Jane Doe"}}},
+		{ch.WithAttrs([]any{"svc", "api"}).WithGroup("req"), &handler.Record{Level: handler.WarnLevel, Message: "slow", KeyValues: []any{"ms", 1.5}}},
+		{h, &handler.Record{Level: handler.ErrorLevel, Message: "failed", KeyValues: []any{"err", errors.New("boom"), "empty", ""}}},
+	}
+	for _, rec := range records {
+		if err := rec.h.Handle(ctx, rec.r); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+}
+
+func TestNewTestCapture_Output(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: handler.CaptureFormatText,
+			want: "INFO user created id=42 name=\"Jane Doe\"\n" +
+				"WARN slow svc=api req_ms=1.5\n" +
+				"ERROR failed err=boom empty=\"\"\n",
+		},
+		{
+			format: handler.CaptureFormatJSON,
+			want: `{"level":"INFO","msg":"user created","id":42,"name":"Jane Doe"}` + "\n" +
+				`{"level":"WARN","msg":"slow","svc":"api","req_ms":1.5}` + "\n" +
+				`{"level":"ERROR","msg":"failed","err":"boom","empty":""}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+			c, h := handler.NewTestCapture(tt.format)
+			if !h.Enabled(handler.TraceLevel) {
+				t.Error("Enabled(TraceLevel) = false, want true")
+			}
+
+			logSample(t, h)
+
+			if got := c.Output(); got != tt.want {
+				t.Errorf("Output() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTestCapture_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, handler.ErrInvalidFormat) {
+			t.Errorf("recover() = %v, want ErrInvalidFormat", r)
+		}
+	}()
+	handler.NewTestCapture("xml")
+}
+
+func TestTestCapture_Golden(t *testing.T) {
+	// Not parallel: changes the working directory and environment.
+	t.Chdir(t.TempDir())
+
+	c, h := handler.NewTestCapture(handler.CaptureFormatText)
+	logSample(t, h)
+
+	t.Setenv("UPDATE_GOLDEN", "1")
+	c.Golden(t, "sample")
+
+	data, err := os.ReadFile(filepath.Join("testdata", "sample.golden"))
+	if err != nil {
+		t.Fatalf("golden file not created: %v", err)
+	}
+	if string(data) != c.Output() {
+		t.Errorf("golden file = %q, want %q", data, c.Output())
+	}
+
+	// Comparison mode passes against the file just written
+	t.Setenv("UPDATE_GOLDEN", "")
+	c.Golden(t, "sample")
+}
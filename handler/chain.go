@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"errors"
+)
+
+// Middleware wraps next in a new Handler, e.g. to buffer, filter or enrich
+// records before they reach it. Wrapping constructors such as
+// NewRingBufferHandler are adapted with a one-line closure:
+//
+//	ring := func(next Handler) (Handler, error) { return NewRingBufferHandler(next, 100) }
+type Middleware func(next Handler) (Handler, error)
+
+// chainHandler is a pipeline built by Chain. Records enter at the outermost
+// stage; stages lists every stage from the outermost to the innermost.
+type chainHandler struct {
+	outer  Handler
+	stages []Handler
+}
+
+// Ensure chainHandler implements the handler interfaces.
+var (
+	_ Handler = (*chainHandler)(nil)
+	_ Chainer = (*chainHandler)(nil)
+	_ Syncer  = (*chainHandler)(nil)
+)
+
+// Chain builds a pipeline around inner. Each middleware wraps the result of
+// the previous one, so the first middleware sits closest to inner and the
+// last one receives records first:
+//
+//	h, err := handler.Chain(fileHandler, sample, enrich)
+//	// records flow: enrich -> sample -> fileHandler
+//
+// The returned handler delegates Enabled, HandlerState and Features to the
+// outermost stage, and its Sync flushes every stage implementing Syncer from
+// the outermost inward. With no middlewares, inner is returned as is.
+// Returns error if inner or a middleware is nil, if a middleware fails, or if
+// a middleware returns a nil handler.
+func Chain(inner Handler, middlewares ...Middleware) (Handler, error) {
+	if inner == nil {
+		return nil, ErrNilHandler
+	}
+	if len(middlewares) == 0 {
+		return inner, nil
+	}
+
+	stages := make([]Handler, len(middlewares)+1)
+	stages[len(middlewares)] = inner
+
+	h := inner
+	for i, mw := range middlewares {
+		if mw == nil {
+			return nil, errors.New("middleware cannot be nil")
+		}
+		next, err := mw(h)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, ErrNilHandler
+		}
+		h = next
+		stages[len(middlewares)-1-i] = h
+	}
+
+	return &chainHandler{outer: h, stages: stages}, nil
+}
+
+// Handle passes the record to the outermost stage.
+func (c *chainHandler) Handle(ctx context.Context, r *Record) error {
+	return c.outer.Handle(ctx, r)
+}
+
+// Enabled reports whether the outermost stage is enabled for level.
+func (c *chainHandler) Enabled(level LogLevel) bool {
+	return c.outer.Enabled(level)
+}
+
+// HandlerState returns the outermost stage's state.
+func (c *chainHandler) HandlerState() HandlerState {
+	return c.outer.HandlerState()
+}
+
+// Features returns the outermost stage's features.
+func (c *chainHandler) Features() HandlerFeatures {
+	return c.outer.Features()
+}
+
+// WithAttrs returns a pipeline whose outermost stage has the key-value pairs
+// added. It returns the original pipeline if the outermost stage does not
+// implement Chainer.
+func (c *chainHandler) WithAttrs(keyValues []any) Chainer {
+	ch, ok := c.outer.(Chainer)
+	if !ok {
+		return c
+	}
+
+	return &chainHandler{outer: ch.WithAttrs(keyValues), stages: c.stages}
+}
+
+// WithGroup returns a pipeline whose outermost stage starts the group.
+// It returns the original pipeline if the outermost stage does not
+// implement Chainer.
+func (c *chainHandler) WithGroup(name string) Chainer {
+	ch, ok := c.outer.(Chainer)
+	if !ok {
+		return c
+	}
+
+	return &chainHandler{outer: ch.WithGroup(name), stages: c.stages}
+}
+
+// Sync flushes every stage implementing Syncer, from the outermost inward.
+// All stages are flushed even if one fails; the errors are joined.
+// Stages that already forward Sync to their inner handler cause it to be
+// flushed more than once, which Syncer implementations must tolerate.
+func (c *chainHandler) Sync() error {
+	var errs []error
+	for _, s := range c.stages {
+		if syncer, ok := s.(Syncer); ok {
+			if err := syncer.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
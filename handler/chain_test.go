@@ -0,0 +1,146 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// stageHandler is a pipeline stage that prefixes messages with its name
+// and logs its Sync calls to a shared trace.
+type stageHandler struct {
+	name  string
+	next  handler.Handler
+	mu    *sync.Mutex
+	trace *[]string
+}
+
+func (h *stageHandler) Handle(ctx context.Context, r *handler.Record) error {
+	r.Message = h.name + ":" + r.Message
+	return h.next.Handle(ctx, r)
+}
+
+func (h *stageHandler) Enabled(level handler.LogLevel) bool { return level >= handler.WarnLevel }
+func (h *stageHandler) HandlerState() handler.HandlerState  { return nil }
+func (h *stageHandler) Features() handler.HandlerFeatures   { return handler.HandlerFeatures{} }
+
+func (h *stageHandler) Sync() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.trace = append(*h.trace, h.name)
+	return nil
+}
+
+func stage(name string, mu *sync.Mutex, trace *[]string) handler.Middleware {
+	return func(next handler.Handler) (handler.Handler, error) {
+		return &stageHandler{name: name, next: next, mu: mu, trace: trace}, nil
+	}
+}
+
+func TestChain_Errors(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{}
+	failing := func(handler.Handler) (handler.Handler, error) { return nil, errors.New("boom") }
+	returnsNil := func(handler.Handler) (handler.Handler, error) { return nil, nil }
+
+	tests := []struct {
+		name        string
+		inner       handler.Handler
+		middlewares []handler.Middleware
+	}{
+		{"nil inner", nil, nil},
+		{"nil middleware", inner, []handler.Middleware{nil}},
+		{"failing middleware", inner, []handler.Middleware{failing}},
+		{"middleware returns nil", inner, []handler.Middleware{returnsNil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := handler.Chain(tt.inner, tt.middlewares...); err == nil {
+				t.Error("Chain() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestChain_NoMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{}
+	h, err := handler.Chain(inner)
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if h != handler.Handler(inner) {
+		t.Error("Chain() without middlewares should return inner")
+	}
+}
+
+func TestChain_Pipeline(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var trace []string
+	inner := &recordingHandler{}
+	var ring *handler.RingBufferHandler
+
+	h, err := handler.Chain(inner,
+		func(next handler.Handler) (handler.Handler, error) {
+			var err error
+			ring, err = handler.NewRingBufferHandler(next, 10)
+			return ring, err
+		},
+		stage("enrich", &mu, &trace),
+		stage("outer", &mu, &trace),
+	)
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+
+	// Enabled comes from the outermost stage
+	if h.Enabled(handler.InfoLevel) || !h.Enabled(handler.WarnLevel) {
+		t.Error("Enabled() should delegate to the outermost stage")
+	}
+
+	if err := h.Handle(context.Background(), newRecord(handler.WarnLevel, "msg")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	// Records pass through every stage, outermost first
+	if got, want := inner.Messages(), []string{"enrich:outer:msg"}; !slices.Equal(got, want) {
+		t.Errorf("inner messages = %v, want %v", got, want)
+	}
+	var buf bytes.Buffer
+	if err := ring.Dump(&buf); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+	if got := dumpMessages(t, buf.String()); len(got) != 1 || got[0]["msg"] != "enrich:outer:msg" {
+		t.Errorf("ring buffer = %v, want the enriched record", got)
+	}
+
+	// Attributes are added at the outermost stage; the result still syncs
+	child, ok := h.(handler.Chainer)
+	if !ok {
+		t.Fatal("Chain() result does not implement Chainer")
+	}
+	s, ok := child.WithAttrs([]any{"k", "v"}).(handler.Syncer)
+	if !ok {
+		t.Fatal("Chain() result does not implement Syncer")
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if want := []string{"outer", "enrich"}; !slices.Equal(trace, want) {
+		t.Errorf("sync order = %v, want %v", trace, want)
+	}
+	if inner.syncs == 0 {
+		t.Error("inner handler was not synced")
+	}
+}
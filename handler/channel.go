@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy selects what a ChannelHandler does when its channel is full.
+type DropPolicy int
+
+const (
+	// DropNewest discards the record being sent, so logging never waits
+	// for the consumer.
+	DropNewest DropPolicy = iota
+
+	// Block waits until the consumer makes room, the record's context is
+	// done or the handler is closed; records are only dropped in the last
+	// two cases.
+	Block
+)
+
+// ChannelHandler sends a copy of every record to a channel, for in-process
+// consumers such as a live log viewer or a test that asserts on structured
+// records without parsing text.
+//
+// Handlers derived via WithAttrs and WithGroup share the channel, the
+// counters and the closed state. All methods are safe for concurrent use.
+type ChannelHandler struct {
+	sink  *channelSink
+	attrs attrState
+}
+
+// channelSink is the state shared by a ChannelHandler and its derived handlers.
+type channelSink struct {
+	ch      chan<- Record
+	policy  DropPolicy
+	sent    atomic.Uint64
+	dropped atomic.Uint64
+	closed  atomic.Bool
+	done    chan struct{} // Closed by Close to release blocked senders
+	once    sync.Once
+}
+
+// Ensure ChannelHandler implements the handler interfaces.
+var (
+	_ Handler      = (*ChannelHandler)(nil)
+	_ Chainer      = (*ChannelHandler)(nil)
+	_ HandlerState = (*ChannelHandler)(nil)
+)
+
+// NewChannelHandler returns a handler that sends records to ch, applying
+// full when ch has no room. The channel is owned by the caller: the handler
+// never closes it.
+//
+// Each record is a copy with its own KeyValues slice, holding the attributes
+// added with WithAttrs followed by the record's own, keys qualified by groups
+// as in MemoryHandler. Values themselves are not copied. Skip is cleared
+// since it is only meaningful on the logging goroutine; PC is kept.
+// Returns error if ch is nil or full is not a known policy.
+func NewChannelHandler(ch chan<- Record, full DropPolicy) (*ChannelHandler, error) {
+	if ch == nil {
+		return nil, errors.New("channel cannot be nil")
+	}
+	if full != DropNewest && full != Block {
+		return nil, errors.New("unknown drop policy")
+	}
+
+	return &ChannelHandler{
+		sink: &channelSink{ch: ch, policy: full, done: make(chan struct{})},
+	}, nil
+}
+
+// Handle sends a copy of the record to the channel, applying the drop policy
+// if the channel is full. It returns ErrHandlerClosed after Close.
+func (h *ChannelHandler) Handle(ctx context.Context, r *Record) error {
+	if r.IsZero() {
+		return ErrZeroRecord
+	}
+
+	s := h.sink
+	if s.closed.Load() {
+		return ErrHandlerClosed
+	}
+
+	rec := *r
+	rec.KeyValues = h.attrs.merge(r.KeyValues)
+	rec.Skip = 0
+
+	if s.policy == DropNewest {
+		select {
+		case s.ch <- rec:
+			s.sent.Add(1)
+		default:
+			s.dropped.Add(1)
+		}
+		return nil
+	}
+
+	var ctxDone <-chan struct{}
+	if ctx != nil {
+		ctxDone = ctx.Done()
+	}
+
+	select {
+	case s.ch <- rec:
+		s.sent.Add(1)
+		return nil
+	case <-ctxDone:
+		s.dropped.Add(1)
+		return ctx.Err()
+	case <-s.done:
+		s.dropped.Add(1)
+		return ErrHandlerClosed
+	}
+}
+
+// Enabled reports true for every level until the handler is closed.
+func (h *ChannelHandler) Enabled(LogLevel) bool {
+	return !h.sink.closed.Load()
+}
+
+// Dropped returns the number of records discarded because the channel was
+// full, including records abandoned while blocked.
+func (h *ChannelHandler) Dropped() uint64 {
+	return h.sink.dropped.Load()
+}
+
+// Close stops sending records and releases senders blocked on a full
+// channel. The channel is left open. Close is idempotent.
+func (h *ChannelHandler) Close() error {
+	s := h.sink
+	s.once.Do(func() {
+		s.closed.Store(true)
+		close(s.done)
+	})
+
+	return nil
+}
+
+// CloseStats closes the handler like Close and returns the number of records
+// sent to the channel and dropped over its lifetime, so that a graceful
+// shutdown can report whether any records were lost. Records handled
+// concurrently with the call may not be counted yet. Like Close, it can be
+// called any number of times.
+func (h *ChannelHandler) CloseStats() (sent, dropped uint64) {
+	_ = h.Close()
+
+	return h.sink.sent.Load(), h.sink.dropped.Load()
+}
+
+// HandlerState returns the handler itself; caller and trace reporting are disabled.
+func (h *ChannelHandler) HandlerState() HandlerState { return h }
+
+// Features reports no native features.
+func (h *ChannelHandler) Features() HandlerFeatures { return HandlerFeatures{} }
+
+// CallerEnabled returns false.
+func (h *ChannelHandler) CallerEnabled() bool { return false }
+
+// TraceEnabled returns false.
+func (h *ChannelHandler) TraceEnabled() bool { return false }
+
+// CallerSkip returns 0.
+func (h *ChannelHandler) CallerSkip() int { return 0 }
+
+// WithAttrs returns a new handler with the key-value pairs added.
+// The channel is shared with the original handler.
+func (h *ChannelHandler) WithAttrs(keyValues []any) Chainer {
+	if len(keyValues) < 2 {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = h.attrs.withAttrs(keyValues)
+
+	return &clone
+}
+
+// WithGroup returns a new handler that prefixes subsequent keys with name
+// using DefaultKeySeparator. The channel is shared with the original handler.
+func (h *ChannelHandler) WithGroup(name string) Chainer {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = h.attrs.withGroup(name)
+
+	return &clone
+}
@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestNewChannelHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := handler.NewChannelHandler(nil, handler.DropNewest); err == nil {
+		t.Error("NewChannelHandler(nil) error = nil, want error")
+	}
+	if _, err := handler.NewChannelHandler(make(chan handler.Record), handler.DropPolicy(42)); err == nil {
+		t.Error("NewChannelHandler(unknown policy) error = nil, want error")
+	}
+}
+
+func TestChannelHandler_CopiesRecords(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan handler.Record, 1)
+	h, _ := handler.NewChannelHandler(ch, handler.DropNewest)
+	child := h.WithAttrs([]any{"svc", "api"}).WithGroup("req")
+
+	kv := []any{"id", 7}
+	r := newRecord(handler.InfoLevel, "msg", kv...)
+	r.Skip = 3
+	if err := child.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	// The record may be reused by the logger once Handle returns
+	r.Message = "reused"
+	kv[1] = 8
+
+	got := <-ch
+	if got.Message != "msg" || got.Skip != 0 {
+		t.Errorf("record = %+v, want message %q and no skip", got, "msg")
+	}
+	if want := []any{"svc", "api", "req_id", 7}; !slices.Equal(got.KeyValues, want) {
+		t.Errorf("KeyValues = %v, want %v", got.KeyValues, want)
+	}
+}
+
+func TestChannelHandler_DropNewest(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan handler.Record, 1)
+	h, _ := handler.NewChannelHandler(ch, handler.DropNewest)
+
+	_ = h.Handle(context.Background(), newRecord(handler.InfoLevel, "first"))
+	if err := h.Handle(context.Background(), newRecord(handler.InfoLevel, "second")); err != nil {
+		t.Fatalf("Handle() on a full channel error = %v, want nil", err)
+	}
+
+	if got := (<-ch).Message; got != "first" {
+		t.Errorf("received %q, want %q", got, "first")
+	}
+	if got := h.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+}
+
+func TestChannelHandler_Block(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan handler.Record)
+	h, _ := handler.NewChannelHandler(ch, handler.Block)
+
+	// A consumer receives the record the sender waits on
+	go func() { <-ch }()
+	if err := h.Handle(context.Background(), newRecord(handler.InfoLevel, "msg")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	// A done context abandons the wait
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := h.Handle(ctx, newRecord(handler.InfoLevel, "msg")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Handle() error = %v, want DeadlineExceeded", err)
+	}
+	if got := h.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+
+	// Close releases a blocked sender
+	errc := make(chan error, 1)
+	go func() { errc <- h.Handle(context.Background(), newRecord(handler.InfoLevel, "msg")) }()
+	time.Sleep(10 * time.Millisecond)
+	_ = h.Close()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, handler.ErrHandlerClosed) {
+			t.Errorf("blocked Handle() error = %v, want ErrHandlerClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() did not release the blocked sender")
+	}
+}
+
+func TestChannelHandler_Close(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan handler.Record, 1)
+	h, _ := handler.NewChannelHandler(ch, handler.DropNewest)
+	child := h.WithAttrs([]any{"k", "v"})
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	if child.Enabled(handler.ErrorLevel) {
+		t.Error("Enabled() should report false after Close")
+	}
+	if err := child.Handle(context.Background(), newRecord(handler.InfoLevel, "msg")); !errors.Is(err, handler.ErrHandlerClosed) {
+		t.Errorf("Handle() after Close error = %v, want ErrHandlerClosed", err)
+	}
+
+	// The user-owned channel stays open
+	select {
+	case ch <- handler.Record{}:
+	default:
+		t.Error("channel should remain usable after Close")
+	}
+}
+
+func TestChannelHandler_CloseStats(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan handler.Record, 2)
+	h, _ := handler.NewChannelHandler(ch, handler.DropNewest)
+
+	for _, msg := range []string{"first", "second", "third"} {
+		_ = h.Handle(context.Background(), newRecord(handler.InfoLevel, msg))
+	}
+
+	sent, dropped := h.CloseStats()
+	if sent != 2 || dropped != 1 {
+		t.Errorf("CloseStats() = %d, %d, want 2, 1", sent, dropped)
+	}
+	if h.Enabled(handler.ErrorLevel) {
+		t.Error("Enabled() should report false after CloseStats")
+	}
+	if sent, dropped := h.CloseStats(); sent != 2 || dropped != 1 {
+		t.Errorf("second CloseStats() = %d, %d, want 2, 1", sent, dropped)
+	}
+}
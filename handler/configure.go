@@ -0,0 +1,43 @@
+package handler
+
+import "io"
+
+// SetLevelIfSupported changes the minimum level of h using whichever
+// configuration style it supports.
+//
+// If h implements MutableConfig, the level is changed in place and h is
+// returned. Otherwise, if h implements Configurable, the handler returned by
+// WithLevel is returned and h is left unchanged. If neither is implemented,
+// h is returned with ErrNotSupported.
+func SetLevelIfSupported(h Handler, level LogLevel) (Handler, error) {
+	if err := ValidateLogLevel(level); err != nil {
+		return h, err
+	}
+
+	switch c := h.(type) {
+	case MutableConfig:
+		return h, c.SetLevel(level)
+	case Configurable:
+		return c.WithLevel(level), nil
+	default:
+		return h, ErrNotSupported
+	}
+}
+
+// SetOutputIfSupported changes the output writer of h using whichever
+// configuration style it supports, with the same precedence and return
+// semantics as SetLevelIfSupported.
+func SetOutputIfSupported(h Handler, w io.Writer) (Handler, error) {
+	if w == nil {
+		return h, ErrNilWriter
+	}
+
+	switch c := h.(type) {
+	case MutableConfig:
+		return h, c.SetOutput(w)
+	case Configurable:
+		return c.WithOutput(w), nil
+	default:
+		return h, ErrNotSupported
+	}
+}
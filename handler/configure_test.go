@@ -0,0 +1,151 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// plainHandler supports neither configuration interface.
+type plainHandler struct{}
+
+func (plainHandler) Handle(context.Context, *handler.Record) error { return nil }
+func (plainHandler) Enabled(handler.LogLevel) bool                 { return true }
+func (plainHandler) HandlerState() handler.HandlerState            { return nil }
+func (plainHandler) Features() handler.HandlerFeatures             { return handler.HandlerFeatures{} }
+
+// mutableHandler implements MutableConfig and Configurable, to verify precedence.
+type mutableHandler struct {
+	plainHandler
+	level handler.LogLevel
+	out   io.Writer
+}
+
+func (h *mutableHandler) SetLevel(level handler.LogLevel) error { h.level = level; return nil }
+func (h *mutableHandler) SetOutput(w io.Writer) error           { h.out = w; return nil }
+
+func (h *mutableHandler) WithLevel(handler.LogLevel) handler.Configurable {
+	panic("WithLevel must not be used when MutableConfig is available")
+}
+
+func (h *mutableHandler) WithOutput(io.Writer) handler.Configurable {
+	panic("WithOutput must not be used when MutableConfig is available")
+}
+
+// immutableHandler implements only Configurable.
+type immutableHandler struct {
+	plainHandler
+	level handler.LogLevel
+	out   io.Writer
+}
+
+func (h *immutableHandler) WithLevel(level handler.LogLevel) handler.Configurable {
+	clone := *h
+	clone.level = level
+	return &clone
+}
+
+func (h *immutableHandler) WithOutput(w io.Writer) handler.Configurable {
+	clone := *h
+	clone.out = w
+	return &clone
+}
+
+func TestSetLevelIfSupported(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MutableConfig", func(t *testing.T) {
+		t.Parallel()
+		h := &mutableHandler{}
+		got, err := handler.SetLevelIfSupported(h, handler.WarnLevel)
+		if err != nil {
+			t.Fatalf("SetLevelIfSupported() error = %v", err)
+		}
+		if got != h || h.level != handler.WarnLevel {
+			t.Errorf("handler = %v, level = %v, want same handler mutated to WARN", got, h.level)
+		}
+	})
+
+	t.Run("Configurable", func(t *testing.T) {
+		t.Parallel()
+		h := &immutableHandler{}
+		got, err := handler.SetLevelIfSupported(h, handler.WarnLevel)
+		if err != nil {
+			t.Fatalf("SetLevelIfSupported() error = %v", err)
+		}
+		nh, ok := got.(*immutableHandler)
+		if !ok || nh == h || nh.level != handler.WarnLevel {
+			t.Errorf("got %v, want new handler at WARN", got)
+		}
+		if h.level != 0 {
+			t.Error("original handler was modified")
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		t.Parallel()
+		h := plainHandler{}
+		got, err := handler.SetLevelIfSupported(h, handler.WarnLevel)
+		if !errors.Is(err, handler.ErrNotSupported) {
+			t.Errorf("error = %v, want ErrNotSupported", err)
+		}
+		if got != h {
+			t.Error("expected original handler")
+		}
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		t.Parallel()
+		if _, err := handler.SetLevelIfSupported(&mutableHandler{}, handler.LogLevel(100)); !errors.Is(err, handler.ErrInvalidLogLevel) {
+			t.Errorf("error = %v, want ErrInvalidLogLevel", err)
+		}
+	})
+}
+
+func TestSetOutputIfSupported(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MutableConfig", func(t *testing.T) {
+		t.Parallel()
+		h := &mutableHandler{}
+		var buf bytes.Buffer
+		got, err := handler.SetOutputIfSupported(h, &buf)
+		if err != nil {
+			t.Fatalf("SetOutputIfSupported() error = %v", err)
+		}
+		if got != h || h.out != &buf {
+			t.Error("expected same handler with output set")
+		}
+	})
+
+	t.Run("Configurable", func(t *testing.T) {
+		t.Parallel()
+		h := &immutableHandler{}
+		var buf bytes.Buffer
+		got, err := handler.SetOutputIfSupported(h, &buf)
+		if err != nil {
+			t.Fatalf("SetOutputIfSupported() error = %v", err)
+		}
+		if nh, ok := got.(*immutableHandler); !ok || nh == h || nh.out != &buf {
+			t.Errorf("got %v, want new handler with output set", got)
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		t.Parallel()
+		if _, err := handler.SetOutputIfSupported(plainHandler{}, io.Discard); !errors.Is(err, handler.ErrNotSupported) {
+			t.Errorf("error = %v, want ErrNotSupported", err)
+		}
+	})
+
+	t.Run("nil writer", func(t *testing.T) {
+		t.Parallel()
+		if _, err := handler.SetOutputIfSupported(&mutableHandler{}, nil); !errors.Is(err, handler.ErrNilWriter) {
+			t.Errorf("error = %v, want ErrNilWriter", err)
+		}
+	})
+}
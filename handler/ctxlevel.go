@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+)
+
+// contextLevelHandler is the Handler returned by NewContextLevelHandler.
+type contextLevelHandler struct {
+	inner Handler
+	level LogLevel // Level applied to records without a context level
+}
+
+// contextLevelKey is the context key for the per-request log level.
+type contextLevelKey struct{}
+
+// Ensure contextLevelHandler implements the handler interfaces.
+var (
+	_ Handler = (*contextLevelHandler)(nil)
+	_ Chainer = (*contextLevelHandler)(nil)
+)
+
+// WithContextLevel returns a context whose records are filtered at level by
+// any handler created with NewContextLevelHandler and by the backends, e.g. to
+// get DEBUG logs for a single request while the service logs at INFO.
+func WithContextLevel(ctx context.Context, level LogLevel) context.Context {
+	return context.WithValue(ctx, contextLevelKey{}, level)
+}
+
+// ContextLevel returns the level attached to ctx with WithContextLevel.
+// The boolean reports whether ctx carries one.
+func ContextLevel(ctx context.Context) (LogLevel, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+
+	level, ok := ctx.Value(contextLevelKey{}).(LogLevel)
+	return level, ok
+}
+
+// enabledContext reports whether h handles a record at level logged with
+// ctx. A level attached to ctx with WithContextLevel takes precedence over
+// h.Enabled.
+func enabledContext(ctx context.Context, h interface{ Enabled(LogLevel) bool }, level LogLevel) bool {
+	if lvl, ok := ContextLevel(ctx); ok {
+		return level >= lvl
+	}
+
+	return h.Enabled(level)
+}
+
+// NewContextLevelHandler returns a handler that filters each record at the
+// level attached to its context with WithContextLevel, and at the inner
+// handler's configured level otherwise. The context level may be lower or
+// higher than the configured one.
+//
+// Enabled has no context to inspect, so it reports true for every level and
+// the level check happens in Handle instead. Records are therefore built even
+// when they end up dropped.
+//
+// If inner implements Configurable, it is reconfigured at MinLevel so that it
+// accepts the records a lower context level lets through. Otherwise records
+// below the inner handler's level may still be dropped by the inner handler.
+// It panics if inner is nil.
+func NewContextLevelHandler(inner Handler) Handler {
+	if inner == nil {
+		panic(ErrNilHandler)
+	}
+
+	// The lowest level the inner handler accepts is its configured level
+	level := MaxLevel
+	for l := MinLevel; l < MaxLevel; l++ {
+		if inner.Enabled(l) {
+			level = l
+			break
+		}
+	}
+
+	if cfg, ok := inner.(Configurable); ok {
+		inner = cfg.WithLevel(MinLevel)
+	}
+
+	return &contextLevelHandler{inner: inner, level: level}
+}
+
+// Handle forwards the record to the inner handler if its level is at or above
+// the context level, or the configured level if the context carries none.
+func (h *contextLevelHandler) Handle(ctx context.Context, r *Record) error {
+	level, ok := ContextLevel(ctx)
+	if !ok {
+		level = h.level
+	}
+	if r.Level < level {
+		return nil
+	}
+
+	return h.inner.Handle(ctx, forwardedRecord(h.inner, r))
+}
+
+// Enabled reports true for every level; see NewContextLevelHandler.
+func (h *contextLevelHandler) Enabled(LogLevel) bool {
+	return true
+}
+
+// HandlerState returns the inner handler's state.
+func (h *contextLevelHandler) HandlerState() HandlerState {
+	return h.inner.HandlerState()
+}
+
+// Features returns the inner handler's features.
+func (h *contextLevelHandler) Features() HandlerFeatures {
+	return h.inner.Features()
+}
+
+// WithAttrs returns a handler whose inner handler has the key-value pairs added.
+// It returns the original handler if the inner handler does not implement Chainer.
+func (h *contextLevelHandler) WithAttrs(keyValues []any) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	return &contextLevelHandler{inner: ch.WithAttrs(keyValues), level: h.level}
+}
+
+// WithGroup returns a handler whose inner handler starts the group.
+// It returns the original handler if the inner handler does not implement Chainer.
+func (h *contextLevelHandler) WithGroup(name string) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	return &contextLevelHandler{inner: ch.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// filteringHandler drops records below its level in Handle, like the
+// backends do, and can be reconfigured with Configurable. Reconfigured
+// copies record into the same recordingHandler.
+type filteringHandler struct {
+	*recordingHandler
+	level handler.LogLevel
+}
+
+func (h *filteringHandler) Handle(ctx context.Context, r *handler.Record) error {
+	if !h.Enabled(r.Level) {
+		return nil
+	}
+	return h.recordingHandler.Handle(ctx, r)
+}
+
+func (h *filteringHandler) Enabled(level handler.LogLevel) bool { return level >= h.level }
+
+func (h *filteringHandler) WithLevel(level handler.LogLevel) handler.Configurable {
+	return &filteringHandler{recordingHandler: h.recordingHandler, level: level}
+}
+
+func (h *filteringHandler) WithOutput(io.Writer) handler.Configurable { return h }
+
+func TestContextLevel(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := handler.ContextLevel(context.Background()); ok {
+		t.Error("ContextLevel() found a level in an empty context")
+	}
+	ctx := handler.WithContextLevel(context.Background(), handler.DebugLevel)
+	if level, ok := handler.ContextLevel(ctx); !ok || level != handler.DebugLevel {
+		t.Errorf("ContextLevel() = %v, %v, want %v, true", level, ok, handler.DebugLevel)
+	}
+}
+
+func TestNewContextLevelHandler_NilPanics(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewContextLevelHandler(nil) did not panic")
+		}
+	}()
+	handler.NewContextLevelHandler(nil)
+}
+
+func TestContextLevelHandler(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{level: handler.InfoLevel}
+	h := handler.NewContextLevelHandler(inner)
+
+	if !h.Enabled(handler.TraceLevel) {
+		t.Error("Enabled() should report true for every level")
+	}
+
+	debugCtx := handler.WithContextLevel(context.Background(), handler.DebugLevel)
+	errorCtx := handler.WithContextLevel(context.Background(), handler.ErrorLevel)
+
+	// Concurrent requests only see their own context level
+	var wg sync.WaitGroup
+	for _, c := range []struct {
+		ctx context.Context
+		msg string
+	}{
+		{debugCtx, "debug-request"},
+		{errorCtx, "error-request"},
+		{context.Background(), "plain-request"},
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = h.Handle(c.ctx, newRecord(handler.DebugLevel, c.msg+":debug"))
+			_ = h.Handle(c.ctx, newRecord(handler.InfoLevel, c.msg+":info"))
+		}()
+	}
+	wg.Wait()
+
+	got := inner.Messages()
+	slices.Sort(got)
+	want := []string{"debug-request:debug", "debug-request:info", "plain-request:info"}
+	if !slices.Equal(got, want) {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+}
+
+func TestContextLevelHandler_Caller(t *testing.T) {
+	t.Parallel()
+
+	testForwardedCaller(t, handler.NewContextLevelHandler)
+}
+
+func TestContextLevelHandler_Configurable(t *testing.T) {
+	t.Parallel()
+
+	rec := &recordingHandler{}
+	inner := &filteringHandler{recordingHandler: rec, level: handler.InfoLevel}
+	h := handler.NewContextLevelHandler(inner)
+
+	// The inner handler is lowered so the DEBUG context level takes effect,
+	// while records without a context level keep the configured INFO level
+	ctx := handler.WithContextLevel(context.Background(), handler.DebugLevel)
+	_ = h.Handle(ctx, newRecord(handler.DebugLevel, "debug"))
+	_ = h.Handle(context.Background(), newRecord(handler.DebugLevel, "dropped"))
+	_ = h.Handle(context.Background(), newRecord(handler.InfoLevel, "info"))
+
+	if got, want := rec.Messages(), []string{"debug", "info"}; !slices.Equal(got, want) {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+	if inner.level != handler.InfoLevel {
+		t.Error("NewContextLevelHandler() modified the original handler")
+	}
+}
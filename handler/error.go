@@ -13,6 +13,11 @@ var (
 	ErrInvalidFormat     = errors.New("invalid format")
 	ErrInvalidSourceSkip = errors.New("source skip must be non-negative")
 	ErrNilWriter         = errors.New("writer cannot be nil")
+	ErrNilHandler        = errors.New("handler cannot be nil")
+	ErrNotSupported      = errors.New("operation not supported by handler")
+	ErrHandlerClosed     = errors.New("handler is closed")
+	ErrZeroRecord        = errors.New("record is uninitialized")
+	ErrOutputProbeFailed = errors.New("output is not writable")
 )
 
 // NewAtomicWriterError returns an error wrapping ErrAtomicWriterFail.
@@ -20,6 +25,11 @@ func NewAtomicWriterError(err error) error {
 	return errors.Join(ErrAtomicWriterFail, err)
 }
 
+// NewOutputProbeError returns an error wrapping ErrOutputProbeFailed.
+func NewOutputProbeError(err error) error {
+	return errors.Join(ErrOutputProbeFailed, err)
+}
+
 // NewOptionApplyError returns an error wrapping ErrOptionApplyFailed.
 func NewOptionApplyError(option string, err error) error {
 	return errors.Join(fmt.Errorf("%s: %w", option, ErrOptionApplyFailed), err)
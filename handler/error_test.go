@@ -34,6 +34,16 @@ func TestNewErrorWrappers(t *testing.T) {
 				errUnderlyingAtomic.Error(),
 			},
 		},
+		{
+			name:           "output probe error",
+			err:            handler.NewOutputProbeError(errUnderlyingAtomic),
+			wantErr:        handler.ErrOutputProbeFailed,
+			wantUnderlying: errUnderlyingAtomic,
+			wantContains: []string{
+				handler.ErrOutputProbeFailed.Error(),
+				errUnderlyingAtomic.Error(),
+			},
+		},
 		{
 			name:           "option error",
 			err:            handler.NewOptionApplyError("myOption", errUnderlyingOption),
@@ -134,6 +144,11 @@ func TestSentinelErrors(t *testing.T) {
 		{"ErrInvalidFormat", handler.ErrInvalidFormat, "invalid format"},
 		{"ErrInvalidSourceSkip", handler.ErrInvalidSourceSkip, "source skip must be non-negative"},
 		{"ErrNilWriter", handler.ErrNilWriter, "writer cannot be nil"},
+		{"ErrNilHandler", handler.ErrNilHandler, "handler cannot be nil"},
+		{"ErrNotSupported", handler.ErrNotSupported, "operation not supported by handler"},
+		{"ErrHandlerClosed", handler.ErrHandlerClosed, "handler is closed"},
+		{"ErrZeroRecord", handler.ErrZeroRecord, "record is uninitialized"},
+		{"ErrOutputProbeFailed", handler.ErrOutputProbeFailed, "output is not writable"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// valueFormatters maps a concrete type to its registered formatter.
+// It is replaced as a whole on registration (copy-on-write), so reads
+// on the hot path are lock-free.
+var (
+	valueFormattersMu sync.Mutex
+	valueFormatters   atomic.Pointer[map[reflect.Type]func(any) string]
+)
+
+// RegisterValueFormatter registers fn to render values of type T in text output.
+// It lets domain types (e.g., UUIDs, money amounts) be rendered consistently
+// across handlers without implementing fmt.Stringer on them.
+// A nil fn removes a previously registered formatter.
+//
+// Formatters are matched on the exact dynamic type of the value.
+// Built-in types handled by the FormatValue fast path (string, bool,
+// integers and floats) cannot be overridden, but named types derived
+// from them (e.g., type Cents int64) can.
+//
+// RegisterValueFormatter is safe for concurrent use, but is intended
+// to be called during program initialization.
+func RegisterValueFormatter[T any](fn func(T) string) {
+	t := reflect.TypeFor[T]()
+
+	valueFormattersMu.Lock()
+	defer valueFormattersMu.Unlock()
+
+	next := make(map[reflect.Type]func(any) string)
+	if current := valueFormatters.Load(); current != nil {
+		for k, v := range *current {
+			next[k] = v
+		}
+	}
+
+	if fn == nil {
+		delete(next, t)
+	} else {
+		next[t] = func(v any) string { return fn(v.(T)) }
+	}
+
+	valueFormatters.Store(&next)
+}
+
+// FormatValue renders v as text the way text-based handlers print attribute values.
+// Common built-in types take a fast path; other values are rendered by their
+// registered formatter (see RegisterValueFormatter) or, failing that, fmt.Sprint.
+func FormatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case int32:
+		return strconv.FormatInt(int64(val), 10)
+	case uint:
+		return strconv.FormatUint(uint64(val), 10)
+	case uint64:
+		return strconv.FormatUint(val, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case nil:
+		return fmt.Sprint(v)
+	}
+
+	if formatters := valueFormatters.Load(); formatters != nil {
+		if fn, ok := (*formatters)[reflect.TypeOf(v)]; ok {
+			return fn(v)
+		}
+	}
+
+	return fmt.Sprint(v)
+}
+
+// FormatJSONValue is like FormatValue, but renders maps, slices and arrays
+// as compact JSON (e.g. {"a":1} or [1,2,3]) instead of Go syntax, so that
+// text output stays machine-readable. Registered formatters take precedence,
+// byte slices are left to FormatValue, and values that cannot be marshaled
+// fall back to FormatValue.
+func FormatJSONValue(v any) string {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return FormatValue(v)
+	}
+
+	if _, ok := v.([]byte); ok {
+		return FormatValue(v)
+	}
+	if formatters := valueFormatters.Load(); formatters != nil {
+		if fn, ok := (*formatters)[rv.Type()]; ok {
+			return fn(v)
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return FormatValue(v)
+	}
+
+	return string(data)
+}
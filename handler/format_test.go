@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+type testMoney struct {
+	Cents    int64
+	Currency string
+}
+
+type testCents int64
+
+type testUnregistered struct{ A int }
+
+func TestFormatValue_FastPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []any{
+		"text", "", true, false,
+		0, -42, int64(math.MaxInt64), int32(-7),
+		uint(3), uint64(math.MaxUint64), uint32(9),
+		1.5, 1e21, float32(0.1), math.Inf(1),
+		nil,
+	}
+
+	for _, v := range tests {
+		if got, want := handler.FormatValue(v), fmt.Sprint(v); got != want {
+			t.Errorf("FormatValue(%#v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestFormatValue_Fallback(t *testing.T) {
+	t.Parallel()
+
+	tests := []any{
+		testUnregistered{A: 1},
+		errors.New("boom"),
+		[]int{1, 2},
+		map[string]int{"a": 1},
+	}
+
+	for _, v := range tests {
+		if got, want := handler.FormatValue(v), fmt.Sprint(v); got != want {
+			t.Errorf("FormatValue(%#v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestRegisterValueFormatter(t *testing.T) {
+	handler.RegisterValueFormatter(func(m testMoney) string {
+		return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)
+	})
+	handler.RegisterValueFormatter(func(c testCents) string {
+		return fmt.Sprintf("%dc", int64(c))
+	})
+	t.Cleanup(func() {
+		handler.RegisterValueFormatter[testMoney](nil)
+		handler.RegisterValueFormatter[testCents](nil)
+	})
+
+	if got, want := handler.FormatValue(testMoney{Cents: 1234, Currency: "EUR"}), "12.34 EUR"; got != want {
+		t.Errorf("FormatValue(testMoney) = %q, want %q", got, want)
+	}
+	if got, want := handler.FormatValue(testCents(5)), "5c"; got != want {
+		t.Errorf("FormatValue(testCents) = %q, want %q", got, want)
+	}
+	if got, want := handler.FormatValue(int64(5)), "5"; got != want {
+		t.Errorf("FormatValue(int64) = %q, want %q (underlying type must not match)", got, want)
+	}
+
+	t.Run("removal", func(t *testing.T) {
+		handler.RegisterValueFormatter[testCents](nil)
+		if got, want := handler.FormatValue(testCents(5)), "5"; got != want {
+			t.Errorf("FormatValue(testCents) after removal = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestRegisterValueFormatter_Concurrent(t *testing.T) {
+	type local struct{ N int }
+	t.Cleanup(func() { handler.RegisterValueFormatter[local](nil) })
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			handler.RegisterValueFormatter(func(l local) string { return "local" })
+		}()
+		go func() {
+			defer wg.Done()
+			_ = handler.FormatValue(local{N: i})
+		}()
+	}
+	wg.Wait()
+
+	if got := handler.FormatValue(local{}); got != "local" {
+		t.Errorf("FormatValue(local) = %q, want %q", got, "local")
+	}
+}
+
+func TestFormatJSONValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"map", map[string]int{"b": 2, "a": 1}, `{"a":1,"b":2}`},
+		{"slice", []int{1, 2, 3}, `[1,2,3]`},
+		{"array", [2]string{"x", "y"}, `["x","y"]`},
+		{"nested", map[string][]any{"k": {1, "v", nil}}, `{"k":[1,"v",null]}`},
+		{"bytes", []byte("ab"), fmt.Sprint([]byte("ab"))},
+		{"scalar", 42, "42"},
+		{"struct", testUnregistered{A: 1}, fmt.Sprint(testUnregistered{A: 1})},
+		{"unmarshalable", []complex128{1}, "[(1+0i)]"},
+	}
+
+	for _, tt := range tests {
+		if got := handler.FormatJSONValue(tt.v); got != tt.want {
+			t.Errorf("%s: FormatJSONValue() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// FunnelHandler merges records from several loggers, e.g. one per service
+// component, into a single inner handler and keeps per-source statistics.
+// Each component logs through its own proxy obtained from Proxy; a record's
+// source is the ID attached to its context with WithFunnelSource.
+type FunnelHandler struct {
+	inner   Handler
+	closed  atomic.Bool
+	records atomic.Uint64
+	errors  atomic.Uint64
+
+	mu      sync.Mutex
+	sources map[string]uint64
+}
+
+// FunnelStats is a snapshot of a FunnelHandler's counters.
+type FunnelStats struct {
+	Records uint64            // Records forwarded to the inner handler
+	Errors  uint64            // Records the inner handler failed to handle
+	Sources map[string]uint64 // Forwarded records per source ID; "" counts records without one
+}
+
+// funnelProxy is a Handler that forwards to a FunnelHandler's inner handler.
+type funnelProxy struct {
+	funnel *FunnelHandler
+	inner  Handler // The funnel's inner handler, possibly chained
+}
+
+// funnelSourceKey is the context key for the funnel source ID.
+type funnelSourceKey struct{}
+
+// Ensure funnelProxy implements the handler interfaces.
+var (
+	_ Handler = (*funnelProxy)(nil)
+	_ Chainer = (*funnelProxy)(nil)
+)
+
+// NewFunnelHandler returns a funnel forwarding to inner, together with a
+// first proxy to log through. Further proxies are obtained with Proxy.
+// It panics if inner is nil.
+func NewFunnelHandler(inner Handler) (*FunnelHandler, Handler) {
+	if inner == nil {
+		panic(ErrNilHandler)
+	}
+
+	f := &FunnelHandler{
+		inner:   inner,
+		sources: make(map[string]uint64),
+	}
+
+	return f, f.Proxy()
+}
+
+// WithFunnelSource returns a context whose records are attributed to source
+// by any FunnelHandler they pass through.
+func WithFunnelSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, funnelSourceKey{}, source)
+}
+
+// Proxy returns a new handler forwarding to the funnel's inner handler.
+func (f *FunnelHandler) Proxy() Handler {
+	return &funnelProxy{funnel: f, inner: f.inner}
+}
+
+// Stats returns a snapshot of the funnel's counters.
+func (f *FunnelHandler) Stats() FunnelStats {
+	f.mu.Lock()
+	sources := maps.Clone(f.sources)
+	f.mu.Unlock()
+
+	return FunnelStats{
+		Records: f.records.Load(),
+		Errors:  f.errors.Load(),
+		Sources: sources,
+	}
+}
+
+// Close stops all proxies from accepting new records: they report every
+// level as disabled and Handle returns ErrHandlerClosed. If the inner
+// handler implements Syncer, it is flushed. Close is idempotent; only the
+// first call flushes.
+func (f *FunnelHandler) Close() error {
+	if f.closed.Swap(true) {
+		return nil
+	}
+
+	if s, ok := f.inner.(Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Handle forwards the record to the inner handler and updates the statistics.
+func (p *funnelProxy) Handle(ctx context.Context, r *Record) error {
+	f := p.funnel
+	if f.closed.Load() {
+		return ErrHandlerClosed
+	}
+
+	var source string
+	if ctx != nil {
+		source, _ = ctx.Value(funnelSourceKey{}).(string)
+	}
+
+	f.records.Add(1)
+	f.mu.Lock()
+	f.sources[source]++
+	f.mu.Unlock()
+
+	if err := p.inner.Handle(ctx, forwardedRecord(p.inner, r)); err != nil {
+		f.errors.Add(1)
+		return err
+	}
+
+	return nil
+}
+
+// Enabled reports whether the inner handler is enabled for level.
+// It reports false for every level once the funnel is closed.
+func (p *funnelProxy) Enabled(level LogLevel) bool {
+	return !p.funnel.closed.Load() && p.inner.Enabled(level)
+}
+
+// HandlerState returns the inner handler's state.
+func (p *funnelProxy) HandlerState() HandlerState {
+	return p.inner.HandlerState()
+}
+
+// Features returns the inner handler's features.
+func (p *funnelProxy) Features() HandlerFeatures {
+	return p.inner.Features()
+}
+
+// WithAttrs returns a proxy whose inner handler has the key-value pairs added.
+// It returns the original proxy if the inner handler does not implement Chainer.
+func (p *funnelProxy) WithAttrs(keyValues []any) Chainer {
+	ch, ok := p.inner.(Chainer)
+	if !ok {
+		return p
+	}
+
+	return &funnelProxy{funnel: p.funnel, inner: ch.WithAttrs(keyValues)}
+}
+
+// WithGroup returns a proxy whose inner handler starts the group.
+// It returns the original proxy if the inner handler does not implement Chainer.
+func (p *funnelProxy) WithGroup(name string) Chainer {
+	ch, ok := p.inner.(Chainer)
+	if !ok {
+		return p
+	}
+
+	return &funnelProxy{funnel: p.funnel, inner: ch.WithGroup(name)}
+}
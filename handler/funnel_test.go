@@ -0,0 +1,128 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// failingHandler is a recordingHandler whose Handle always fails.
+type failingHandler struct {
+	recordingHandler
+}
+
+func (h *failingHandler) Handle(context.Context, *handler.Record) error {
+	return errors.New("write failed")
+}
+
+func TestNewFunnelHandler_NilInner(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewFunnelHandler(nil) did not panic")
+		}
+	}()
+	handler.NewFunnelHandler(nil)
+}
+
+func TestFunnelHandler_Attribution(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{}
+	funnel, first := handler.NewFunnelHandler(inner)
+	proxies := []handler.Handler{first, funnel.Proxy(), funnel.Proxy()}
+
+	var wg sync.WaitGroup
+	for i, p := range proxies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := handler.WithFunnelSource(context.Background(), fmt.Sprintf("svc%d", i))
+			for j := range i + 1 {
+				_ = p.Handle(ctx, newRecord(handler.InfoLevel, fmt.Sprintf("svc%d-%d", i, j)))
+			}
+		}()
+	}
+	wg.Wait()
+	_ = first.Handle(context.Background(), newRecord(handler.InfoLevel, "anonymous"))
+
+	if got := len(inner.Messages()); got != 7 {
+		t.Errorf("inner received %d records, want 7", got)
+	}
+
+	stats := funnel.Stats()
+	if stats.Records != 7 || stats.Errors != 0 {
+		t.Errorf("Stats() = %d records, %d errors; want 7, 0", stats.Records, stats.Errors)
+	}
+	want := map[string]uint64{"svc0": 1, "svc1": 2, "svc2": 3, "": 1}
+	for source, n := range want {
+		if stats.Sources[source] != n {
+			t.Errorf("Sources[%q] = %d, want %d", source, stats.Sources[source], n)
+		}
+	}
+
+	// The snapshot is independent of the funnel
+	stats.Sources["svc0"] = 100
+	if got := funnel.Stats().Sources["svc0"]; got != 1 {
+		t.Errorf("Sources[svc0] after modifying snapshot = %d, want 1", got)
+	}
+}
+
+func TestFunnelHandler_Caller(t *testing.T) {
+	t.Parallel()
+
+	testForwardedCaller(t, func(inner handler.Handler) handler.Handler {
+		_, proxy := handler.NewFunnelHandler(inner)
+		return proxy
+	})
+}
+
+func TestFunnelHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	funnel, proxy := handler.NewFunnelHandler(&failingHandler{})
+
+	if err := proxy.Handle(context.Background(), newRecord(handler.InfoLevel, "msg")); err == nil {
+		t.Error("Handle() error = nil, want inner error")
+	}
+	if stats := funnel.Stats(); stats.Records != 1 || stats.Errors != 1 {
+		t.Errorf("Stats() = %d records, %d errors; want 1, 1", stats.Records, stats.Errors)
+	}
+}
+
+func TestFunnelHandler_Close(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{}
+	funnel, proxy := handler.NewFunnelHandler(inner)
+	child := proxy.(handler.Chainer).WithGroup("g")
+
+	if !child.Enabled(handler.InfoLevel) {
+		t.Fatal("Enabled() before Close = false, want true")
+	}
+
+	if err := funnel.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := funnel.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	if child.Enabled(handler.ErrorLevel) {
+		t.Error("Enabled() after Close = true, want false")
+	}
+	if err := proxy.Handle(context.Background(), newRecord(handler.ErrorLevel, "late")); !errors.Is(err, handler.ErrHandlerClosed) {
+		t.Errorf("Handle() after Close error = %v, want ErrHandlerClosed", err)
+	}
+	if len(inner.Messages()) != 0 {
+		t.Errorf("inner received %v after Close", inner.Messages())
+	}
+	if inner.syncs != 1 {
+		t.Errorf("inner synced %d times, want 1", inner.syncs)
+	}
+}
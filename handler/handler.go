@@ -22,6 +22,7 @@ package handler
 import (
 	"context"
 	"io"
+	"sync/atomic"
 	"time"
 )
 
@@ -30,7 +31,8 @@ type HandlerState interface {
 	// CallerEnabled returns whether caller information should be included.
 	CallerEnabled() bool
 
-	// TraceEnabled returns whether stack traces should be included for error-level logs.
+	// TraceEnabled returns whether stack traces should be included for records
+	// at or above the handler's trace level (ERROR by default).
 	TraceEnabled() bool
 
 	// CallerSkip returns the current caller skip value.
@@ -164,6 +166,17 @@ type Syncer interface {
 	Sync() error
 }
 
+// RawWriter writes pre-rendered log lines straight to the output, bypassing
+// attribute normalization and formatting. It is an escape hatch for
+// high-volume logs, such as access logs, that are already formatted.
+type RawWriter interface {
+	Handler
+
+	// WriteRaw writes payload as is if level is enabled. The payload should
+	// be a complete line, including the trailing newline.
+	WriteRaw(level LogLevel, payload []byte) error
+}
+
 // Record represents a single log entry with structured attributes.
 type Record struct {
 	// Time is the timestamp of the log entry.
@@ -186,3 +199,47 @@ type Record struct {
 	// It will be used for loggers that support source location natively.
 	Skip int
 }
+
+// IsZero reports whether r looks uninitialized: no time, no message,
+// the zero level and no key-value pairs. The logger never passes such a
+// record to a handler, so handlers reject it with ErrZeroRecord.
+func (r *Record) IsZero() bool {
+	return r.Time.IsZero() && r.Message == "" && r.Level == 0 && len(r.KeyValues) == 0
+}
+
+// DefaultMaxKeyValuesPerRecord is the default limit on key-value pairs per record.
+// Records with more pairs usually indicate a programming error, such as a loop
+// accidentally appending into the same slice.
+const DefaultMaxKeyValuesPerRecord = 256
+
+// Keys of the fields added to a record whose key-value pairs were truncated.
+const (
+	TruncatedKey     = "_truncated" // Always true
+	KeyValueCountKey = "_kv_count"  // Original number of pairs
+)
+
+// TruncatedAttrsKey is the key of the field added to a record whose pairs
+// were cut by a logger's attribute limit. Its value is the number of pairs
+// dropped.
+const TruncatedAttrsKey = "_truncated_attrs"
+
+// maxKeyValuesPerRecord holds the current limit; non-positive disables it.
+var maxKeyValuesPerRecord atomic.Int64
+
+func init() {
+	maxKeyValuesPerRecord.Store(DefaultMaxKeyValuesPerRecord)
+}
+
+// MaxKeyValuesPerRecord returns the maximum number of key-value pairs
+// forwarded per record. Zero means no limit.
+func MaxKeyValuesPerRecord() int {
+	return int(maxKeyValuesPerRecord.Load())
+}
+
+// SetMaxKeyValuesPerRecord sets the maximum number of key-value pairs
+// forwarded per record. When a record exceeds it, only the first n pairs are
+// forwarded, followed by TruncatedKey and KeyValueCountKey fields.
+// A non-positive n disables the limit. It is safe for concurrent use.
+func SetMaxKeyValuesPerRecord(n int) {
+	maxKeyValuesPerRecord.Store(int64(max(n, 0)))
+}
@@ -0,0 +1,82 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestSetMaxKeyValuesPerRecord(t *testing.T) {
+	// Not parallel: modifies package-level state.
+	t.Cleanup(func() { handler.SetMaxKeyValuesPerRecord(handler.DefaultMaxKeyValuesPerRecord) })
+
+	if got := handler.MaxKeyValuesPerRecord(); got != handler.DefaultMaxKeyValuesPerRecord {
+		t.Errorf("MaxKeyValuesPerRecord() = %d, want default %d", got, handler.DefaultMaxKeyValuesPerRecord)
+	}
+
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{10, 10},
+		{0, 0},
+		{-5, 0},
+		{1000, 1000},
+	}
+
+	for _, tt := range tests {
+		handler.SetMaxKeyValuesPerRecord(tt.n)
+		if got := handler.MaxKeyValuesPerRecord(); got != tt.want {
+			t.Errorf("after SetMaxKeyValuesPerRecord(%d): MaxKeyValuesPerRecord() = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRecord_IsZero(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		r    handler.Record
+		want bool
+	}{
+		{"zero", handler.Record{}, true},
+		{"pc only", handler.Record{PC: 1, Skip: 2}, true},
+		{"time", handler.Record{Time: time.Now()}, false},
+		{"message", handler.Record{Message: "msg"}, false},
+		{"level", handler.Record{Level: handler.InfoLevel}, false},
+		{"key values", handler.Record{KeyValues: []any{"k", "v"}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.IsZero(); got != tt.want {
+			t.Errorf("%s: IsZero() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHandlers_RejectZeroRecord(t *testing.T) {
+	t.Parallel()
+
+	_, capture := handler.NewTestCapture(handler.CaptureFormatText)
+	memory, _ := handler.NewMemoryHandler(1024)
+	ring, _ := handler.NewRingBufferHandler(&recordingHandler{}, 4)
+
+	handlers := map[string]handler.Handler{
+		"capture":    capture,
+		"memory":     memory,
+		"ringbuffer": ring,
+	}
+
+	for name, h := range handlers {
+		if err := h.Handle(context.Background(), &handler.Record{}); !errors.Is(err, handler.ErrZeroRecord) {
+			t.Errorf("%s: Handle(zero record) error = %v, want ErrZeroRecord", name, err)
+		}
+		if err := h.Handle(context.Background(), newRecord(handler.InfoLevel, "msg")); err != nil {
+			t.Errorf("%s: Handle(valid record) error = %v", name, err)
+		}
+	}
+}
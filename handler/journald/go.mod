@@ -0,0 +1,10 @@
+module github.com/balinomad/go-unilog/handler/journald
+
+go 1.24
+
+require (
+	github.com/balinomad/go-caller v1.0.0
+	github.com/balinomad/go-unilog v0.0.0-20251121032946-11d98d413577
+)
+
+require github.com/balinomad/go-atomicwriter v1.0.1 // indirect
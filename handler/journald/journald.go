@@ -0,0 +1,611 @@
+// Package journald provides a handler writing to the systemd journal with
+// its native protocol, so that attributes become searchable journal fields.
+package journald
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/balinomad/go-caller"
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// DefaultSocketPath is the path of the journald native protocol socket.
+const DefaultSocketPath = "/run/systemd/journal/socket"
+
+// journaldOptions holds configuration for the journald handler.
+type journaldOptions struct {
+	base       *handler.BaseOptions
+	socketPath string
+	identifier string
+}
+
+// JournaldOption configures the journald handler creation.
+type JournaldOption func(*journaldOptions) error
+
+// WithLevel sets the minimum log level.
+func WithLevel(level handler.LogLevel) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithLevel(level)(o.base)
+	}
+}
+
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR only.
+// The minimum level becomes the lowest enabled level.
+func WithEnabledLevels(levels ...handler.LogLevel) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithEnabledLevels(levels...)(o.base)
+	}
+}
+
+// WithOutput sets the writer used instead of the journal when it is not
+// available, e.g. when not running under systemd. The default is os.Stderr.
+func WithOutput(w io.Writer) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithOutput(w)(o.base)
+	}
+}
+
+// WithSeparator sets the separator for group key prefixes.
+func WithSeparator(separator string) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithSeparator(separator)(o.base)
+	}
+}
+
+// WithCaller enables or disables the CODE_FILE, CODE_LINE and CODE_FUNC
+// fields. The default value is false.
+func WithCaller(enabled bool) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithCaller(enabled)(o.base)
+	}
+}
+
+// WithCallerShortPath trims the caller file to its package directory and
+// file name, e.g. "server/http.go", instead of the absolute path.
+// The default value is false.
+func WithCallerShortPath(enabled bool) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithCallerShortPath(enabled)(o.base)
+	}
+}
+
+// WithCallerLevel sets the minimum level that carries caller information
+// when caller reporting is enabled. The default value is handler.MinLevel.
+func WithCallerLevel(level handler.LogLevel) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithCallerLevel(level)(o.base)
+	}
+}
+
+// WithDebugFriendlyDefaults drops records below INFO, reports the caller
+// from WARN and adds a stack trace from ERROR.
+// See handler.WithDebugFriendlyDefaults.
+func WithDebugFriendlyDefaults() JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithDebugFriendlyDefaults()(o.base)
+	}
+}
+
+// WithTrace enables a STACK field for ERROR and above, or for the level
+// set with WithTraceLevel.
+func WithTrace(enabled bool) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithTrace(enabled)(o.base)
+	}
+}
+
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. The default value is handler.DefaultTraceLevel.
+func WithTraceLevel(level handler.LogLevel) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithTraceLevel(level)(o.base)
+	}
+}
+
+// WithMaxStackDepth limits stack traces to the top n frames closest to the
+// log call site. The default value is handler.DefaultMaxStackDepth.
+func WithMaxStackDepth(n int) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithMaxStackDepth(n)(o.base)
+	}
+}
+
+// WithFieldValidator registers fn to check every field key before it is
+// converted to a journal field name.
+func WithFieldValidator(fn func(key string) error) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithFieldValidator(fn)(o.base)
+	}
+}
+
+// WithKeyCase normalizes attribute keys and group names to the given case
+// before they are converted to journal field names, e.g. handler.KeyCaseSnake
+// turns "requestID" into REQUEST_ID rather than REQUESTID. The default value
+// is handler.KeyCaseAsIs.
+func WithKeyCase(c handler.KeyCase) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithKeyCase(c)(o.base)
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record.
+func WithMetricsProvider(p handler.MetricsProvider) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithMetricsProvider(p)(o.base)
+	}
+}
+
+// WithJSONValues renders map, slice and array attribute values as compact
+// JSON (e.g. {"a":1}) instead of Go syntax (map[a:1]). The default value is false.
+func WithJSONValues(enabled bool) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithJSONValues(enabled)(o.base)
+	}
+}
+
+// WithRedactPattern replaces every match of re in messages and string values
+// with replacement, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString. Patterns run on every record, so keep
+// them few. See handler.WithRedactPattern.
+func WithRedactPattern(re *regexp.Regexp, replacement string) JournaldOption {
+	return func(o *journaldOptions) error {
+		return handler.WithRedactPattern(re, replacement)(o.base)
+	}
+}
+
+// WithIdentifier sets the SYSLOG_IDENTIFIER field, which journalctl -t
+// filters on. The default is the base name of the executable.
+// Empty omits the field.
+func WithIdentifier(id string) JournaldOption {
+	return func(o *journaldOptions) error {
+		o.identifier = id
+		return nil
+	}
+}
+
+// WithSocketPath sets the path of the journal socket.
+// The default value is DefaultSocketPath.
+func WithSocketPath(path string) JournaldOption {
+	return func(o *journaldOptions) error {
+		if path == "" {
+			return handler.NewOptionApplyError("WithSocketPath", errors.New("socket path cannot be empty"))
+		}
+		o.socketPath = path
+		return nil
+	}
+}
+
+// journal is a connection to the journal socket, shared by derived handlers.
+type journal struct {
+	conn *net.UnixConn
+	addr *net.UnixAddr
+}
+
+// journaldHandler writes records to the systemd journal.
+type journaldHandler struct {
+	base       *handler.BaseHandler
+	journal    *journal // nil => write text to the output instead
+	identifier string
+	fields     []field // Attributes added with WithAttrs, names already converted
+
+	// Cached from base for lock-free hot-path
+	withCaller bool
+	withTrace  bool
+}
+
+// field is a journal field.
+type field struct {
+	name  string
+	value string
+}
+
+// Ensure journaldHandler implements the following interfaces.
+var (
+	_ handler.Handler        = (*journaldHandler)(nil)
+	_ handler.Chainer        = (*journaldHandler)(nil)
+	_ handler.Configurable   = (*journaldHandler)(nil)
+	_ handler.CallerAdjuster = (*journaldHandler)(nil)
+	_ handler.FeatureToggler = (*journaldHandler)(nil)
+	_ handler.MutableConfig  = (*journaldHandler)(nil)
+)
+
+// priorityMapper maps unilog log levels to syslog priorities. FATAL and PANIC
+// stay at crit: emerg is broadcast to every terminal, and alert is meant for
+// conditions needing immediate operator action.
+var priorityMapper = handler.NewLevelMapper(
+	7, // Trace: debug
+	7, // Debug: debug
+	6, // Info: info
+	4, // Warn: warning
+	3, // Error: err
+	2, // Critical: crit
+	2, // Fatal: crit
+	2, // Panic: crit
+)
+
+// reservedFields are the fields set by the handler itself. Attributes whose
+// name collides with one are prefixed with "ATTR_".
+var reservedFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+	"STACK":             true,
+}
+
+// New creates a new handler.Handler instance writing to the systemd journal.
+// If the journal socket does not exist or cannot be reached, e.g. when not
+// running under systemd, records are written as text lines to the output
+// set with WithOutput instead.
+func New(opts ...JournaldOption) (handler.Handler, error) {
+	o := &journaldOptions{
+		base: &handler.BaseOptions{
+			Level:       handler.DefaultLevel,
+			TraceLevel:  handler.DefaultTraceLevel,
+			CallerLevel: handler.MinLevel,
+			Output:      os.Stderr,
+		},
+		socketPath: DefaultSocketPath,
+		identifier: filepath.Base(os.Args[0]),
+	}
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	base, err := handler.NewBaseHandler(o.base)
+	if err != nil {
+		return nil, err
+	}
+
+	return &journaldHandler{
+		base:       base,
+		journal:    openJournal(o.socketPath),
+		identifier: o.identifier,
+		withCaller: base.CallerEnabled(),
+		withTrace:  base.TraceEnabled(),
+	}, nil
+}
+
+// openJournal returns a connection to the journal socket at path,
+// or nil if there is no journal to connect to.
+func openJournal(path string) *journal {
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+
+	conn, err := listenUnixgram()
+	if err != nil {
+		return nil
+	}
+
+	return &journal{conn: conn, addr: &net.UnixAddr{Name: path, Net: "unixgram"}}
+}
+
+// Handle implements the handler.Handler interface for the journal.
+// If sending to the journal fails, the record is written to the output.
+func (h *journaldHandler) Handle(ctx context.Context, r *handler.Record) error {
+	if r.IsZero() {
+		return handler.ErrZeroRecord
+	}
+	if !h.base.EnabledContext(ctx, r.Level) {
+		return nil
+	}
+
+	keyValues := h.base.ValidateKeys(r.KeyValues)
+
+	msg := h.base.RedactString(r.Message)
+	if m, ok := h.base.HumanMessage(r.Message, keyValues); ok {
+		msg = m
+	}
+
+	fields := make([]field, 0, 3+len(h.fields)+len(keyValues)/2+4)
+	fields = append(fields,
+		field{"MESSAGE", msg},
+		field{"PRIORITY", strconv.Itoa(priorityMapper.Map(r.Level))},
+	)
+	if h.identifier != "" {
+		fields = append(fields, field{"SYSLOG_IDENTIFIER", h.identifier})
+	}
+	fields = append(fields, h.fields...)
+	fields = h.appendAttrs(fields, keyValues)
+
+	// Only compute caller if enabled
+	if h.withCaller && r.PC != 0 {
+		c := caller.NewFromPC(r.PC)
+		fields = append(fields,
+			field{"CODE_FILE", h.base.CallerPath(c.File())},
+			field{"CODE_LINE", strconv.Itoa(c.Line())},
+			field{"CODE_FUNC", c.FullFunction()},
+		)
+	}
+
+	// Only capture stack if enabled and at or above the trace level
+	if h.withTrace && r.Level >= h.base.TraceLevel() {
+		fields = append(fields, field{"STACK", handler.CaptureStack(0, h.base.MaxStackDepth())})
+	}
+
+	if h.journal != nil {
+		if err := h.journal.send(encodeNative(fields)); err == nil {
+			h.base.RecordHandled(r.Level)
+			return nil
+		}
+	}
+
+	if _, err := h.base.AtomicWriter().Write(encodeText(h.base.LevelName(r.Level), fields)); err != nil {
+		h.base.RecordError()
+		return err
+	}
+	h.base.RecordHandled(r.Level)
+
+	return nil
+}
+
+// Enabled checks if the given log level is enabled.
+func (h *journaldHandler) Enabled(level handler.LogLevel) bool {
+	return h.base.Enabled(level)
+}
+
+// HandlerState returns the underlying BaseHandler.
+func (h *journaldHandler) HandlerState() handler.HandlerState {
+	return h.base
+}
+
+// Features returns the supported HandlerFeatures.
+func (h *journaldHandler) Features() handler.HandlerFeatures {
+	return handler.NewHandlerFeatures(handler.FeatDynamicLevel | handler.FeatDynamicOutput)
+}
+
+// WithAttrs returns a new handler with the provided keyValues added to every record.
+// If keyValues is empty, the original handler is returned.
+func (h *journaldHandler) WithAttrs(keyValues []any) handler.Chainer {
+	keyValues = h.base.ValidateKeys(keyValues)
+
+	if len(keyValues) < 2 {
+		return h
+	}
+
+	clone := h.clone()
+	clone.fields = h.appendAttrs(h.fields[:len(h.fields):len(h.fields)], keyValues)
+
+	return clone
+}
+
+// WithGroup returns a handler that starts a group, if name is non-empty.
+func (h *journaldHandler) WithGroup(name string) handler.Chainer {
+	if name == "" {
+		return h
+	}
+
+	base, err := h.base.WithKeyPrefix(h.base.ConvertKey(name))
+	if err != nil {
+		return h
+	}
+
+	clone := h.clone()
+	clone.base = base
+
+	return clone
+}
+
+// SetLevel dynamically changes the minimum level of logs that will be processed.
+func (h *journaldHandler) SetLevel(level handler.LogLevel) error {
+	return h.base.SetLevel(level)
+}
+
+// SetOutput sets the writer used when the journal is not available.
+func (h *journaldHandler) SetOutput(w io.Writer) error {
+	return h.base.SetOutput(w)
+}
+
+// CallerSkip returns the current number of stack frames being skipped.
+func (h *journaldHandler) CallerSkip() int {
+	return h.base.CallerSkip()
+}
+
+// WithCaller returns a new handler with caller reporting enabled or disabled.
+// It returns the original handler if the enabled value is unchanged.
+func (h *journaldHandler) WithCaller(enabled bool) handler.FeatureToggler {
+	newBase := h.base.WithCaller(enabled)
+	if newBase == h.base {
+		return h
+	}
+
+	return h.withBase(newBase)
+}
+
+// WithTrace returns a new handler that enables or disables stack trace logging.
+// It returns the original handler if the enabled value is unchanged.
+func (h *journaldHandler) WithTrace(enabled bool) handler.FeatureToggler {
+	newBase := h.base.WithTrace(enabled)
+	if newBase == h.base {
+		return h
+	}
+
+	return h.withBase(newBase)
+}
+
+// WithLevel returns a new handler with a new minimum level applied.
+// It returns the original handler if the level value is unchanged.
+func (h *journaldHandler) WithLevel(level handler.LogLevel) handler.Configurable {
+	newBase, err := h.base.WithLevel(level)
+	if err != nil || newBase == h.base {
+		return h
+	}
+
+	return h.withBase(newBase)
+}
+
+// WithOutput returns a new handler with the fallback output set permanently.
+// It returns the original handler if the writer value is unchanged.
+func (h *journaldHandler) WithOutput(w io.Writer) handler.Configurable {
+	newBase, err := h.base.WithOutput(w)
+	if err != nil || newBase == h.base {
+		return h
+	}
+
+	return h.withBase(newBase)
+}
+
+// WithCallerSkip returns a new handler with the caller skip permanently adjusted.
+// It returns the original handler if the skip value is unchanged.
+func (h *journaldHandler) WithCallerSkip(skip int) handler.CallerAdjuster {
+	current := h.base.CallerSkip()
+	if skip == current {
+		return h
+	}
+
+	return h.WithCallerSkipDelta(skip - current)
+}
+
+// WithCallerSkipDelta returns a new handler with the caller skip altered by delta.
+// It returns the original handler if the delta value is zero.
+func (h *journaldHandler) WithCallerSkipDelta(delta int) handler.CallerAdjuster {
+	if delta == 0 {
+		return h
+	}
+
+	newBase, err := h.base.WithCallerSkipDelta(delta)
+	if err != nil {
+		return h
+	}
+
+	return h.withBase(newBase)
+}
+
+// clone returns a shallow copy of the handler.
+func (h *journaldHandler) clone() *journaldHandler {
+	clone := *h
+	return &clone
+}
+
+// withBase returns a copy of the handler using base, with the cached flags refreshed.
+func (h *journaldHandler) withBase(base *handler.BaseHandler) *journaldHandler {
+	clone := h.clone()
+	clone.base = base
+	clone.withCaller = base.CallerEnabled()
+	clone.withTrace = base.TraceEnabled()
+
+	return clone
+}
+
+// appendAttrs appends keyValues to fields, qualifying keys with the current
+// group prefix and converting them to journal field names.
+func (h *journaldHandler) appendAttrs(fields []field, keyValues []any) []field {
+	prefix := h.base.KeyPrefix()
+	sep := h.base.Separator()
+
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyValues[i])
+		}
+		if prefix != "" {
+			key = prefix + sep + key
+		}
+		fields = append(fields, field{fieldName(key), h.base.FormatValue(keyValues[i+1])})
+	}
+
+	return fields
+}
+
+// maxFieldNameLength is the longest field name journald accepts.
+const maxFieldNameLength = 64
+
+// fieldName converts key to a journal field name: uppercase ASCII letters,
+// digits and underscores, not starting with an underscore, which marks
+// trusted fields, or a digit. Keys colliding with a field set by the
+// handler are prefixed with "ATTR_".
+func fieldName(key string) string {
+	var sb strings.Builder
+	sb.Grow(len(key))
+	for _, c := range strings.ToUpper(key) {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+			sb.WriteRune(c)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+
+	name := strings.TrimLeft(sb.String(), "_")
+	switch {
+	case name == "":
+		name = "FIELD"
+	case name[0] >= '0' && name[0] <= '9':
+		name = "FIELD_" + name
+	case reservedFields[name]:
+		name = "ATTR_" + name
+	}
+
+	if len(name) > maxFieldNameLength {
+		name = name[:maxFieldNameLength]
+	}
+
+	return name
+}
+
+// encodeNative encodes fields in the journal native protocol. Values
+// containing a newline are sent as binary data prefixed with their length.
+func encodeNative(fields []field) []byte {
+	var b []byte
+	for _, f := range fields {
+		b = append(b, f.name...)
+		if strings.IndexByte(f.value, '\n') < 0 {
+			b = append(b, '=')
+			b = append(b, f.value...)
+		} else {
+			n := uint64(len(f.value))
+			b = append(b, '\n',
+				byte(n), byte(n>>8), byte(n>>16), byte(n>>24),
+				byte(n>>32), byte(n>>40), byte(n>>48), byte(n>>56))
+			b = append(b, f.value...)
+		}
+		b = append(b, '\n')
+	}
+
+	return b
+}
+
+// encodeText formats fields as a single text line for the fallback output:
+//
+//	[INFO] message NAME=value ...
+//
+// PRIORITY is conveyed by the level name; values with newlines are quoted.
+func encodeText(level string, fields []field) []byte {
+	var sb strings.Builder
+	sb.WriteString("[")
+	sb.WriteString(level)
+	sb.WriteString("] ")
+	sb.WriteString(fields[0].value)
+
+	for _, f := range fields[1:] {
+		if f.name == "PRIORITY" {
+			continue
+		}
+		sb.WriteString(" ")
+		sb.WriteString(f.name)
+		sb.WriteString("=")
+		if strings.IndexByte(f.value, '\n') < 0 {
+			sb.WriteString(f.value)
+		} else {
+			sb.WriteString(strconv.Quote(f.value))
+		}
+	}
+	sb.WriteString("\n")
+
+	return []byte(sb.String())
+}
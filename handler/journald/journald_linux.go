@@ -0,0 +1,43 @@
+//go:build linux
+
+package journald
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenUnixgram returns an unconnected datagram socket with an autobound
+// address. Sending to the journal's address on every write, rather than
+// connecting once, survives journald restarts.
+func listenUnixgram() (*net.UnixConn, error) {
+	return net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+}
+
+// send writes data to the journal as one datagram. Data too large for a
+// datagram is written to an unlinked file in /dev/shm whose descriptor is
+// passed instead, as the native protocol allows.
+func (j *journal) send(data []byte) error {
+	_, _, err := j.conn.WriteMsgUnix(data, nil, j.addr)
+	if err == nil || (!errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS)) {
+		return err
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "journald-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+
+	_, _, err = j.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), j.addr)
+	return err
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package journald
+
+import (
+	"errors"
+	"net"
+)
+
+// errNoJournal reports that the journal is only available on Linux.
+var errNoJournal = errors.New("journald is only available on Linux")
+
+// listenUnixgram always fails: outside Linux, records go to the output.
+func listenUnixgram() (*net.UnixConn, error) {
+	return nil, errNoJournal
+}
+
+// send always fails; it is never called since there is no journal.
+func (j *journal) send([]byte) error {
+	return errNoJournal
+}
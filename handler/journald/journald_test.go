@@ -0,0 +1,273 @@
+//go:build linux
+
+package journald_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+	"github.com/balinomad/go-unilog/handler/journald"
+)
+
+// listenJournal starts a fake journal socket and returns its path and connection.
+func listenJournal(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return path, conn
+}
+
+// readFields reads one datagram and decodes its native protocol fields.
+func readFields(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+
+	buf := make([]byte, 64*1024)
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("ReadMsgUnix() error = %v", err)
+	}
+
+	data := buf[:n]
+	if oobn > 0 {
+		data = readPassedFile(t, oob[:oobn])
+	}
+
+	fields := map[string]string{}
+	for len(data) > 0 {
+		eol := bytes.IndexByte(data, '\n')
+		if eol < 0 {
+			t.Fatalf("unterminated field %q", data)
+		}
+		line := string(data[:eol])
+		if name, value, ok := strings.Cut(line, "="); ok {
+			fields[name] = value
+			data = data[eol+1:]
+			continue
+		}
+		size := binary.LittleEndian.Uint64(data[eol+1 : eol+9])
+		fields[line] = string(data[eol+9 : eol+9+int(size)])
+		data = data[eol+9+int(size)+1:]
+	}
+
+	return fields
+}
+
+// readPassedFile returns the contents of the file whose descriptor was sent
+// in the control message oob.
+func readPassedFile(t *testing.T, oob []byte) []byte {
+	t.Helper()
+
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("ParseSocketControlMessage() = %v, %v", msgs, err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("ParseUnixRights() = %v, %v", fds, err)
+	}
+
+	f := os.NewFile(uintptr(fds[0]), "journal")
+	defer f.Close()
+
+	var data bytes.Buffer
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := data.ReadFrom(f); err != nil {
+		t.Fatal(err)
+	}
+
+	return data.Bytes()
+}
+
+func TestHandle_Native(t *testing.T) {
+	path, conn := listenJournal(t)
+
+	h, err := journald.New(
+		journald.WithSocketPath(path),
+		journald.WithIdentifier("app"),
+		journald.WithLevel(handler.DebugLevel),
+		journald.WithCaller(true),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	h = h.(handler.Chainer).WithGroup("req").WithAttrs([]any{"http.route", "/api"}).(handler.Handler)
+
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	r := &handler.Record{
+		Time:      time.Now(),
+		Level:     handler.WarnLevel,
+		PC:        pcs[0],
+		Message:   "line one\nline two",
+		KeyValues: []any{"user-id", 42},
+	}
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	got := readFields(t, conn)
+	want := map[string]string{
+		"MESSAGE":           "line one\nline two",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "app",
+		"REQ_HTTP_ROUTE":    "/api",
+		"REQ_USER_ID":       "42",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("field %s = %q, want %q", name, got[name], value)
+		}
+	}
+	if got["CODE_FILE"] == "" || got["CODE_LINE"] == "" || got["CODE_FUNC"] == "" {
+		t.Errorf("missing caller fields in %v", got)
+	}
+}
+
+func TestHandle_FieldNames(t *testing.T) {
+	path, conn := listenJournal(t)
+
+	h, err := journald.New(journald.WithSocketPath(path), journald.WithIdentifier(""))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r := &handler.Record{
+		Time:      time.Now(),
+		Level:     handler.ErrorLevel,
+		Message:   "failed",
+		KeyValues: []any{"_hidden", "x", "message", "shadow", "1st", true, "priority", 1, strings.Repeat("a", 70), "long"},
+	}
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	got := readFields(t, conn)
+	want := map[string]string{
+		"MESSAGE":               "failed",
+		"PRIORITY":              "3",
+		"HIDDEN":                "x",
+		"ATTR_MESSAGE":          "shadow",
+		"ATTR_PRIORITY":         "1",
+		"FIELD_1ST":             "true",
+		strings.Repeat("A", 64): "long",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("field %s = %q, want %q", name, got[name], value)
+		}
+	}
+	if _, ok := got["SYSLOG_IDENTIFIER"]; ok {
+		t.Error("SYSLOG_IDENTIFIER set with empty identifier")
+	}
+}
+
+func TestHandle_Priority(t *testing.T) {
+	path, conn := listenJournal(t)
+
+	h, err := journald.New(journald.WithSocketPath(path), journald.WithLevel(handler.TraceLevel))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		level handler.LogLevel
+		want  string
+	}{
+		{handler.TraceLevel, "7"},
+		{handler.DebugLevel, "7"},
+		{handler.InfoLevel, "6"},
+		{handler.WarnLevel, "4"},
+		{handler.ErrorLevel, "3"},
+		{handler.CriticalLevel, "2"},
+		{handler.FatalLevel, "2"},
+		{handler.PanicLevel, "2"},
+	}
+	for _, tt := range tests {
+		r := &handler.Record{Time: time.Now(), Level: tt.level, Message: "m"}
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle(%v) error = %v", tt.level, err)
+		}
+		if got := readFields(t, conn)["PRIORITY"]; got != tt.want {
+			t.Errorf("PRIORITY for %v = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestHandle_LargeRecord(t *testing.T) {
+	if _, err := os.Stat("/dev/shm"); err != nil {
+		t.Skip("/dev/shm not available")
+	}
+
+	path, conn := listenJournal(t)
+
+	h, err := journald.New(journald.WithSocketPath(path))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	msg := strings.Repeat("x", 4<<20)
+	r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: msg}
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if got := readFields(t, conn)["MESSAGE"]; got != msg {
+		t.Errorf("MESSAGE length = %d, want %d", len(got), len(msg))
+	}
+}
+
+func TestHandle_Fallback(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := journald.New(
+		journald.WithSocketPath(filepath.Join(t.TempDir(), "missing")),
+		journald.WithIdentifier("app"),
+		journald.WithOutput(&buf),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r := &handler.Record{
+		Time:      time.Now(),
+		Level:     handler.InfoLevel,
+		Message:   "started",
+		KeyValues: []any{"port", 8080},
+	}
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	want := "[INFO] started SYSLOG_IDENTIFIER=app PORT=8080\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestNew_EmptySocketPath(t *testing.T) {
+	if _, err := journald.New(journald.WithSocketPath("")); err == nil {
+		t.Error("New() error = nil, want error")
+	}
+}
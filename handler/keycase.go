@@ -0,0 +1,159 @@
+package handler
+
+import "strings"
+
+// KeyCase is the casing attribute keys are normalized to (see WithKeyCase).
+type KeyCase int
+
+const (
+	KeyCaseAsIs  KeyCase = iota // Keep keys as given
+	KeyCaseSnake                // request_id
+	KeyCaseCamel                // requestId
+	KeyCaseLower                // requestid
+)
+
+// String returns the name of the key case.
+func (c KeyCase) String() string {
+	switch c {
+	case KeyCaseAsIs:
+		return "as-is"
+	case KeyCaseSnake:
+		return "snake"
+	case KeyCaseCamel:
+		return "camel"
+	case KeyCaseLower:
+		return "lower"
+	default:
+		return "unknown"
+	}
+}
+
+// isValid reports whether c is one of the defined key cases.
+func (c KeyCase) isValid() bool {
+	return c >= KeyCaseAsIs && c <= KeyCaseLower
+}
+
+// Convert returns key in the key case. Snake and camel case split key into
+// words at ASCII case changes and at every character other than ASCII
+// letters and digits, so "requestID", "request-id" and "RequestId" all
+// become "request_id" or "requestId". Leading underscores are kept. Lower
+// case only lowers ASCII letters. Keys already in the key case are returned
+// without allocating.
+func (c KeyCase) Convert(key string) string {
+	switch c {
+	case KeyCaseSnake:
+		if isSnakeKey(key) {
+			return key
+		}
+		return joinKeyWords(key, false)
+	case KeyCaseCamel:
+		if isCamelKey(key) {
+			return key
+		}
+		return joinKeyWords(key, true)
+	case KeyCaseLower:
+		return strings.ToLower(key)
+	default:
+		return key
+	}
+}
+
+// isSnakeKey reports whether key has only lowercase ASCII letters, digits
+// and underscores.
+func isSnakeKey(key string) bool {
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !isLowerByte(c) && !isDigitByte(c) && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// isCamelKey reports whether key has only ASCII letters and digits, starts
+// lowercase and has no run of uppercase letters.
+func isCamelKey(key string) bool {
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case isUpperByte(c):
+			if i == 0 || isUpperByte(key[i-1]) {
+				return false
+			}
+		case !isLowerByte(c) && !isDigitByte(c):
+			return false
+		}
+	}
+	return true
+}
+
+// joinKeyWords splits key into lowercase words and joins them with
+// underscores, or in camel case if camel is true. It returns key unchanged
+// if it has no words.
+func joinKeyWords(key string, camel bool) string {
+	orig := key
+	var sb strings.Builder
+	sb.Grow(len(key) + 4)
+
+	// Keep leading underscores, e.g. of "_id"
+	rest := strings.TrimLeft(key, "_")
+	sb.WriteString(key[:len(key)-len(rest)])
+	key = rest
+
+	words := 0
+	inWord := false
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !isUpperByte(c) && !isLowerByte(c) && !isDigitByte(c) && c < 0x80 {
+			inWord = false
+			continue
+		}
+
+		// Start a new word after a separator, on a case change ("userId")
+		// or at the end of an acronym ("HTTPServer")
+		newWord := !inWord
+		if inWord && isUpperByte(c) {
+			prev := key[i-1]
+			lowerNext := i+1 < len(key) && isLowerByte(key[i+1])
+			newWord = !isUpperByte(prev) || lowerNext
+		}
+
+		if newWord {
+			if words > 0 && !camel {
+				sb.WriteByte('_')
+			}
+			words++
+		}
+		inWord = true
+
+		if newWord && camel && words > 1 {
+			sb.WriteByte(toUpperByte(c))
+		} else {
+			sb.WriteByte(toLowerByte(c))
+		}
+	}
+
+	if words == 0 {
+		return orig
+	}
+
+	return sb.String()
+}
+
+func isUpperByte(c byte) bool { return c >= 'A' && c <= 'Z' }
+func isLowerByte(c byte) bool { return c >= 'a' && c <= 'z' }
+func isDigitByte(c byte) bool { return c >= '0' && c <= '9' }
+
+func toUpperByte(c byte) byte {
+	if isLowerByte(c) {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+func toLowerByte(c byte) byte {
+	if isUpperByte(c) {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,72 @@
+package handler_test
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestKeyCase_Convert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key   string
+		snake string
+		camel string
+		lower string
+	}{
+		{"request_id", "request_id", "requestId", "request_id"},
+		{"requestID", "request_id", "requestId", "requestid"},
+		{"RequestId", "request_id", "requestId", "requestid"},
+		{"request-id", "request_id", "requestId", "request-id"},
+		{"HTTPServer", "http_server", "httpServer", "httpserver"},
+		{"user.name", "user_name", "userName", "user.name"},
+		{"_id", "_id", "_id", "_id"},
+		{"status", "status", "status", "status"},
+		{"retry2Count", "retry2_count", "retry2Count", "retry2count"},
+		{"--", "--", "--", "--"},
+	}
+
+	for _, tt := range tests {
+		for c, want := range map[handler.KeyCase]string{
+			handler.KeyCaseAsIs:  tt.key,
+			handler.KeyCaseSnake: tt.snake,
+			handler.KeyCaseCamel: tt.camel,
+			handler.KeyCaseLower: tt.lower,
+		} {
+			if got := c.Convert(tt.key); got != want {
+				t.Errorf("%v.Convert(%q) = %q, want %q", c, tt.key, got, want)
+			}
+		}
+	}
+}
+
+func TestWithKeyCase(t *testing.T) {
+	t.Parallel()
+
+	if err := handler.WithKeyCase(handler.KeyCase(42))(&handler.BaseOptions{}); err == nil {
+		t.Error("WithKeyCase() with unknown key case should return error")
+	}
+
+	opts := &handler.BaseOptions{Output: io.Discard}
+	if err := handler.WithKeyCase(handler.KeyCaseSnake)(opts); err != nil {
+		t.Fatalf("WithKeyCase() error = %v", err)
+	}
+	h := newHandler(t, opts)
+
+	in := []any{"requestID", 1, 2, "x", "status", "ok"}
+	want := []any{"request_id", 1, 2, "x", "status", "ok"}
+	if got := h.Clone().ValidateKeys(in); !slices.Equal(got, want) {
+		t.Errorf("ValidateKeys(%v) = %v, want %v", in, got, want)
+	}
+	if in[0] != "requestID" {
+		t.Errorf("ValidateKeys() modified its input: %v", in)
+	}
+
+	same := []any{"request_id", 1}
+	if got := h.ValidateKeys(same); &got[0] != &same[0] {
+		t.Error("ValidateKeys() should return its input when every key is in the key case")
+	}
+}
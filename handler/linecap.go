@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+// Markers ending a line shortened by TruncateLine.
+const (
+	jsonTruncatedMarker = `"` + TruncatedKey + `":true`
+	textTruncatedMarker = " " + TruncatedKey + "=true"
+)
+
+// capLines returns w wrapped to truncate every write to n bytes, or w itself
+// if n is not positive.
+func capLines(w io.Writer, n int) io.Writer {
+	if n <= 0 {
+		return w
+	}
+
+	return &lineCapWriter{w: w, n: n}
+}
+
+// lineCapWriter shortens every write to at most n bytes before passing it
+// to w. Handlers write one rendered record per call.
+type lineCapWriter struct {
+	w io.Writer
+	n int
+}
+
+// Write writes p, truncated with TruncateLine, to the underlying writer.
+// It reports len(p) written on success, so callers do not see a short write.
+func (lw *lineCapWriter) Write(p []byte) (int, error) {
+	if _, err := lw.w.Write(TruncateLine(p, lw.n)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Sync flushes the underlying writer if it implements Sync or Flush,
+// which the wrapper would otherwise hide from AtomicWriter.Sync.
+func (lw *lineCapWriter) Sync() error {
+	switch w := lw.w.(type) {
+	case interface{ Sync() error }:
+		return w.Sync()
+	case interface{ Flush() error }:
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// TruncateLine returns line shortened to at most n bytes, keeping its
+// trailing newline, if any. It returns line itself if it fits.
+//
+// A line holding a JSON object keeps as many of its leading members as fit
+// and ends with a "_truncated":true member, so it stays valid JSON; it is
+// never shortened below {"_truncated":true}. Other lines are cut at a UTF-8
+// character boundary and end with " _truncated=true" when that fits.
+func TruncateLine(line []byte, n int) []byte {
+	if n <= 0 || len(line) <= n {
+		return line
+	}
+
+	body, nl := line, []byte(nil)
+	if bytes.HasSuffix(body, []byte("\n")) {
+		body, nl = body[:len(body)-1], []byte("\n")
+	}
+	room := n - len(nl)
+
+	var out []byte
+	if isJSONObject(body) {
+		keep := 1 // Just the opening brace
+		for _, end := range jsonMemberEnds(body) {
+			if end+len(",")+len(jsonTruncatedMarker)+len("}") > room {
+				break
+			}
+			keep = end
+		}
+		out = append(out, body[:keep]...)
+		if keep > 1 {
+			out = append(out, ',')
+		}
+		out = append(out, jsonTruncatedMarker...)
+		out = append(out, '}')
+	} else {
+		marker := textTruncatedMarker
+		if room < 2*len(marker) {
+			marker = ""
+		}
+		cut := max(room-len(marker), 0)
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		out = append(out, body[:cut]...)
+		out = append(out, marker...)
+	}
+
+	return append(out, nl...)
+}
+
+// isJSONObject reports whether b looks like a single JSON object.
+func isJSONObject(b []byte) bool {
+	return len(b) >= 2 && b[0] == '{' && b[len(b)-1] == '}'
+}
+
+// jsonMemberEnds returns the offsets just past each top-level member of the
+// JSON object b, i.e. of each separating comma and of the closing brace.
+func jsonMemberEnds(b []byte) []int {
+	var ends []int
+	depth, inString, escaped := 0, false, false
+	for i, c := range b {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				ends = append(ends, i)
+			}
+		case c == ',' && depth == 1:
+			ends = append(ends, i)
+		}
+	}
+
+	return ends
+}
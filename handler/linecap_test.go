@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestTruncateLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		line string
+		n    int
+		want string
+	}{
+		{"fits", `{"a":1}` + "\n", 8, `{"a":1}` + "\n"},
+		{"no limit", "abcdef", 0, "abcdef"},
+		{"json drops trailing members", `{"msg":"hi","a":"xxxxxxxx","b":2}` + "\n", 31, `{"msg":"hi","_truncated":true}` + "\n"},
+		{"json keeps nested member intact", `{"g":{"x":1,"y":2},"b":"zzzzzzzzzzzzzzzzzzzz"}`, 40, `{"g":{"x":1,"y":2},"_truncated":true}`},
+		{"json comma in string", `{"m":"a,b","c":"dddddddddddddddddddd"}`, 32, `{"m":"a,b","_truncated":true}`},
+		{"json nothing fits", `{"msg":"a long message"}`, 10, `{"_truncated":true}`},
+		{"text", "level=INFO msg=hello key=value extra=data\n", 36, "level=INFO msg=hell _truncated=true\n"},
+		{"text too short for marker", "abcdefghijklmnop", 5, "abcde"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := string(handler.TruncateLine([]byte(tt.line), tt.n)); got != tt.want {
+				t.Errorf("TruncateLine(%q, %d) = %q, want %q", tt.line, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateLine_UTF8Boundary(t *testing.T) {
+	t.Parallel()
+
+	got := handler.TruncateLine([]byte("héllo wörld, ünïcode everywhere"), 20)
+	if len(got) > 20 {
+		t.Errorf("TruncateLine() length = %d, want at most 20", len(got))
+	}
+	if !utf8.Valid(got) {
+		t.Errorf("TruncateLine() = %q, want valid UTF-8", got)
+	}
+}
+
+func TestBaseHandler_WithMaxLineBytes(t *testing.T) {
+	t.Parallel()
+
+	if err := handler.WithMaxLineBytes(0)(&handler.BaseOptions{}); err == nil {
+		t.Error("WithMaxLineBytes(0) error = nil, want error")
+	}
+
+	var buf bytes.Buffer
+	h, err := handler.NewBaseHandler(&handler.BaseOptions{
+		Level:        handler.InfoLevel,
+		Output:       &buf,
+		MaxLineBytes: 60,
+	})
+	if err != nil {
+		t.Fatalf("NewBaseHandler() error = %v", err)
+	}
+
+	line := `{"msg":"hello","user":"alice","payload":"` + string(bytes.Repeat([]byte("x"), 100)) + `"}` + "\n"
+	if n, err := h.AtomicWriter().Write([]byte(line)); err != nil || n != len(line) {
+		t.Fatalf("Write() = %d, %v, want %d, nil", n, err, len(line))
+	}
+
+	got := buf.Bytes()
+	if len(got) > 60 {
+		t.Errorf("output length = %d, want at most 60", len(got))
+	}
+	var m map[string]any
+	if err := json.Unmarshal(got, &m); err != nil {
+		t.Fatalf("output %q is not valid JSON: %v", got, err)
+	}
+	if m["msg"] != "hello" || m["user"] != "alice" || m[handler.TruncatedKey] != true {
+		t.Errorf("output = %q, want msg, user and %s kept", got, handler.TruncatedKey)
+	}
+
+	var swapped bytes.Buffer
+	if err := h.SetOutput(&swapped); err != nil {
+		t.Fatalf("SetOutput() error = %v", err)
+	}
+	_, _ = h.AtomicWriter().Write([]byte(line))
+	if swapped.Len() > 60 {
+		t.Errorf("output length after SetOutput = %d, want at most 60", swapped.Len())
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"io"
 	"os"
-	"runtime/debug"
+	"strconv"
 
 	"github.com/inconshreveable/log15/v3"
 
@@ -33,6 +33,15 @@ func WithLevel(level handler.LogLevel) Log15Option {
 	}
 }
 
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR only.
+// The minimum level becomes the lowest enabled level.
+func WithEnabledLevels(levels ...handler.LogLevel) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithEnabledLevels(levels...)(o.base)
+	}
+}
+
 // WithOutput sets the output writer.
 func WithOutput(w io.Writer) Log15Option {
 	return func(o *log15Options) error {
@@ -40,6 +49,14 @@ func WithOutput(w io.Writer) Log15Option {
 	}
 }
 
+// WithFailFast makes New return an error if the output is not writable,
+// instead of failing on the first log. The default value is false.
+func WithFailFast(enabled bool) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithFailFast(enabled)(o.base)
+	}
+}
+
 // WithFormat sets the output format ("json", "terminal", or "logfmt").
 // The default format is "terminal".
 func WithFormat(format string) Log15Option {
@@ -59,13 +76,99 @@ func WithCaller(enabled bool) Log15Option {
 	}
 }
 
-// WithTrace enables stack traces for ERROR and above.
+// WithCallerShortPath trims the caller file to its package directory and
+// file name, e.g. "server/http.go", instead of the absolute path.
+// The default value is false.
+func WithCallerShortPath(enabled bool) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithCallerShortPath(enabled)(o.base)
+	}
+}
+
+// WithCallerLevel sets the minimum level that carries caller information
+// when caller reporting is enabled. The default value is handler.MinLevel.
+func WithCallerLevel(level handler.LogLevel) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithCallerLevel(level)(o.base)
+	}
+}
+
+// WithDebugFriendlyDefaults drops records below INFO, reports the caller
+// from WARN and adds a stack trace from ERROR.
+// See handler.WithDebugFriendlyDefaults.
+func WithDebugFriendlyDefaults() Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithDebugFriendlyDefaults()(o.base)
+	}
+}
+
+// WithTrace enables stack traces for ERROR and above, or for the level
+// set with WithTraceLevel.
 func WithTrace(enabled bool) Log15Option {
 	return func(o *log15Options) error {
 		return handler.WithTrace(enabled)(o.base)
 	}
 }
 
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. The default value is handler.DefaultTraceLevel.
+func WithTraceLevel(level handler.LogLevel) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithTraceLevel(level)(o.base)
+	}
+}
+
+// WithMaxStackDepth limits stack traces to the top n frames closest to the
+// log call site. The default value is handler.DefaultMaxStackDepth.
+func WithMaxStackDepth(n int) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithMaxStackDepth(n)(o.base)
+	}
+}
+
+// WithOutputSwapHook registers fn to be called after SetOutput replaced the
+// output writer, e.g. to close the old writer. Writes made after fn is
+// called go to the new writer.
+func WithOutputSwapHook(fn func(old, new io.Writer)) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithOutputSwapHook(fn)(o.base)
+	}
+}
+
+// WithFieldValidator registers fn to check every field key. Rejected keys
+// are logged in a sanitized snake_case form, with the original kept in an
+// "_invalid_field_<n>" field.
+func WithFieldValidator(fn func(key string) error) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithFieldValidator(fn)(o.base)
+	}
+}
+
+// WithMaxLineBytes truncates every rendered record to at most n bytes.
+// JSON records keep their leading fields and end with "_truncated":true.
+// See handler.WithMaxLineBytes.
+func WithMaxLineBytes(n int) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithMaxLineBytes(n)(o.base)
+	}
+}
+
+// WithKeyCase normalizes attribute keys and group names to the given case,
+// e.g. handler.KeyCaseSnake renders both "requestID" and "request-id" as
+// "request_id". The default value is handler.KeyCaseAsIs.
+func WithKeyCase(c handler.KeyCase) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithKeyCase(c)(o.base)
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record.
+func WithMetricsProvider(p handler.MetricsProvider) Log15Option {
+	return func(o *log15Options) error {
+		return handler.WithMetricsProvider(p)(o.base)
+	}
+}
+
 // log15Handler is a wrapper around log15 package.
 type log15Handler struct {
 	base      *handler.BaseHandler
@@ -85,6 +188,7 @@ var (
 	_ handler.CallerAdjuster = (*log15Handler)(nil)
 	_ handler.FeatureToggler = (*log15Handler)(nil)
 	_ handler.MutableConfig  = (*log15Handler)(nil)
+	_ handler.RawWriter      = (*log15Handler)(nil)
 )
 
 // levelMapper maps unilog log levels to log15 log levels.
@@ -104,6 +208,8 @@ func New(opts ...Log15Option) (handler.Handler, error) {
 	o := &log15Options{
 		base: &handler.BaseOptions{
 			Level:        handler.DefaultLevel,
+			TraceLevel:   handler.DefaultTraceLevel,
+			CallerLevel:  handler.MinLevel,
 			Output:       os.Stderr,
 			Format:       defaultFormat,
 			ValidFormats: validFormats,
@@ -135,26 +241,32 @@ func New(opts ...Log15Option) (handler.Handler, error) {
 
 // Handle implements the handler.Handler interface for log15.
 func (h *log15Handler) Handle(ctx context.Context, r *handler.Record) error {
-	if !h.Enabled(r.Level) {
+	if r.IsZero() {
+		return handler.ErrZeroRecord
+	}
+	if !h.base.EnabledContext(ctx, r.Level) {
 		return nil
 	}
 
+	keyValues := h.base.ValidateKeys(r.KeyValues)
+
 	// Combine handler attributes + record attributes
-	fields := make([]any, 0, len(h.keyValues)+len(r.KeyValues)+4)
+	fields := make([]any, 0, len(h.keyValues)+len(keyValues)+4)
 	fields = append(fields, h.keyValues...)
-	fields = append(fields, r.KeyValues...)
+	fields = append(fields, keyValues...)
 
 	// Only compute caller if enabled
 	if h.withCaller && r.PC != 0 {
-		fields = append(fields, "source", caller.NewFromPC(r.PC).Location())
+		c := caller.NewFromPC(r.PC)
+		fields = append(fields, "source", h.base.CallerPath(c.File())+":"+strconv.Itoa(c.Line()))
 	}
 
-	// Only capture stack if enabled and error-level
-	if h.withTrace && r.Level >= handler.ErrorLevel {
-		fields = append(fields, "stack", string(debug.Stack()))
+	// Only capture stack if enabled and at or above the trace level
+	if h.withTrace && r.Level >= h.base.TraceLevel() {
+		fields = append(fields, "stack", handler.CaptureStack(0, h.base.MaxStackDepth()))
 	}
 
-	h.logger.GetHandler().Log(
+	err := h.logger.GetHandler().Log(
 		log15.Record{
 			Time:     r.Time,
 			Lvl:      levelMapper.Map(r.Level),
@@ -162,6 +274,11 @@ func (h *log15Handler) Handle(ctx context.Context, r *handler.Record) error {
 			Ctx:      fields,
 			KeyNames: log15.DefaultRecordKeyNames,
 		})
+	if err != nil {
+		h.base.RecordError()
+		return err
+	}
+	h.base.RecordHandled(r.Level)
 
 	return nil
 }
@@ -171,6 +288,11 @@ func (h *log15Handler) Enabled(level handler.LogLevel) bool {
 	return h.base.Enabled(level)
 }
 
+// WriteRaw writes a pre-rendered line to the output, bypassing the backend.
+func (h *log15Handler) WriteRaw(level handler.LogLevel, payload []byte) error {
+	return h.base.WriteRaw(level, payload)
+}
+
 // HandlerState returns the underlying BaseHandler.
 func (h *log15Handler) HandlerState() handler.HandlerState {
 	return h.base
@@ -184,6 +306,8 @@ func (h *log15Handler) Features() handler.HandlerFeatures {
 // WithAttrs returns a new logger with the provided keyValues added to the context.
 // If keyValues is empty, the original logger is returned.
 func (h *log15Handler) WithAttrs(keyValues []any) handler.Chainer {
+	keyValues = h.base.ValidateKeys(keyValues)
+
 	if len(keyValues) < 2 {
 		return h
 	}
@@ -210,7 +334,7 @@ func (h *log15Handler) WithGroup(name string) handler.Chainer {
 		return h
 	}
 
-	base, err := h.base.WithKeyPrefix(name)
+	base, err := h.base.WithKeyPrefix(h.base.ConvertKey(name))
 	if err == nil {
 		return h
 	}
@@ -223,13 +347,7 @@ func (h *log15Handler) WithGroup(name string) handler.Chainer {
 
 // SetLevel dynamically changes the minimum level of logs that will be processed.
 func (h *log15Handler) SetLevel(level handler.LogLevel) error {
-	if err := h.base.SetLevel(level); err != nil {
-		return err
-	}
-
-	h.logger.SetHandler(h.buildLog15Handler())
-
-	return nil
+	return h.base.SetLevel(level)
 }
 
 // SetOutput sets the log destination.
@@ -340,10 +458,10 @@ func (h *log15Handler) deepClone(base *handler.BaseHandler) *log15Handler {
 }
 
 // buildLog15Handler returns a log15.Handler based on the current configuration.
+// It does not filter by level: Handle does, honoring a context level.
 func (h *log15Handler) buildLog15Handler() log15.Handler {
 	format := stringToFormat(h.base.Format())
-	handler := log15.StreamHandler(h.base.AtomicWriter(), format)
-	return log15.LvlFilterHandler(levelMapper.Map(h.base.Level()), handler)
+	return log15.StreamHandler(h.base.AtomicWriter(), format)
 }
 
 // setLog15Logger sets the log15.Logger to be used by the handler.
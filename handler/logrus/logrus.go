@@ -6,7 +6,6 @@ import (
 	"io"
 	"os"
 	"runtime"
-	"runtime/debug"
 
 	"github.com/sirupsen/logrus"
 
@@ -31,6 +30,15 @@ func WithLevel(level handler.LogLevel) LogrusOption {
 	}
 }
 
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR only.
+// The minimum level becomes the lowest enabled level.
+func WithEnabledLevels(levels ...handler.LogLevel) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithEnabledLevels(levels...)(o.base)
+	}
+}
+
 // WithOutput sets the output writer.
 func WithOutput(w io.Writer) LogrusOption {
 	return func(o *logrusOptions) error {
@@ -38,6 +46,14 @@ func WithOutput(w io.Writer) LogrusOption {
 	}
 }
 
+// WithFailFast makes New return an error if the output is not writable,
+// instead of failing on the first log. The default value is false.
+func WithFailFast(enabled bool) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithFailFast(enabled)(o.base)
+	}
+}
+
 // WithFormat sets the output format ("json" or "text").
 func WithFormat(format string) LogrusOption {
 	return func(o *logrusOptions) error {
@@ -56,13 +72,99 @@ func WithCaller(enabled bool) LogrusOption {
 	}
 }
 
-// WithTrace enables stack traces for ERROR and above.
+// WithCallerShortPath trims the caller file to its package directory and
+// file name, e.g. "server/http.go", instead of the absolute path.
+// The default value is false.
+func WithCallerShortPath(enabled bool) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithCallerShortPath(enabled)(o.base)
+	}
+}
+
+// WithCallerLevel sets the minimum level that carries caller information
+// when caller reporting is enabled. The default value is handler.MinLevel.
+func WithCallerLevel(level handler.LogLevel) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithCallerLevel(level)(o.base)
+	}
+}
+
+// WithDebugFriendlyDefaults drops records below INFO, reports the caller
+// from WARN and adds a stack trace from ERROR.
+// See handler.WithDebugFriendlyDefaults.
+func WithDebugFriendlyDefaults() LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithDebugFriendlyDefaults()(o.base)
+	}
+}
+
+// WithTrace enables stack traces for ERROR and above, or for the level
+// set with WithTraceLevel.
 func WithTrace(enabled bool) LogrusOption {
 	return func(o *logrusOptions) error {
 		return handler.WithTrace(enabled)(o.base)
 	}
 }
 
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. The default value is handler.DefaultTraceLevel.
+func WithTraceLevel(level handler.LogLevel) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithTraceLevel(level)(o.base)
+	}
+}
+
+// WithMaxStackDepth limits stack traces to the top n frames closest to the
+// log call site. The default value is handler.DefaultMaxStackDepth.
+func WithMaxStackDepth(n int) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithMaxStackDepth(n)(o.base)
+	}
+}
+
+// WithOutputSwapHook registers fn to be called after SetOutput replaced the
+// output writer, e.g. to close the old writer. Writes made after fn is
+// called go to the new writer.
+func WithOutputSwapHook(fn func(old, new io.Writer)) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithOutputSwapHook(fn)(o.base)
+	}
+}
+
+// WithFieldValidator registers fn to check every field key. Rejected keys
+// are logged in a sanitized snake_case form, with the original kept in an
+// "_invalid_field_<n>" field.
+func WithFieldValidator(fn func(key string) error) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithFieldValidator(fn)(o.base)
+	}
+}
+
+// WithMaxLineBytes truncates every rendered record to at most n bytes.
+// JSON records keep their leading fields and end with "_truncated":true.
+// See handler.WithMaxLineBytes.
+func WithMaxLineBytes(n int) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithMaxLineBytes(n)(o.base)
+	}
+}
+
+// WithKeyCase normalizes attribute keys and group names to the given case,
+// e.g. handler.KeyCaseSnake renders both "requestID" and "request-id" as
+// "request_id". The default value is handler.KeyCaseAsIs.
+func WithKeyCase(c handler.KeyCase) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithKeyCase(c)(o.base)
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record.
+func WithMetricsProvider(p handler.MetricsProvider) LogrusOption {
+	return func(o *logrusOptions) error {
+		return handler.WithMetricsProvider(p)(o.base)
+	}
+}
+
 // logrusHandler is a wrapper around logrus.Logger.
 type logrusHandler struct {
 	base   *handler.BaseHandler
@@ -82,6 +184,7 @@ var (
 	_ handler.CallerAdjuster = (*logrusHandler)(nil)
 	_ handler.FeatureToggler = (*logrusHandler)(nil)
 	_ handler.MutableConfig  = (*logrusHandler)(nil)
+	_ handler.RawWriter      = (*logrusHandler)(nil)
 )
 
 // levelMapper maps unilog log levels to logrus log levels.
@@ -101,6 +204,8 @@ func New(opts ...LogrusOption) (handler.Handler, error) {
 	o := &logrusOptions{
 		base: &handler.BaseOptions{
 			Level:        handler.InfoLevel,
+			TraceLevel:   handler.DefaultTraceLevel,
+			CallerLevel:  handler.MinLevel,
 			Output:       os.Stderr,
 			Format:       "text",
 			ValidFormats: validFormats,
@@ -120,8 +225,8 @@ func New(opts ...LogrusOption) (handler.Handler, error) {
 
 	// Create logrus logger
 	logger := logrus.New()
-	logger.SetOutput(base.AtomicWriter())
-	logger.SetLevel(levelMapper.Map(base.Level()))
+	logger.SetOutput(base.ErrorRecordingWriter())
+	logger.SetLevel(logrus.TraceLevel) // Handle filters by level
 
 	// Set formatter
 	if base.Format() == "json" {
@@ -149,10 +254,15 @@ func New(opts ...LogrusOption) (handler.Handler, error) {
 
 // Handle implements the handler.Handler interface for logrus.
 func (h *logrusHandler) Handle(ctx context.Context, r *handler.Record) error {
-	if !h.Enabled(r.Level) {
+	if r.IsZero() {
+		return handler.ErrZeroRecord
+	}
+	if !h.base.EnabledContext(ctx, r.Level) {
 		return nil
 	}
 
+	keyValues := h.base.ValidateKeys(r.KeyValues)
+
 	// Start with entry (may have chained fields)
 	entry := h.entry
 
@@ -162,27 +272,28 @@ func (h *logrusHandler) Handle(ctx context.Context, r *handler.Record) error {
 	}
 
 	// Convert keyValues to logrus.Fields
-	n := len(r.KeyValues)
+	n := len(keyValues)
 	fields := make(logrus.Fields, n/2+2)
 	for i := 0; i < n-1; i += 2 {
-		key, ok := r.KeyValues[i].(string)
+		key, ok := keyValues[i].(string)
 		if !ok {
-			key = fmt.Sprint(r.KeyValues[i])
+			key = fmt.Sprint(keyValues[i])
 		}
-		fields[key] = r.KeyValues[i+1]
+		fields[key] = keyValues[i+1]
 	}
 
 	// Add caller if enabled and not already handled by logger
 	if h.withCaller && r.PC != 0 {
-		fields["caller"] = resolveFrame(r.PC)
+		fields["caller"] = resolveFrame(r.PC, h.base)
 	}
 
 	// Add stack trace if enabled
-	if h.withTrace && r.Level >= handler.ErrorLevel {
-		fields["stack"] = string(debug.Stack())
+	if h.withTrace && r.Level >= h.base.TraceLevel() {
+		fields["stack"] = handler.CaptureStack(0, h.base.MaxStackDepth())
 	}
 
 	entry.WithFields(fields).Log(levelMapper.Map(r.Level), r.Message)
+	h.base.RecordHandled(r.Level)
 
 	return nil
 }
@@ -192,6 +303,11 @@ func (h *logrusHandler) Enabled(level handler.LogLevel) bool {
 	return h.base.Enabled(level)
 }
 
+// WriteRaw writes a pre-rendered line to the output, bypassing the backend.
+func (h *logrusHandler) WriteRaw(level handler.LogLevel, payload []byte) error {
+	return h.base.WriteRaw(level, payload)
+}
+
 // HandlerState returns the underlying BaseHandler.
 func (h *logrusHandler) HandlerState() handler.HandlerState {
 	return h.base
@@ -207,6 +323,8 @@ func (h *logrusHandler) Features() handler.HandlerFeatures {
 
 // WithAttrs returns a new logger with the provided keyValues added to the context.
 func (h *logrusHandler) WithAttrs(keyValues []any) handler.Chainer {
+	keyValues = h.base.ValidateKeys(keyValues)
+
 	if len(keyValues) < 2 {
 		return h
 	}
@@ -234,7 +352,7 @@ func (h *logrusHandler) WithGroup(name string) handler.Chainer {
 		return h
 	}
 
-	base, err := h.base.WithKeyPrefix(name)
+	base, err := h.base.WithKeyPrefix(h.base.ConvertKey(name))
 	if err != nil {
 		return h
 	}
@@ -247,13 +365,7 @@ func (h *logrusHandler) WithGroup(name string) handler.Chainer {
 
 // SetLevel dynamically changes the minimum level of logs that will be processed.
 func (h *logrusHandler) SetLevel(level handler.LogLevel) error {
-	if err := h.base.SetLevel(level); err != nil {
-		return err
-	}
-
-	h.logger.SetLevel(levelMapper.Map(level))
-
-	return nil
+	return h.base.SetLevel(level)
 }
 
 // SetOutput sets the log destination.
@@ -262,7 +374,7 @@ func (h *logrusHandler) SetOutput(w io.Writer) error {
 		return err
 	}
 
-	h.logger.SetOutput(h.base.AtomicWriter())
+	h.logger.SetOutput(h.base.ErrorRecordingWriter())
 
 	return nil
 }
@@ -350,8 +462,8 @@ func (h *logrusHandler) clone() *logrusHandler {
 // deepClone returns a deep copy of the handler with a new BaseHandler.
 func (h *logrusHandler) deepClone(base *handler.BaseHandler) *logrusHandler {
 	logger := logrus.New()
-	logger.SetOutput(base.AtomicWriter())
-	logger.SetLevel(levelMapper.Map(base.Level()))
+	logger.SetOutput(base.ErrorRecordingWriter())
+	logger.SetLevel(logrus.TraceLevel) // Handle filters by level
 
 	if base.Format() == "json" {
 		logger.SetFormatter(&logrus.JSONFormatter{
@@ -373,9 +485,10 @@ func (h *logrusHandler) deepClone(base *handler.BaseHandler) *logrusHandler {
 	}
 }
 
-// resolveFrame converts a PC to a source location string.
-func resolveFrame(pc uintptr) string {
+// resolveFrame converts a PC to a source location string, with the file
+// path as configured on base.
+func resolveFrame(pc uintptr, base *handler.BaseHandler) string {
 	frames := runtime.CallersFrames([]uintptr{pc})
 	frame, _ := frames.Next()
-	return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+	return fmt.Sprintf("%s:%d", base.CallerPath(frame.File), frame.Line)
 }
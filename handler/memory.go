@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// MemoryHandler renders records as text lines into a bounded in-memory
+// FIFO buffer that can be queried at any time, e.g. to serve recent logs from
+// a debug endpoint in serverless or embedded environments.
+//
+// When adding a line would exceed the byte limit, the oldest lines are
+// dropped. Unlike RingBufferHandler, it stores rendered output rather than
+// records and respects its own minimum level.
+//
+// Handlers derived via WithAttrs and WithGroup share the same buffer and level.
+// All methods are safe for concurrent use.
+type MemoryHandler struct {
+	store *lineStore
+	level *atomic.Int32
+	attrs attrState
+}
+
+// lineStore is a FIFO of rendered lines bounded by their total size.
+type lineStore struct {
+	mu       sync.Mutex
+	lines    [][]byte
+	size     int // Total bytes in lines
+	maxBytes int
+}
+
+// Ensure MemoryHandler implements the handler interfaces.
+var (
+	_ Handler      = (*MemoryHandler)(nil)
+	_ Chainer      = (*MemoryHandler)(nil)
+	_ HandlerState = (*MemoryHandler)(nil)
+)
+
+// NewMemoryHandler returns a handler that keeps at most maxBytes of rendered
+// output in memory. Lines have the form:
+//
+//	2024-01-15T10:30:00.123Z INFO user created id=42 name="Jane Doe"
+//
+// The minimum level defaults to DefaultLevel; see SetLevel.
+// Returns error if maxBytes is not positive.
+func NewMemoryHandler(maxBytes int) (*MemoryHandler, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("memory handler size must be positive, got %d", maxBytes)
+	}
+
+	h := &MemoryHandler{
+		store: &lineStore{maxBytes: maxBytes},
+		level: &atomic.Int32{},
+	}
+	h.level.Store(int32(DefaultLevel))
+
+	return h, nil
+}
+
+// Handle renders the record and appends it to the buffer,
+// dropping the oldest lines if needed.
+func (h *MemoryHandler) Handle(_ context.Context, r *Record) error {
+	if r.IsZero() {
+		return ErrZeroRecord
+	}
+	if !h.Enabled(r.Level) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(r.Time.Format(time.RFC3339Nano))
+	buf.WriteByte(' ')
+	appendRecordText(&buf, &Record{Level: r.Level, Message: r.Message, KeyValues: h.attrs.merge(r.KeyValues)})
+
+	h.store.push(buf.Bytes())
+
+	return nil
+}
+
+// Enabled reports whether the handler stores records at the given level.
+func (h *MemoryHandler) Enabled(level LogLevel) bool {
+	return level >= LogLevel(h.level.Load())
+}
+
+// SetLevel changes the minimum level of records that will be stored.
+// Affects all handlers sharing this buffer.
+func (h *MemoryHandler) SetLevel(level LogLevel) error {
+	if err := ValidateLogLevel(level); err != nil {
+		return err
+	}
+
+	h.level.Store(int32(level))
+
+	return nil
+}
+
+// HandlerState returns the handler itself; caller and trace reporting are disabled.
+func (h *MemoryHandler) HandlerState() HandlerState { return h }
+
+// Features reports no native features.
+func (h *MemoryHandler) Features() HandlerFeatures { return HandlerFeatures{} }
+
+// CallerEnabled returns false.
+func (h *MemoryHandler) CallerEnabled() bool { return false }
+
+// TraceEnabled returns false.
+func (h *MemoryHandler) TraceEnabled() bool { return false }
+
+// CallerSkip returns 0.
+func (h *MemoryHandler) CallerSkip() int { return 0 }
+
+// WithAttrs returns a new handler with the key-value pairs added.
+// The buffer is shared with the original handler.
+func (h *MemoryHandler) WithAttrs(keyValues []any) Chainer {
+	if len(keyValues) < 2 {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = h.attrs.withAttrs(keyValues)
+
+	return &clone
+}
+
+// WithGroup returns a new handler that prefixes subsequent keys with name
+// using DefaultKeySeparator. The buffer is shared with the original handler.
+func (h *MemoryHandler) WithGroup(name string) Chainer {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = h.attrs.withGroup(name)
+
+	return &clone
+}
+
+// Bytes returns a copy of the buffered output, oldest line first.
+func (h *MemoryHandler) Bytes() []byte {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+
+	return bytes.Join(h.store.lines, nil)
+}
+
+// Lines returns the buffered lines without trailing newlines, oldest first.
+func (h *MemoryHandler) Lines() []string {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+
+	lines := make([]string, len(h.store.lines))
+	for i, line := range h.store.lines {
+		lines[i] = string(bytes.TrimSuffix(line, []byte{'\n'}))
+	}
+
+	return lines
+}
+
+// push appends line, evicting the oldest lines until the total fits maxBytes.
+// A line longer than maxBytes on its own is truncated to fit.
+func (s *lineStore) push(line []byte) {
+	if len(line) > s.maxBytes {
+		line = truncateLine(line, s.maxBytes)
+	}
+	line = bytes.Clone(line)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drop := 0
+	for s.size+len(line) > s.maxBytes && drop < len(s.lines) {
+		s.size -= len(s.lines[drop])
+		s.lines[drop] = nil
+		drop++
+	}
+	s.lines = append(s.lines[drop:], line)
+	s.size += len(line)
+}
+
+// truncateLine shortens a newline-terminated line to at most n bytes,
+// keeping the trailing newline and not splitting a UTF-8 sequence.
+func truncateLine(line []byte, n int) []byte {
+	if n <= 1 {
+		return []byte{'\n'}
+	}
+
+	cut := line[:n-1]
+	// Drop a trailing rune that the cut left incomplete
+	start := len(cut) - 1
+	for start > 0 && !utf8.RuneStart(cut[start]) {
+		start--
+	}
+	if start >= 0 && !utf8.FullRune(cut[start:]) {
+		cut = cut[:start]
+	}
+
+	return append(bytes.Clone(cut), '\n')
+}
@@ -0,0 +1,136 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestNewMemoryHandler_InvalidSize(t *testing.T) {
+	t.Parallel()
+
+	if _, err := handler.NewMemoryHandler(0); err == nil {
+		t.Error("NewMemoryHandler(0) error = nil, want error")
+	}
+}
+
+func TestMemoryHandler_Lines(t *testing.T) {
+	t.Parallel()
+
+	h, err := handler.NewMemoryHandler(1024)
+	if err != nil {
+		t.Fatalf("NewMemoryHandler() error = %v", err)
+	}
+
+	ctx := context.Background()
+	ts := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	_ = h.Handle(ctx, &handler.Record{Time: ts, Level: handler.InfoLevel, Message: "started", KeyValues: []any{"port", 8080}})
+	_ = h.Handle(ctx, &handler.Record{Time: ts, Level: handler.DebugLevel, Message: "dropped"})
+	_ = h.WithGroup("req").Handle(ctx, &handler.Record{Time: ts, Level: handler.WarnLevel, Message: "slow", KeyValues: []any{"path", "/a b"}})
+
+	want := []string{
+		`2024-01-15T10:30:00Z INFO started port=8080`,
+		`2024-01-15T10:30:00Z WARN slow req_path="/a b"`,
+	}
+	got := h.Lines()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+	if string(h.Bytes()) != strings.Join(want, "\n")+"\n" {
+		t.Errorf("Bytes() = %q", h.Bytes())
+	}
+
+	if err := h.SetLevel(handler.DebugLevel); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	_ = h.Handle(ctx, &handler.Record{Time: ts, Level: handler.DebugLevel, Message: "kept"})
+	if got := h.Lines(); len(got) != 3 {
+		t.Errorf("after SetLevel(Debug) lines = %d, want 3", len(got))
+	}
+}
+
+func TestMemoryHandler_EvictsOldest(t *testing.T) {
+	t.Parallel()
+
+	line := func(i int) *handler.Record {
+		return &handler.Record{Level: handler.InfoLevel, Message: fmt.Sprintf("msg-%02d", i)}
+	}
+
+	// Measure one rendered line to size the buffer for exactly three
+	probe, _ := handler.NewMemoryHandler(1024)
+	_ = probe.Handle(context.Background(), line(0))
+	lineLen := len(probe.Bytes())
+
+	h, _ := handler.NewMemoryHandler(3 * lineLen)
+	for i := range 10 {
+		_ = h.Handle(context.Background(), line(i))
+	}
+
+	got := h.Lines()
+	if len(got) != 3 {
+		t.Fatalf("lines = %d, want 3", len(got))
+	}
+	for i, l := range got {
+		if want := fmt.Sprintf("msg-%02d", i+7); !strings.HasSuffix(l, want) {
+			t.Errorf("line %d = %q, want suffix %q", i, l, want)
+		}
+	}
+	if n := len(h.Bytes()); n > 3*lineLen {
+		t.Errorf("Bytes() length = %d, exceeds limit %d", n, 3*lineLen)
+	}
+}
+
+func TestMemoryHandler_OversizedLine(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewMemoryHandler(40)
+	_ = h.Handle(context.Background(), &handler.Record{Level: handler.InfoLevel, Message: "short"})
+	_ = h.Handle(context.Background(), &handler.Record{Level: handler.InfoLevel, Message: strings.Repeat("é", 50)})
+
+	data := h.Bytes()
+	if len(data) > 40 {
+		t.Errorf("Bytes() length = %d, want <= 40", len(data))
+	}
+	if !utf8.Valid(data) {
+		t.Errorf("Bytes() = %q, not valid UTF-8", data)
+	}
+	if lines := h.Lines(); len(lines) != 1 {
+		t.Errorf("lines = %d, want 1 (oversized line replaces the buffer)", len(lines))
+	}
+}
+
+func TestMemoryHandler_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewMemoryHandler(4096)
+	child := h.WithAttrs([]any{"worker", true})
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				_ = child.Handle(context.Background(), &handler.Record{Level: handler.InfoLevel, Message: "m", KeyValues: []any{"i", i, "j", j}})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				_ = h.Lines()
+				_ = h.Bytes()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(h.Bytes()); n > 4096 || n == 0 {
+		t.Errorf("Bytes() length = %d, want in (0, 4096]", n)
+	}
+}
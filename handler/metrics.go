@@ -0,0 +1,16 @@
+package handler
+
+// MetricsProvider receives per-record outcome notifications from a handler.
+// It lets metrics backends (e.g., Prometheus) be plugged in without the
+// handler package depending on them.
+//
+// Implementations must be safe for concurrent use, since handlers call
+// them from the logging hot path.
+type MetricsProvider interface {
+	// RecordHandled is called after a record at the given level was
+	// successfully passed to the backend.
+	RecordHandled(level LogLevel)
+
+	// RecordError is called when the backend failed to handle a record.
+	RecordError()
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"runtime"
+)
+
+// multiHandler is the Handler returned by NewMultiHandler.
+type multiHandler struct {
+	handlers []Handler
+	features HandlerFeatures
+}
+
+// multiState is the HandlerState of a multiHandler.
+type multiState struct {
+	caller bool
+	trace  bool
+}
+
+// Ensure multiHandler implements the handler interfaces.
+var (
+	_ Handler = (*multiHandler)(nil)
+	_ Chainer = (*multiHandler)(nil)
+	_ Syncer  = (*multiHandler)(nil)
+)
+
+// NewMultiHandler returns a handler that sends every record to each of
+// handlers enabled for its level, e.g. to write JSON to a file and text to
+// the console. Handle calls the handlers in order and joins their errors.
+//
+// Caller reporting works for both kinds of handlers: the multi handler
+// advertises FeatNativeCaller and resolves the program counter itself for
+// handlers that need one. It reports caller and trace as enabled if any
+// handler has them enabled.
+// Returns error if handlers is empty or contains nil.
+func NewMultiHandler(handlers ...Handler) (Handler, error) {
+	if len(handlers) == 0 {
+		return nil, errors.New("at least one handler is required")
+	}
+
+	// Features shared by every handler, plus the native caller support
+	// the multi handler emulates
+	shared := ^Feature(0)
+	for _, h := range handlers {
+		if h == nil {
+			return nil, ErrNilHandler
+		}
+		shared &= h.Features().features
+	}
+
+	return &multiHandler{
+		handlers: append([]Handler(nil), handlers...),
+		features: NewHandlerFeatures(shared&^FeatZeroAlloc | FeatNativeCaller),
+	}, nil
+}
+
+// Handle sends the record to every handler enabled for its level.
+// Each handler receives its own copy of the record.
+func (m *multiHandler) Handle(ctx context.Context, r *Record) error {
+	if r.IsZero() {
+		return ErrZeroRecord
+	}
+
+	var (
+		errs []error
+		pc   uintptr
+	)
+	for _, h := range m.handlers {
+		if !enabledContext(ctx, h, r.Level) {
+			continue
+		}
+
+		rec := *r
+		if r.Skip > 0 {
+			if h.Features().Supports(FeatNativeCaller) {
+				// Account for this frame
+				rec.Skip = r.Skip + 1
+			} else {
+				if pc == 0 {
+					pc = callerPC(r.Skip + 1)
+				}
+				rec.PC = pc
+				rec.Skip = 0
+			}
+		}
+
+		if err := h.Handle(ctx, &rec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// callerPC returns the program counter skip frames above its caller,
+// counted as runtime.Callers does from the caller's frame.
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return 0
+	}
+
+	return pcs[0]
+}
+
+// forwardedRecord returns the record a wrapping handler passes on to inner
+// from its Handle method. If r carries a caller skip, the returned copy
+// accounts for the wrapper's frame: the skip is increased by one for native
+// caller handlers, or resolved to a program counter for the others.
+// Otherwise r is returned as is.
+func forwardedRecord(inner Handler, r *Record) *Record {
+	if r == nil || r.Skip <= 0 {
+		return r
+	}
+
+	rec := *r
+	if inner.Features().Supports(FeatNativeCaller) {
+		rec.Skip = r.Skip + 1
+	} else {
+		rec.PC = callerPC(r.Skip + 2)
+		rec.Skip = 0
+	}
+
+	return &rec
+}
+
+// Enabled reports whether any handler is enabled for level.
+func (m *multiHandler) Enabled(level LogLevel) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HandlerState returns a state combining the states of all handlers.
+func (m *multiHandler) HandlerState() HandlerState {
+	var s multiState
+	for _, h := range m.handlers {
+		if state := h.HandlerState(); state != nil {
+			s.caller = s.caller || state.CallerEnabled()
+			s.trace = s.trace || state.TraceEnabled()
+		}
+	}
+
+	return &s
+}
+
+// Features returns the features shared by all handlers, with FeatNativeCaller
+// always set and FeatZeroAlloc always cleared.
+func (m *multiHandler) Features() HandlerFeatures {
+	return m.features
+}
+
+// WithAttrs returns a multi handler whose handlers have the key-value pairs
+// added. Handlers that do not implement Chainer are kept unchanged.
+func (m *multiHandler) WithAttrs(keyValues []any) Chainer {
+	return m.derive(func(ch Chainer) Chainer { return ch.WithAttrs(keyValues) })
+}
+
+// WithGroup returns a multi handler whose handlers start the group.
+// Handlers that do not implement Chainer are kept unchanged.
+func (m *multiHandler) WithGroup(name string) Chainer {
+	return m.derive(func(ch Chainer) Chainer { return ch.WithGroup(name) })
+}
+
+// Sync flushes every handler implementing Syncer. All handlers are flushed
+// even if one fails; the errors are joined.
+func (m *multiHandler) Sync() error {
+	var errs []error
+	for _, h := range m.handlers {
+		if s, ok := h.(Syncer); ok {
+			if err := s.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// derive returns a multi handler with fn applied to every Chainer handler.
+func (m *multiHandler) derive(fn func(Chainer) Chainer) *multiHandler {
+	handlers := make([]Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		if ch, ok := h.(Chainer); ok {
+			handlers[i] = fn(ch)
+		} else {
+			handlers[i] = h
+		}
+	}
+
+	return &multiHandler{handlers: handlers, features: m.features}
+}
+
+// CallerEnabled reports whether any handler has caller reporting enabled.
+func (s *multiState) CallerEnabled() bool { return s.caller }
+
+// TraceEnabled reports whether any handler has stack traces enabled.
+func (s *multiState) TraceEnabled() bool { return s.trace }
+
+// CallerSkip returns 0; each handler applies its own caller skip.
+func (s *multiState) CallerSkip() int { return 0 }
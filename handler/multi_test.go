@@ -0,0 +1,184 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// callerHandler records the caller information of the last record.
+// A native caller handler resolves the skip to a file and line, as the
+// backends do.
+type callerHandler struct {
+	recordingHandler
+	native bool
+	pc     uintptr
+	skip   int
+	file   string
+	line   int
+}
+
+func (h *callerHandler) Handle(ctx context.Context, r *handler.Record) error {
+	h.pc, h.skip = r.PC, r.Skip
+	if h.native && r.Skip > 0 {
+		_, h.file, h.line, _ = runtime.Caller(r.Skip)
+	}
+	return h.recordingHandler.Handle(ctx, r)
+}
+
+// testForwardedCaller checks that the handler returned by wrap reports the
+// caller of its Handle method, both through a native caller handler and
+// through one that needs a program counter.
+func testForwardedCaller(t *testing.T, wrap func(inner handler.Handler) handler.Handler) {
+	t.Helper()
+
+	native := &callerHandler{native: true}
+	h := wrap(native)
+	r := newRecord(handler.InfoLevel, "msg")
+	r.Skip = 1 // The caller of Handle
+	_, file, line, _ := runtime.Caller(0)
+	_ = h.Handle(context.Background(), r)
+	if native.file != file || native.line != line+1 {
+		t.Errorf("native handler caller = %s:%d, want %s:%d", native.file, native.line, file, line+1)
+	}
+
+	plain := &callerHandler{}
+	h = wrap(plain)
+	r = newRecord(handler.InfoLevel, "msg")
+	r.Skip = 1
+	_, file, line, _ = runtime.Caller(0)
+	_ = h.Handle(context.Background(), r)
+	got, _ := runtime.CallersFrames([]uintptr{plain.pc}).Next()
+	if plain.skip != 0 || got.File != file || got.Line != line+1 {
+		t.Errorf("plain handler got skip %d, caller %s:%d; want skip 0, caller %s:%d",
+			plain.skip, got.File, got.Line, file, line+1)
+	}
+}
+
+func (h *callerHandler) Features() handler.HandlerFeatures {
+	if h.native {
+		return handler.NewHandlerFeatures(handler.FeatNativeCaller)
+	}
+	return handler.HandlerFeatures{}
+}
+
+func TestNewMultiHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := handler.NewMultiHandler(); err == nil {
+		t.Error("NewMultiHandler() error = nil, want error")
+	}
+	if _, err := handler.NewMultiHandler(&recordingHandler{}, nil); !errors.Is(err, handler.ErrNilHandler) {
+		t.Errorf("NewMultiHandler(nil) error = %v, want ErrNilHandler", err)
+	}
+}
+
+func TestMultiHandler(t *testing.T) {
+	t.Parallel()
+
+	info := &recordingHandler{level: handler.InfoLevel}
+	warn := &recordingHandler{level: handler.WarnLevel}
+	h, err := handler.NewMultiHandler(info, warn)
+	if err != nil {
+		t.Fatalf("NewMultiHandler() error = %v", err)
+	}
+
+	if h.Enabled(handler.DebugLevel) || !h.Enabled(handler.InfoLevel) {
+		t.Error("Enabled() should report whether any handler is enabled")
+	}
+	if err := h.Handle(context.Background(), &handler.Record{}); !errors.Is(err, handler.ErrZeroRecord) {
+		t.Errorf("Handle(zero record) error = %v, want ErrZeroRecord", err)
+	}
+
+	_ = h.Handle(context.Background(), newRecord(handler.InfoLevel, "info"))
+	_ = h.Handle(context.Background(), newRecord(handler.ErrorLevel, "error"))
+
+	// Each handler only receives records at or above its own level
+	if got, want := info.Messages(), []string{"info", "error"}; !slices.Equal(got, want) {
+		t.Errorf("info handler messages = %v, want %v", got, want)
+	}
+	if got, want := warn.Messages(), []string{"error"}; !slices.Equal(got, want) {
+		t.Errorf("warn handler messages = %v, want %v", got, want)
+	}
+
+	s, ok := h.(handler.Syncer)
+	if !ok {
+		t.Fatal("NewMultiHandler() result does not implement Syncer")
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if info.syncs != 1 || warn.syncs != 1 {
+		t.Errorf("syncs = %d, %d, want 1, 1", info.syncs, warn.syncs)
+	}
+}
+
+func TestMultiHandler_JoinsErrors(t *testing.T) {
+	t.Parallel()
+
+	ok := &recordingHandler{}
+	h, _ := handler.NewMultiHandler(&failingHandler{}, ok)
+
+	if err := h.Handle(context.Background(), newRecord(handler.InfoLevel, "msg")); err == nil {
+		t.Error("Handle() error = nil, want the failing handler's error")
+	}
+	if got := ok.Messages(); len(got) != 1 {
+		t.Errorf("handler after the failing one got %v, want the record", got)
+	}
+}
+
+func TestMultiHandler_Caller(t *testing.T) {
+	t.Parallel()
+
+	native := &callerHandler{native: true}
+	plain := &callerHandler{}
+	h, _ := handler.NewMultiHandler(native, plain)
+
+	if !h.Features().Supports(handler.FeatNativeCaller) {
+		t.Fatal("Features() should include FeatNativeCaller")
+	}
+
+	r := newRecord(handler.InfoLevel, "msg")
+	r.Skip = 1 // The caller of Handle, as runtime.Callers counts from there
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	_ = h.Handle(context.Background(), r)
+
+	// Native handlers get the skip adjusted for the multi handler's frame
+	if native.skip != 2 || native.pc != 0 {
+		t.Errorf("native handler got skip %d, pc %#x; want skip 2, no pc", native.skip, native.pc)
+	}
+
+	// Other handlers get the resolved program counter
+	want, _ := runtime.CallersFrames(pcs).Next()
+	got, _ := runtime.CallersFrames([]uintptr{plain.pc}).Next()
+	if plain.skip != 0 || got.Function != want.Function {
+		t.Errorf("plain handler got skip %d, function %q; want skip 0, function %q",
+			plain.skip, got.Function, want.Function)
+	}
+}
+
+func TestMultiHandler_WithAttrs(t *testing.T) {
+	t.Parallel()
+
+	mem, _ := handler.NewMemoryHandler(1024)
+	plain := &recordingHandler{}
+	h, _ := handler.NewMultiHandler(mem, plain)
+
+	child := h.(handler.Chainer).WithAttrs([]any{"k", "v"}).WithGroup("g")
+	if err := child.Handle(context.Background(), newRecord(handler.InfoLevel, "msg", "a", 1)); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if got := mem.Lines(); len(got) != 1 || !strings.HasSuffix(got[0], "INFO msg k=v g_a=1") {
+		t.Errorf("memory handler lines = %v, want the record with its attributes", got)
+	}
+	if got := plain.Messages(); len(got) != 1 {
+		t.Errorf("non-Chainer handler messages = %v, want the record", got)
+	}
+}
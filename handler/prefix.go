@@ -0,0 +1,89 @@
+package handler
+
+import "context"
+
+// PrefixHandler wraps a Handler and prepends a constant prefix, followed by
+// a space, to the message of every record it forwards, e.g. "[billing]" to
+// tell components apart in a shared log file or to match a legacy format.
+// An empty prefix leaves messages unchanged.
+type PrefixHandler struct {
+	inner  Handler
+	prefix string
+}
+
+// Ensure PrefixHandler implements the handler interfaces.
+var (
+	_ Handler = (*PrefixHandler)(nil)
+	_ Chainer = (*PrefixHandler)(nil)
+	_ Syncer  = (*PrefixHandler)(nil)
+)
+
+// NewPrefixHandler returns a handler that prepends prefix and a space to
+// the message of every record passed to inner.
+// Returns error if inner is nil.
+func NewPrefixHandler(inner Handler, prefix string) (*PrefixHandler, error) {
+	if inner == nil {
+		return nil, ErrNilHandler
+	}
+
+	return &PrefixHandler{inner: inner, prefix: prefix}, nil
+}
+
+// Handle forwards a copy of the record with the prefixed message to the
+// inner handler. The caller's record is left unchanged.
+func (h *PrefixHandler) Handle(ctx context.Context, r *Record) error {
+	if h.prefix == "" || r == nil {
+		return h.inner.Handle(ctx, forwardedRecord(h.inner, r))
+	}
+
+	rec := *r
+	rec.Message = h.prefix + " " + r.Message
+
+	return h.inner.Handle(ctx, forwardedRecord(h.inner, &rec))
+}
+
+// Enabled reports whether the inner handler is enabled for level.
+func (h *PrefixHandler) Enabled(level LogLevel) bool {
+	return h.inner.Enabled(level)
+}
+
+// HandlerState returns the inner handler's state.
+func (h *PrefixHandler) HandlerState() HandlerState {
+	return h.inner.HandlerState()
+}
+
+// Features returns the inner handler's features.
+func (h *PrefixHandler) Features() HandlerFeatures {
+	return h.inner.Features()
+}
+
+// Sync flushes the inner handler if it implements Syncer.
+func (h *PrefixHandler) Sync() error {
+	if s, ok := h.inner.(Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// WithAttrs returns a handler whose inner handler has the key-value pairs added.
+// It returns the original handler if the inner handler does not implement Chainer.
+func (h *PrefixHandler) WithAttrs(keyValues []any) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	return &PrefixHandler{inner: ch.WithAttrs(keyValues), prefix: h.prefix}
+}
+
+// WithGroup returns a handler whose inner handler starts the group.
+// It returns the original handler if the inner handler does not implement Chainer.
+func (h *PrefixHandler) WithGroup(name string) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	return &PrefixHandler{inner: ch.WithGroup(name), prefix: h.prefix}
+}
@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestNewPrefixHandler_NilInner(t *testing.T) {
+	t.Parallel()
+
+	if _, err := handler.NewPrefixHandler(nil, "[billing]"); err == nil {
+		t.Error("NewPrefixHandler(nil) error = nil, want error")
+	}
+}
+
+func TestPrefixHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{"prefix", "[billing]", "[billing] charged"},
+		{"empty prefix", "", "charged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inner := &recordingHandler{}
+			h, err := handler.NewPrefixHandler(inner, tt.prefix)
+			if err != nil {
+				t.Fatalf("NewPrefixHandler() error = %v", err)
+			}
+
+			r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "charged"}
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			if got := inner.Messages(); !slices.Equal(got, []string{tt.want}) {
+				t.Errorf("messages = %q, want [%q]", got, tt.want)
+			}
+			if r.Message != "charged" {
+				t.Errorf("caller's record message = %q, want it unchanged", r.Message)
+			}
+		})
+	}
+}
+
+func TestPrefixHandler_Caller(t *testing.T) {
+	t.Parallel()
+
+	for _, prefix := range []string{"[billing]", ""} {
+		testForwardedCaller(t, func(inner handler.Handler) handler.Handler {
+			h, _ := handler.NewPrefixHandler(inner, prefix)
+			return h
+		})
+	}
+}
+
+func TestPrefixHandler_Chain(t *testing.T) {
+	t.Parallel()
+
+	mem, _ := handler.NewMemoryHandler(1024)
+	h, _ := handler.NewPrefixHandler(mem, "[billing]")
+	ch := h.WithAttrs([]any{"tenant", "acme"}).WithGroup("req")
+
+	r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "charged", KeyValues: []any{"id", 1}}
+	if err := ch.(handler.Handler).Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	out := string(mem.Bytes())
+	for _, want := range []string{"[billing] charged", "tenant", "acme"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output = %q, want it to contain %q", out, want)
+		}
+	}
+}
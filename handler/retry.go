@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultRetryMaxElapsed is the default bound on the time RetryHandler
+// spends retrying a single record.
+const DefaultRetryMaxElapsed = time.Second
+
+// RetryHandler wraps a Handler and retries records the inner handler failed
+// to handle, with exponential backoff, so that a transient failure of a
+// remote handler does not immediately send the record to the fallback logger.
+//
+// Retries run on the logging goroutine. They stop after the configured
+// number of attempts, once the next wait would exceed the maximum elapsed
+// time (see WithMaxElapsed), or when the record's context is done, and the
+// last error is returned. ErrZeroRecord and ErrHandlerClosed are returned
+// without retrying, since another attempt cannot succeed.
+type RetryHandler struct {
+	inner      Handler
+	attempts   int
+	backoff    time.Duration
+	maxElapsed time.Duration
+}
+
+// Ensure RetryHandler implements the handler interfaces.
+var (
+	_ Handler = (*RetryHandler)(nil)
+	_ Chainer = (*RetryHandler)(nil)
+	_ Syncer  = (*RetryHandler)(nil)
+)
+
+// NewRetryHandler returns a handler that calls inner.Handle up to attempts
+// times per record, waiting backoff before the first retry and doubling the
+// wait before each further one. Retrying stops after DefaultRetryMaxElapsed
+// unless changed with WithMaxElapsed.
+// Returns error if inner is nil, attempts is not positive or backoff is negative.
+func NewRetryHandler(inner Handler, attempts int, backoff time.Duration) (*RetryHandler, error) {
+	if inner == nil {
+		return nil, ErrNilHandler
+	}
+	if attempts <= 0 {
+		return nil, fmt.Errorf("retry attempts must be positive, got %d", attempts)
+	}
+	if backoff < 0 {
+		return nil, fmt.Errorf("retry backoff cannot be negative, got %s", backoff)
+	}
+
+	return &RetryHandler{
+		inner:      inner,
+		attempts:   attempts,
+		backoff:    backoff,
+		maxElapsed: DefaultRetryMaxElapsed,
+	}, nil
+}
+
+// WithMaxElapsed returns a copy of the handler that gives up retrying a
+// record instead of waiting past d since its first attempt. The first
+// attempt is always made. Zero or negative d means no limit other than the
+// number of attempts.
+func (h *RetryHandler) WithMaxElapsed(d time.Duration) *RetryHandler {
+	clone := *h
+	clone.maxElapsed = max(d, 0)
+
+	return &clone
+}
+
+// Handle passes the record to the inner handler, retrying on error as
+// described on RetryHandler.
+func (h *RetryHandler) Handle(ctx context.Context, r *Record) error {
+	r = forwardedRecord(h.inner, r)
+	err := h.inner.Handle(ctx, r)
+	if err == nil || h.attempts == 1 || errors.Is(err, ErrZeroRecord) || errors.Is(err, ErrHandlerClosed) {
+		return err
+	}
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	start := time.Now()
+	wait := h.backoff
+	for attempt := 1; attempt < h.attempts; attempt++ {
+		if h.maxElapsed > 0 && time.Since(start)+wait > h.maxElapsed {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-done:
+			timer.Stop()
+			return err
+		}
+
+		if err = h.inner.Handle(ctx, r); err == nil {
+			return nil
+		}
+		wait *= 2
+	}
+
+	return err
+}
+
+// Enabled reports whether the inner handler is enabled for level.
+func (h *RetryHandler) Enabled(level LogLevel) bool {
+	return h.inner.Enabled(level)
+}
+
+// HandlerState returns the inner handler's state.
+func (h *RetryHandler) HandlerState() HandlerState {
+	return h.inner.HandlerState()
+}
+
+// Features returns the inner handler's features.
+func (h *RetryHandler) Features() HandlerFeatures {
+	return h.inner.Features()
+}
+
+// Sync flushes the inner handler if it implements Syncer.
+func (h *RetryHandler) Sync() error {
+	if s, ok := h.inner.(Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// WithAttrs returns a handler whose inner handler has the key-value pairs added.
+// It returns the original handler if the inner handler does not implement Chainer.
+func (h *RetryHandler) WithAttrs(keyValues []any) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	clone := *h
+	clone.inner = ch.WithAttrs(keyValues)
+
+	return &clone
+}
+
+// WithGroup returns a handler whose inner handler starts the group.
+// It returns the original handler if the inner handler does not implement Chainer.
+func (h *RetryHandler) WithGroup(name string) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	clone := *h
+	clone.inner = ch.WithGroup(name)
+
+	return &clone
+}
@@ -0,0 +1,150 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// flakyHandler fails its first failures calls with err.
+type flakyHandler struct {
+	failures int
+	err      error
+	calls    atomic.Int32
+}
+
+func (h *flakyHandler) Handle(context.Context, *handler.Record) error {
+	if int(h.calls.Add(1)) <= h.failures {
+		return h.err
+	}
+	return nil
+}
+
+func (h *flakyHandler) Enabled(handler.LogLevel) bool      { return true }
+func (h *flakyHandler) HandlerState() handler.HandlerState { return nil }
+func (h *flakyHandler) Features() handler.HandlerFeatures  { return handler.HandlerFeatures{} }
+
+func TestNewRetryHandler_Validation(t *testing.T) {
+	t.Parallel()
+
+	inner := &flakyHandler{}
+	tests := []struct {
+		name     string
+		inner    handler.Handler
+		attempts int
+		backoff  time.Duration
+	}{
+		{"nil inner", nil, 3, 0},
+		{"zero attempts", inner, 0, 0},
+		{"negative backoff", inner, 3, -time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := handler.NewRetryHandler(tt.inner, tt.attempts, tt.backoff); err == nil {
+				t.Error("NewRetryHandler() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestRetryHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient")
+	r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg"}
+
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		attempts  int
+		wantErr   error
+		wantCalls int32
+	}{
+		{"succeeds first time", 0, errTransient, 3, nil, 1},
+		{"succeeds after retries", 2, errTransient, 3, nil, 3},
+		{"gives up after attempts", 5, errTransient, 3, errTransient, 3},
+		{"no retry when closed", 5, handler.ErrHandlerClosed, 3, handler.ErrHandlerClosed, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inner := &flakyHandler{failures: tt.failures, err: tt.err}
+			h, err := handler.NewRetryHandler(inner, tt.attempts, time.Millisecond)
+			if err != nil {
+				t.Fatalf("NewRetryHandler() error = %v", err)
+			}
+
+			if err := h.Handle(context.Background(), r); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := inner.calls.Load(); got != tt.wantCalls {
+				t.Errorf("inner calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryHandler_Caller(t *testing.T) {
+	t.Parallel()
+
+	testForwardedCaller(t, func(inner handler.Handler) handler.Handler {
+		h, _ := handler.NewRetryHandler(inner, 3, time.Millisecond)
+		return h
+	})
+}
+
+func TestRetryHandler_MaxElapsed(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient")
+	inner := &flakyHandler{failures: 100, err: errTransient}
+	h, err := handler.NewRetryHandler(inner, 100, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewRetryHandler() error = %v", err)
+	}
+	h = h.WithMaxElapsed(50 * time.Millisecond)
+
+	start := time.Now()
+	err = h.Handle(context.Background(), &handler.Record{Time: time.Now(), Message: "msg"})
+	if !errors.Is(err, errTransient) {
+		t.Errorf("Handle() error = %v, want %v", err, errTransient)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Handle() took %s, want at most about 50ms", elapsed)
+	}
+	// Waits of 10ms and 20ms fit in 50ms, the next 40ms does not; a slow
+	// machine may already give up before the second wait
+	if got := inner.calls.Load(); got < 2 || got > 3 {
+		t.Errorf("inner calls = %d, want 2 or 3", got)
+	}
+}
+
+func TestRetryHandler_ContextCancel(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient")
+	inner := &flakyHandler{failures: 100, err: errTransient}
+	h, err := handler.NewRetryHandler(inner, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("NewRetryHandler() error = %v", err)
+	}
+	h = h.WithMaxElapsed(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := h.Handle(ctx, &handler.Record{Time: time.Now(), Message: "msg"}); !errors.Is(err, errTransient) {
+		t.Errorf("Handle() error = %v, want %v", err, errTransient)
+	}
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("inner calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RingBufferHandler wraps a Handler and retains the last N records in memory,
+// regardless of the inner handler's level. Records flow to the inner handler
+// as usual; the buffer can be dumped on demand, e.g. when an unexpected error
+// occurs and the preceding debug records are needed for context.
+//
+// Because every record is buffered, Enabled always reports true and the logger
+// builds records even for levels the inner handler discards. Use it where
+// the extra allocation per call is acceptable.
+//
+// Handlers derived via WithAttrs and WithGroup share the same buffer.
+type RingBufferHandler struct {
+	inner Handler
+	ring  *recordRing
+	attrs attrState
+}
+
+// recordRing is a fixed-capacity circular buffer of records.
+type recordRing struct {
+	mu      sync.Mutex
+	records []Record
+	next    int  // Index of the next slot to write
+	full    bool // True once the buffer has wrapped around
+}
+
+// Ensure RingBufferHandler implements the handler interfaces.
+var (
+	_ Handler = (*RingBufferHandler)(nil)
+	_ Chainer = (*RingBufferHandler)(nil)
+	_ Syncer  = (*RingBufferHandler)(nil)
+)
+
+// NewRingBufferHandler returns a handler that forwards records to inner and
+// retains the last capacity records of any level.
+// Returns error if inner is nil or capacity is not positive.
+func NewRingBufferHandler(inner Handler, capacity int) (*RingBufferHandler, error) {
+	if inner == nil {
+		return nil, ErrNilHandler
+	}
+	if capacity <= 0 {
+		return nil, fmt.Errorf("ring buffer capacity must be positive, got %d", capacity)
+	}
+
+	return &RingBufferHandler{
+		inner: inner,
+		ring:  &recordRing{records: make([]Record, capacity)},
+	}, nil
+}
+
+// Handle buffers a copy of the record and forwards it to the inner handler
+// if the inner handler is enabled for the record's level.
+func (h *RingBufferHandler) Handle(ctx context.Context, r *Record) error {
+	if r.IsZero() {
+		return ErrZeroRecord
+	}
+	h.ring.push(Record{
+		Time:      r.Time,
+		Level:     r.Level,
+		Message:   r.Message,
+		KeyValues: h.attrs.merge(r.KeyValues),
+	})
+
+	if !enabledContext(ctx, h.inner, r.Level) {
+		return nil
+	}
+
+	return h.inner.Handle(ctx, forwardedRecord(h.inner, r))
+}
+
+// Enabled always returns true, so that records of every level are buffered.
+func (h *RingBufferHandler) Enabled(LogLevel) bool {
+	return true
+}
+
+// HandlerState returns the inner handler's state.
+func (h *RingBufferHandler) HandlerState() HandlerState {
+	return h.inner.HandlerState()
+}
+
+// Features returns the inner handler's features.
+func (h *RingBufferHandler) Features() HandlerFeatures {
+	return h.inner.Features()
+}
+
+// WithAttrs returns a new handler with the key-value pairs added.
+// The inner handler is chained if it implements Chainer.
+// The buffer is shared with the original handler.
+func (h *RingBufferHandler) WithAttrs(keyValues []any) Chainer {
+	if len(keyValues) < 2 {
+		return h
+	}
+
+	clone := *h
+	if ch, ok := h.inner.(Chainer); ok {
+		clone.inner = ch.WithAttrs(keyValues)
+	}
+	clone.attrs = h.attrs.withAttrs(keyValues)
+
+	return &clone
+}
+
+// WithGroup returns a new handler that qualifies subsequent keys with name.
+// Buffered keys are prefixed using DefaultKeySeparator.
+// The inner handler is chained if it implements Chainer.
+func (h *RingBufferHandler) WithGroup(name string) Chainer {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	if ch, ok := h.inner.(Chainer); ok {
+		clone.inner = ch.WithGroup(name)
+	}
+	clone.attrs = h.attrs.withGroup(name)
+
+	return &clone
+}
+
+// Sync flushes the inner handler if it implements Syncer.
+func (h *RingBufferHandler) Sync() error {
+	if s, ok := h.inner.(Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Dump writes all buffered records to w as JSON, one object per line,
+// oldest first. The buffer is left intact.
+func (h *RingBufferHandler) Dump(w io.Writer) error {
+	return writeRecordsJSON(w, h.ring.snapshot(false))
+}
+
+// DumpAndClear atomically takes and clears the buffered records, then writes
+// them to w like Dump. Records logged while writing are kept for the next dump.
+// If writing fails, the taken records are lost.
+func (h *RingBufferHandler) DumpAndClear(w io.Writer) error {
+	return writeRecordsJSON(w, h.ring.snapshot(true))
+}
+
+// push stores r, overwriting the oldest record if the buffer is full.
+func (b *recordRing) push(r Record) {
+	b.mu.Lock()
+	b.records[b.next] = r
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+	b.mu.Unlock()
+}
+
+// snapshot returns the buffered records, oldest first, optionally clearing the buffer.
+func (b *recordRing) snapshot(reset bool) []Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []Record
+	if b.full {
+		out = append(out, b.records[b.next:]...)
+	}
+	out = append(out, b.records[:b.next]...)
+
+	if reset {
+		for i := range b.records {
+			b.records[i] = Record{}
+		}
+		b.next = 0
+		b.full = false
+	}
+
+	return out
+}
+
+// writeRecordsJSON writes records to w as newline-delimited JSON objects.
+func writeRecordsJSON(w io.Writer, records []Record) error {
+	var buf bytes.Buffer
+	for i := range records {
+		appendRecordJSON(&buf, &records[i])
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// appendRecordJSON encodes r as a single-line JSON object with time, level,
+// msg and the record's key-value pairs, in that order.
+func appendRecordJSON(buf *bytes.Buffer, r *Record) {
+	buf.WriteString(`{"time":`)
+	writeJSONValue(buf, r.Time.Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	appendJSONFields(buf, r)
+	buf.WriteString("}\n")
+}
+
+// appendJSONFields encodes the level, msg and key-value pairs of r as
+// comma-separated JSON object members, without the enclosing braces.
+func appendJSONFields(buf *bytes.Buffer, r *Record) {
+	buf.WriteString(`"level":`)
+	writeJSONValue(buf, r.Level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSONValue(buf, r.Message)
+
+	for i := 0; i < len(r.KeyValues)-1; i += 2 {
+		buf.WriteByte(',')
+		writeJSONValue(buf, fmt.Sprint(r.KeyValues[i]))
+		buf.WriteByte(':')
+		writeJSONValue(buf, r.KeyValues[i+1])
+	}
+}
+
+// writeJSONValue encodes v as JSON. Errors are encoded as their message and
+// values that cannot be marshaled fall back to FormatValue.
+func writeJSONValue(buf *bytes.Buffer, v any) {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(FormatValue(v))
+	}
+	buf.Write(data)
+}
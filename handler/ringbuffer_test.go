@@ -0,0 +1,271 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// recordingHandler records the messages it handles at or above level.
+type recordingHandler struct {
+	mu       sync.Mutex
+	level    handler.LogLevel
+	messages []string
+	syncs    int
+}
+
+var (
+	_ handler.Handler = (*recordingHandler)(nil)
+	_ handler.Syncer  = (*recordingHandler)(nil)
+)
+
+func (h *recordingHandler) Handle(_ context.Context, r *handler.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, r.Message)
+	return nil
+}
+
+func (h *recordingHandler) Enabled(level handler.LogLevel) bool { return level >= h.level }
+func (h *recordingHandler) HandlerState() handler.HandlerState  { return nil }
+func (h *recordingHandler) Features() handler.HandlerFeatures   { return handler.HandlerFeatures{} }
+
+func (h *recordingHandler) Sync() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.syncs++
+	return nil
+}
+
+func (h *recordingHandler) Syncs() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.syncs
+}
+
+func (h *recordingHandler) Messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.messages...)
+}
+
+// dumpMessages decodes NDJSON output into one map per line.
+func dumpMessages(t *testing.T, data string) []map[string]any {
+	t.Helper()
+
+	var out []map[string]any
+	for line := range strings.Lines(data) {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+func newRecord(level handler.LogLevel, msg string, keyValues ...any) *handler.Record {
+	return &handler.Record{Time: time.Now(), Level: level, Message: msg, KeyValues: keyValues}
+}
+
+func TestNewRingBufferHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := handler.NewRingBufferHandler(nil, 10); !errors.Is(err, handler.ErrNilHandler) {
+		t.Errorf("NewRingBufferHandler(nil) error = %v, want ErrNilHandler", err)
+	}
+	if _, err := handler.NewRingBufferHandler(&recordingHandler{}, 0); err == nil {
+		t.Error("NewRingBufferHandler(capacity 0) error = nil, want error")
+	}
+}
+
+func TestRingBufferHandler_RetainsLastN(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 3
+	inner := &recordingHandler{level: handler.InfoLevel}
+	h, err := handler.NewRingBufferHandler(inner, capacity)
+	if err != nil {
+		t.Fatalf("NewRingBufferHandler() error = %v", err)
+	}
+
+	if !h.Enabled(handler.TraceLevel) {
+		t.Error("Enabled(TraceLevel) = false, want true")
+	}
+
+	ctx := context.Background()
+	for i := range 5 {
+		if err := h.Handle(ctx, newRecord(handler.DebugLevel, fmt.Sprintf("debug-%d", i), "i", i)); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+
+	if got := inner.Messages(); len(got) != 0 {
+		t.Errorf("inner received %v, want no debug records", got)
+	}
+
+	var buf bytes.Buffer
+	if err := h.Dump(&buf); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	lines := dumpMessages(t, buf.String())
+	if len(lines) != capacity {
+		t.Fatalf("dumped %d records, want %d", len(lines), capacity)
+	}
+	for i, line := range lines {
+		want := fmt.Sprintf("debug-%d", i+2)
+		if line["msg"] != want {
+			t.Errorf("record %d msg = %v, want %q", i, line["msg"], want)
+		}
+		if line["level"] != "DEBUG" {
+			t.Errorf("record %d level = %v, want DEBUG", i, line["level"])
+		}
+		if line["i"] != float64(i+2) {
+			t.Errorf("record %d i = %v, want %d", i, line["i"], i+2)
+		}
+	}
+
+	// Dump leaves the buffer intact
+	buf.Reset()
+	_ = h.Dump(&buf)
+	if got := len(dumpMessages(t, buf.String())); got != capacity {
+		t.Errorf("second Dump() records = %d, want %d", got, capacity)
+	}
+}
+
+func TestRingBufferHandler_ForwardsEnabled(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{level: handler.WarnLevel}
+	h, _ := handler.NewRingBufferHandler(inner, 10)
+
+	ctx := context.Background()
+	_ = h.Handle(ctx, newRecord(handler.InfoLevel, "info"))
+	_ = h.Handle(ctx, newRecord(handler.ErrorLevel, "error", "err", errors.New("boom")))
+
+	if got := inner.Messages(); len(got) != 1 || got[0] != "error" {
+		t.Errorf("inner messages = %v, want [error]", got)
+	}
+
+	var buf bytes.Buffer
+	_ = h.Dump(&buf)
+	lines := dumpMessages(t, buf.String())
+	if len(lines) != 2 {
+		t.Fatalf("dumped %d records, want 2", len(lines))
+	}
+	if lines[1]["err"] != "boom" {
+		t.Errorf("err = %v, want %q", lines[1]["err"], "boom")
+	}
+
+	if err := h.Sync(); err != nil || inner.syncs != 1 {
+		t.Errorf("Sync() error = %v, inner syncs = %d, want nil and 1", err, inner.syncs)
+	}
+}
+
+func TestRingBufferHandler_Caller(t *testing.T) {
+	t.Parallel()
+
+	testForwardedCaller(t, func(inner handler.Handler) handler.Handler {
+		h, _ := handler.NewRingBufferHandler(inner, 4)
+		return h
+	})
+}
+
+func TestRingBufferHandler_DumpAndClear(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewRingBufferHandler(&recordingHandler{}, 2)
+	ctx := context.Background()
+	_ = h.Handle(ctx, newRecord(handler.InfoLevel, "a"))
+	_ = h.Handle(ctx, newRecord(handler.InfoLevel, "b"))
+	_ = h.Handle(ctx, newRecord(handler.InfoLevel, "c"))
+
+	var buf bytes.Buffer
+	if err := h.DumpAndClear(&buf); err != nil {
+		t.Fatalf("DumpAndClear() error = %v", err)
+	}
+	if got := len(dumpMessages(t, buf.String())); got != 2 {
+		t.Errorf("DumpAndClear() records = %d, want 2", got)
+	}
+
+	buf.Reset()
+	_ = h.Dump(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("Dump() after clear = %q, want empty", buf.String())
+	}
+
+	_ = h.Handle(ctx, newRecord(handler.InfoLevel, "d"))
+	_ = h.Dump(&buf)
+	if lines := dumpMessages(t, buf.String()); len(lines) != 1 || lines[0]["msg"] != "d" {
+		t.Errorf("Dump() after refill = %v, want [d]", lines)
+	}
+}
+
+func TestRingBufferHandler_Chaining(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewRingBufferHandler(&recordingHandler{}, 10)
+	child := h.WithAttrs([]any{"svc", "api"}).WithGroup("req").WithAttrs([]any{"id", 7})
+
+	if h.WithAttrs(nil) != h || h.WithGroup("") != h {
+		t.Error("empty WithAttrs/WithGroup should return the original handler")
+	}
+
+	_ = child.Handle(context.Background(), newRecord(handler.InfoLevel, "msg", "path", "/"))
+
+	var buf bytes.Buffer
+	_ = h.Dump(&buf) // Buffer is shared with the parent
+	lines := dumpMessages(t, buf.String())
+	if len(lines) != 1 {
+		t.Fatalf("dumped %d records, want 1", len(lines))
+	}
+	want := map[string]any{"svc": "api", "req_id": float64(7), "req_path": "/"}
+	for k, v := range want {
+		if lines[0][k] != v {
+			t.Errorf("%s = %v, want %v", k, lines[0][k], v)
+		}
+	}
+}
+
+func TestRingBufferHandler_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewRingBufferHandler(&recordingHandler{}, 16)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				_ = h.Handle(ctx, newRecord(handler.InfoLevel, "m", "g", i, "j", j))
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var buf bytes.Buffer
+		for range 10 {
+			_ = h.Dump(&buf)
+		}
+	}()
+	wg.Wait()
+
+	var buf bytes.Buffer
+	_ = h.Dump(&buf)
+	if got := len(dumpMessages(t, buf.String())); got != 16 {
+		t.Errorf("records = %d, want 16", got)
+	}
+}
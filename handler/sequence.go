@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// SequenceKey is the attribute key of a record's sequence number, as read
+// by SequenceVerifier.
+const SequenceKey = "seq"
+
+// Messages of the records SequenceVerifier emits on a discontinuity.
+const (
+	SequenceGapMessage        = "log sequence gap"
+	SequenceOutOfOrderMessage = "log sequence out of order"
+)
+
+// SequenceVerifier wraps a Handler and checks that the sequence numbers of
+// the records it forwards increase by one, to debug pipelines that drop or
+// reorder records. Before forwarding a record whose sequence number skips
+// ahead, it sends a WARN record with SequenceGapMessage; before forwarding
+// one whose number is not above the highest seen so far, a WARN record with
+// SequenceOutOfOrderMessage. Both carry "seq_expected" and "seq_received".
+//
+// The sequence number is the record's own SequenceKey attribute, of any
+// integer type. Records without one are forwarded unchecked.
+//
+// Records logged concurrently may reach the verifier in a different order
+// than they were numbered, which is reported as a gap followed by an
+// out-of-order record. Handlers derived via WithAttrs and WithGroup share
+// the sequence state. All methods are safe for concurrent use.
+type SequenceVerifier struct {
+	inner Handler
+	state *sequenceState
+}
+
+// sequenceState is the sequence state shared by derived verifiers.
+type sequenceState struct {
+	mu   sync.Mutex
+	seen bool   // False until the first numbered record
+	last uint64 // Highest sequence number seen
+}
+
+// Ensure SequenceVerifier implements the handler interfaces.
+var (
+	_ Handler = (*SequenceVerifier)(nil)
+	_ Chainer = (*SequenceVerifier)(nil)
+	_ Syncer  = (*SequenceVerifier)(nil)
+)
+
+// NewSequenceVerifier returns a handler that checks the sequence numbers of
+// the records passed to inner.
+// Returns error if inner is nil.
+func NewSequenceVerifier(inner Handler) (*SequenceVerifier, error) {
+	if inner == nil {
+		return nil, ErrNilHandler
+	}
+
+	return &SequenceVerifier{inner: inner, state: &sequenceState{}}, nil
+}
+
+// Handle checks the record's sequence number, sends a WARN record to the
+// inner handler if it is not the expected one, then forwards the record.
+func (h *SequenceVerifier) Handle(ctx context.Context, r *Record) error {
+	if r.IsZero() {
+		return ErrZeroRecord
+	}
+	r = forwardedRecord(h.inner, r)
+
+	seq, ok := recordSequence(r.KeyValues)
+	if !ok {
+		return h.inner.Handle(ctx, r)
+	}
+
+	expected, inOrder := h.state.observe(seq)
+	if inOrder {
+		return h.inner.Handle(ctx, r)
+	}
+
+	msg := SequenceGapMessage
+	if seq < expected {
+		msg = SequenceOutOfOrderMessage
+	}
+
+	var errWarn error
+	if h.inner.Enabled(WarnLevel) {
+		errWarn = h.inner.Handle(ctx, &Record{
+			Time:      r.Time,
+			Level:     WarnLevel,
+			Message:   msg,
+			KeyValues: []any{"seq_expected", expected, "seq_received", seq},
+		})
+	}
+
+	return errors.Join(errWarn, h.inner.Handle(ctx, r))
+}
+
+// observe records seq and returns the sequence number that was expected,
+// reporting whether seq is that number. The first number seen is always
+// expected.
+func (s *sequenceState) observe(seq uint64) (expected uint64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.seen {
+		s.seen = true
+		s.last = seq
+		return seq, true
+	}
+
+	expected = s.last + 1
+	if seq > s.last {
+		s.last = seq
+	}
+
+	return expected, seq == expected
+}
+
+// recordSequence returns the value of the first SequenceKey attribute in
+// keyValues as a uint64, reporting false if there is none or it is not a
+// non-negative integer.
+func recordSequence(keyValues []any) (uint64, bool) {
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		if key, ok := keyValues[i].(string); !ok || key != SequenceKey {
+			continue
+		}
+
+		switch v := keyValues[i+1].(type) {
+		case uint64:
+			return v, true
+		case uint:
+			return uint64(v), true
+		case uint32:
+			return uint64(v), true
+		case int:
+			return uint64(v), v >= 0
+		case int64:
+			return uint64(v), v >= 0
+		case int32:
+			return uint64(v), v >= 0
+		default:
+			return 0, false
+		}
+	}
+
+	return 0, false
+}
+
+// Enabled reports whether the inner handler is enabled for level.
+func (h *SequenceVerifier) Enabled(level LogLevel) bool {
+	return h.inner.Enabled(level)
+}
+
+// HandlerState returns the inner handler's state.
+func (h *SequenceVerifier) HandlerState() HandlerState {
+	return h.inner.HandlerState()
+}
+
+// Features returns the inner handler's features.
+func (h *SequenceVerifier) Features() HandlerFeatures {
+	return h.inner.Features()
+}
+
+// Sync flushes the inner handler if it implements Syncer.
+func (h *SequenceVerifier) Sync() error {
+	if s, ok := h.inner.(Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// WithAttrs returns a handler whose inner handler has the key-value pairs added.
+// It returns the original handler if the inner handler does not implement Chainer.
+// The sequence state is shared with the original handler.
+func (h *SequenceVerifier) WithAttrs(keyValues []any) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	return &SequenceVerifier{inner: ch.WithAttrs(keyValues), state: h.state}
+}
+
+// WithGroup returns a handler whose inner handler starts the group.
+// It returns the original handler if the inner handler does not implement Chainer.
+// The sequence state is shared with the original handler.
+func (h *SequenceVerifier) WithGroup(name string) Chainer {
+	ch, ok := h.inner.(Chainer)
+	if !ok {
+		return h
+	}
+
+	return &SequenceVerifier{inner: ch.WithGroup(name), state: h.state}
+}
@@ -0,0 +1,127 @@
+package handler_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestNewSequenceVerifier_NilInner(t *testing.T) {
+	t.Parallel()
+
+	if _, err := handler.NewSequenceVerifier(nil); err == nil {
+		t.Error("NewSequenceVerifier(nil) error = nil, want error")
+	}
+}
+
+func TestSequenceVerifier_Handle(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{}
+	h, err := handler.NewSequenceVerifier(inner)
+	if err != nil {
+		t.Fatalf("NewSequenceVerifier() error = %v", err)
+	}
+
+	records := []struct {
+		msg string
+		kv  []any
+	}{
+		{"first", []any{handler.SequenceKey, 7}},
+		{"second", []any{"user", "jane", handler.SequenceKey, int64(8)}},
+		{"unnumbered", []any{"user", "jane"}},
+		{"not an integer", []any{handler.SequenceKey, "9"}},
+		{"after gap", []any{handler.SequenceKey, uint64(10)}},
+		{"late", []any{handler.SequenceKey, uint(9)}},
+		{"next", []any{handler.SequenceKey, 11}},
+	}
+	for _, rec := range records {
+		r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: rec.msg, KeyValues: rec.kv}
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle(%q) error = %v", rec.msg, err)
+		}
+	}
+
+	want := []string{
+		"first",
+		"second",
+		"unnumbered",
+		"not an integer",
+		handler.SequenceGapMessage,
+		"after gap",
+		handler.SequenceOutOfOrderMessage,
+		"late",
+		"next",
+	}
+	if got := inner.Messages(); !slices.Equal(got, want) {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestSequenceVerifier_Caller(t *testing.T) {
+	t.Parallel()
+
+	testForwardedCaller(t, func(inner handler.Handler) handler.Handler {
+		h, _ := handler.NewSequenceVerifier(inner)
+		return h
+	})
+}
+
+func TestSequenceVerifier_WarnDisabled(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{level: handler.ErrorLevel}
+	h, _ := handler.NewSequenceVerifier(inner)
+
+	for _, seq := range []int{1, 3} {
+		r := &handler.Record{Time: time.Now(), Level: handler.ErrorLevel, Message: "msg", KeyValues: []any{handler.SequenceKey, seq}}
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+
+	if got := inner.Messages(); !slices.Equal(got, []string{"msg", "msg"}) {
+		t.Errorf("messages = %q, want only the logged records", got)
+	}
+}
+
+func TestSequenceVerifier_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{}
+	h, _ := handler.NewSequenceVerifier(inner)
+	derived := h.WithAttrs([]any{"k", "v"}).(handler.Handler)
+
+	var (
+		mu  sync.Mutex
+		seq int
+		wg  sync.WaitGroup
+	)
+	const goroutines, perGoroutine = 8, 50
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				// Number and handle under one lock, so the order is preserved
+				mu.Lock()
+				seq++
+				r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", KeyValues: []any{handler.SequenceKey, seq}}
+				err := derived.Handle(context.Background(), r)
+				mu.Unlock()
+				if err != nil {
+					t.Errorf("Handle() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(inner.Messages()); got != goroutines*perGoroutine {
+		t.Errorf("handled %d records, want %d without gaps", got, goroutines*perGoroutine)
+	}
+}
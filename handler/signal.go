@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// FlushOnSignal starts a goroutine that calls Sync on h whenever one of sigs
+// arrives, e.g. SIGUSR1 to flush buffered handlers on demand with
+// `kill -USR1 <pid>`. Sync errors are ignored. The returned function stops
+// the goroutine and deregisters the signals; it is idempotent.
+//
+// Signal delivery is shared: channels registered by the application with
+// signal.Notify for the same signals still receive them. As with any use of
+// signal.Notify, the signals' default action (such as terminating the
+// process on SIGUSR1) is disabled until stop is called and no other
+// registration remains.
+//
+// If h does not implement Syncer or sigs is empty, nothing is registered and
+// stop does nothing.
+func FlushOnSignal(h Handler, sigs ...os.Signal) (stop func()) {
+	s, ok := h.(Syncer)
+	if !ok || len(sigs) == 0 {
+		return func() {}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})    // Closed by stop
+	stopped := make(chan struct{}) // Closed when the goroutine returns
+
+	signal.Notify(ch, sigs...)
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ch:
+				_ = s.Sync()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+			<-stopped
+		})
+	}
+}
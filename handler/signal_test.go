@@ -0,0 +1,42 @@
+//go:build unix
+
+package handler_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestFlushOnSignal(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingHandler{}
+	stop := handler.FlushOnSignal(inner, syscall.SIGUSR1)
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for inner.Syncs() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Sync was not called after the signal")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	stop()
+	stop()
+}
+
+func TestFlushOnSignal_NotSyncer(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewMemoryHandler(1024)
+	stop := handler.FlushOnSignal(h, syscall.SIGUSR1)
+	stop()
+}
@@ -0,0 +1,31 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestWithKeyCase_Group(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h, err := New(WithOutput(&buf), WithKeyCase(handler.KeyCaseSnake))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	g := h.(handler.Chainer).WithGroup("HTTPRequest")
+	r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", KeyValues: []any{"statusCode", 200}}
+	if err := g.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, `"http_request":{"status_code":200}`) {
+		t.Errorf("output = %s, want group http_request with key status_code", out)
+	}
+}
@@ -0,0 +1,179 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestUnilogLevelToSlog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level handler.LogLevel
+		want  slog.Level
+	}{
+		{handler.TraceLevel, slog.Level(-8)},
+		{handler.DebugLevel, slog.LevelDebug},
+		{handler.InfoLevel, slog.LevelInfo},
+		{handler.WarnLevel, slog.LevelWarn},
+		{handler.ErrorLevel, slog.LevelError},
+		{handler.CriticalLevel, slog.Level(12)},
+		{handler.FatalLevel, slog.Level(16)},
+		{handler.PanicLevel, slog.Level(20)},
+	}
+
+	for _, tt := range tests {
+		if got := unilogLevelToSlog(tt.level); got != tt.want {
+			t.Errorf("unilogLevelToSlog(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestSlogLevelToUnilog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level slog.Level
+		want  handler.LogLevel
+	}{
+		{slog.Level(-100), handler.TraceLevel},
+		{slog.Level(-9), handler.TraceLevel},
+		{slog.Level(-8), handler.TraceLevel},
+		{slog.Level(-7), handler.TraceLevel},
+		{slog.Level(-6), handler.DebugLevel},
+		{slog.LevelDebug, handler.DebugLevel},
+		{slog.Level(-1), handler.InfoLevel},
+		{slog.LevelInfo, handler.InfoLevel},
+		{slog.Level(1), handler.InfoLevel},
+		{slog.Level(2), handler.WarnLevel},
+		{slog.LevelWarn, handler.WarnLevel},
+		{slog.Level(5), handler.WarnLevel},
+		{slog.Level(6), handler.ErrorLevel},
+		{slog.LevelError, handler.ErrorLevel},
+		{slog.Level(12), handler.CriticalLevel},
+		{slog.Level(16), handler.FatalLevel},
+		{slog.Level(20), handler.PanicLevel},
+		{slog.Level(100), handler.PanicLevel},
+	}
+
+	for _, tt := range tests {
+		if got := slogLevelToUnilog(tt.level); got != tt.want {
+			t.Errorf("slogLevelToUnilog(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestSlogLevel_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for level := handler.MinLevel; level <= handler.MaxLevel; level++ {
+		if got := slogLevelToUnilog(unilogLevelToSlog(level)); got != level {
+			t.Errorf("round trip of %v = %v", level, got)
+		}
+	}
+}
+
+func TestWithSlogLevel(t *testing.T) {
+	t.Parallel()
+
+	h, err := New(WithOutput(&bytes.Buffer{}), WithSlogLevel(slog.Level(6)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if h.Enabled(handler.WarnLevel) {
+		t.Error("Enabled(WarnLevel) = true, want false")
+	}
+	if !h.Enabled(handler.ErrorLevel) {
+		t.Error("Enabled(ErrorLevel) = false, want true")
+	}
+}
+
+func TestWithEnabledLevels(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h, err := New(WithOutput(&buf), WithEnabledLevels(handler.TraceLevel, handler.ErrorLevel))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, level := range []handler.LogLevel{handler.TraceLevel, handler.InfoLevel, handler.ErrorLevel} {
+		_ = h.Handle(context.Background(), &handler.Record{Level: level, Message: "msg-" + level.String()})
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "msg-TRACE") || !strings.Contains(out, "msg-ERROR") {
+		t.Errorf("output = %q, want TRACE and ERROR records", out)
+	}
+	if strings.Contains(out, "msg-INFO") {
+		t.Errorf("output = %q, want no INFO record", out)
+	}
+}
+
+func TestWithLevelNames(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range validFormats {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			var seen string
+			h, err := New(
+				WithOutput(&buf),
+				WithFormat(format),
+				WithLevelNames(map[handler.LogLevel]string{handler.WarnLevel: "WARNING"}),
+				WithReplaceAttr(func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.LevelKey {
+						seen = a.Value.String()
+					}
+					return a
+				}),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			_ = h.Handle(context.Background(), &handler.Record{Level: handler.WarnLevel, Message: "w"})
+			if !strings.Contains(buf.String(), "WARNING") {
+				t.Errorf("output = %q, want renamed level WARNING", buf.String())
+			}
+			if seen != "WARNING" {
+				t.Errorf("user ReplaceAttr saw level %q, want WARNING", seen)
+			}
+
+			buf.Reset()
+			_ = h.Handle(context.Background(), &handler.Record{Level: handler.ErrorLevel, Message: "e"})
+			if !strings.Contains(buf.String(), "ERROR") {
+				t.Errorf("output = %q, want canonical level ERROR", buf.String())
+			}
+		})
+	}
+}
+
+func TestWithTraceLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h, err := New(WithOutput(&buf), WithTrace(true), WithTraceLevel(handler.CriticalLevel))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_ = h.Handle(context.Background(), &handler.Record{Level: handler.ErrorLevel, Message: "e"})
+	if strings.Contains(buf.String(), `"stack"`) {
+		t.Errorf("ERROR below trace level carried a stack: %q", buf.String())
+	}
+
+	buf.Reset()
+	_ = h.Handle(context.Background(), &handler.Record{Level: handler.CriticalLevel, Message: "c"})
+	if !strings.Contains(buf.String(), `"stack"`) {
+		t.Errorf("CRITICAL at trace level has no stack: %q", buf.String())
+	}
+}
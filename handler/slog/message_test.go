@@ -0,0 +1,68 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestWithHumanMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(WithHumanMessageTemplate("{{.user")); err == nil {
+		t.Error("New() error = nil, want template parse error")
+	}
+
+	record := &handler.Record{
+		Time:      time.Now(),
+		Level:     handler.InfoLevel,
+		Message:   "login",
+		KeyValues: []any{"user", "jane"},
+	}
+
+	t.Run("json keeps the message", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		h, err := New(
+			WithOutput(&buf),
+			WithFormat("json"),
+			WithHumanMessageTemplate("{{.user}} logged in"),
+			WithHumanMessageKey("human"),
+		)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if err := h.Handle(context.Background(), record); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+
+		var got map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+		}
+		if got["msg"] != "login" || got["human"] != "jane logged in" || got["user"] != "jane" {
+			t.Errorf("output = %v, want original msg, rendered human and user fields", got)
+		}
+	})
+
+	t.Run("text replaces the message", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		h, err := New(WithOutput(&buf), WithFormat("text"), WithHumanMessageTemplate("{{.user}} logged in"))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if err := h.Handle(context.Background(), record); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+
+		if out := buf.String(); !strings.Contains(out, `msg="jane logged in"`) || !strings.Contains(out, "user=jane") {
+			t.Errorf("output = %q, want the rendered message and the user field", out)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestWithPrettyJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		derive func(h handler.Handler, buf *bytes.Buffer) (handler.Handler, *bytes.Buffer)
+	}{
+		{"new", func(h handler.Handler, buf *bytes.Buffer) (handler.Handler, *bytes.Buffer) { return h, buf }},
+		{"with attrs", func(h handler.Handler, buf *bytes.Buffer) (handler.Handler, *bytes.Buffer) {
+			return h.(handler.Chainer).WithAttrs([]any{"service", "api"}).(handler.Handler), buf
+		}},
+		{"with output", func(h handler.Handler, _ *bytes.Buffer) (handler.Handler, *bytes.Buffer) {
+			var out bytes.Buffer
+			return h.(handler.Configurable).WithOutput(&out).(handler.Handler), &out
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var initial bytes.Buffer
+			h, err := New(WithOutput(&initial), WithPrettyJSON(true))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			h, buf := tt.derive(h, &initial)
+
+			for range 2 {
+				r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", KeyValues: []any{"k", "v"}}
+				if err := h.Handle(context.Background(), r); err != nil {
+					t.Fatalf("Handle() error = %v", err)
+				}
+			}
+
+			out := buf.String()
+			if !strings.Contains(out, "{\n  \"time\": ") || !strings.Contains(out, "\n  \"k\": \"v\"") {
+				t.Errorf("output is not indented:\n%s", out)
+			}
+			if !strings.HasSuffix(out, "}\n") || strings.Count(out, "}\n{") != 1 {
+				t.Errorf("records do not end with a newline:\n%s", out)
+			}
+			dec := json.NewDecoder(buf)
+			for i := range 2 {
+				var v map[string]any
+				if err := dec.Decode(&v); err != nil {
+					t.Fatalf("record %d: Decode() error = %v", i, err)
+				}
+			}
+		})
+	}
+}
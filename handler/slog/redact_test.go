@@ -0,0 +1,41 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestWithRedactPattern(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h, err := New(WithOutput(&buf), WithRedactPattern(regexp.MustCompile(`\d{3}-\d{2}-\d{4}`), "[SSN]"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	h = h.(handler.Chainer).WithAttrs([]any{"owner_ssn", "987-65-4321"}).(handler.Handler)
+	r := &handler.Record{
+		Time:      time.Now(),
+		Level:     handler.InfoLevel,
+		Message:   "lookup 123-45-6789",
+		KeyValues: []any{"ssn", "123-45-6789", "n", 1},
+	}
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "6789") || strings.Contains(out, "4321") {
+		t.Errorf("output leaks a redacted value: %s", out)
+	}
+	if strings.Count(out, "[SSN]") != 3 {
+		t.Errorf("output = %s, want message and both values redacted", out)
+	}
+}
@@ -1,12 +1,14 @@
 package slog
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
-	"runtime/debug"
+	"regexp"
 
 	"github.com/balinomad/go-unilog/handler"
 )
@@ -18,6 +20,7 @@ var validFormats = []string{"json", "text"}
 type slogOptions struct {
 	base        *handler.BaseOptions
 	replaceAttr func([]string, slog.Attr) slog.Attr // slog-specific option
+	pretty      bool                                // Indent JSON output
 }
 
 // SlogOption configures slog logger creation.
@@ -30,6 +33,25 @@ func WithLevel(level handler.LogLevel) SlogOption {
 	}
 }
 
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR only.
+// The minimum level becomes the lowest enabled level.
+func WithEnabledLevels(levels ...handler.LogLevel) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithEnabledLevels(levels...)(o.base)
+	}
+}
+
+// WithSlogLevel sets the minimum log level from a [slog.Level].
+// Levels between the named slog constants are rounded to the nearest
+// unilog level, so libraries using extended slog levels can configure
+// the handler with their native values.
+func WithSlogLevel(level slog.Level) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithLevel(slogLevelToUnilog(level))(o.base)
+	}
+}
+
 // WithOutput sets the output writer.
 func WithOutput(w io.Writer) SlogOption {
 	return func(o *slogOptions) error {
@@ -37,6 +59,14 @@ func WithOutput(w io.Writer) SlogOption {
 	}
 }
 
+// WithFailFast makes New return an error if the output is not writable,
+// instead of failing on the first log. The default value is false.
+func WithFailFast(enabled bool) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithFailFast(enabled)(o.base)
+	}
+}
+
 // WithFormat sets the output format ("json" or "text").
 func WithFormat(format string) SlogOption {
 	return func(o *slogOptions) error {
@@ -55,13 +85,149 @@ func WithCaller(enabled bool) SlogOption {
 	}
 }
 
-// WithTrace enables stack traces for ERROR and above.
+// WithCallerShortPath trims the caller file to its package directory and
+// file name, e.g. "server/http.go", instead of the absolute path.
+// The default value is false.
+func WithCallerShortPath(enabled bool) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithCallerShortPath(enabled)(o.base)
+	}
+}
+
+// WithCallerLevel sets the minimum level that carries caller information
+// when caller reporting is enabled. The default value is handler.MinLevel.
+func WithCallerLevel(level handler.LogLevel) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithCallerLevel(level)(o.base)
+	}
+}
+
+// WithDebugFriendlyDefaults drops records below INFO, reports the caller
+// from WARN and adds a stack trace from ERROR.
+// See handler.WithDebugFriendlyDefaults.
+func WithDebugFriendlyDefaults() SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithDebugFriendlyDefaults()(o.base)
+	}
+}
+
+// WithTrace enables stack traces for ERROR and above, or for the level
+// set with WithTraceLevel.
 func WithTrace(enabled bool) SlogOption {
 	return func(o *slogOptions) error {
 		return handler.WithTrace(enabled)(o.base)
 	}
 }
 
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. The default value is handler.DefaultTraceLevel.
+func WithTraceLevel(level handler.LogLevel) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithTraceLevel(level)(o.base)
+	}
+}
+
+// WithMaxStackDepth limits stack traces to the top n frames closest to the
+// log call site. The default value is handler.DefaultMaxStackDepth.
+func WithMaxStackDepth(n int) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithMaxStackDepth(n)(o.base)
+	}
+}
+
+// WithOutputSwapHook registers fn to be called after SetOutput replaced the
+// output writer, e.g. to close the old writer. Writes made after fn is
+// called go to the new writer.
+func WithOutputSwapHook(fn func(old, new io.Writer)) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithOutputSwapHook(fn)(o.base)
+	}
+}
+
+// WithFieldValidator registers fn to check every field key. Rejected keys
+// are logged in a sanitized snake_case form, with the original kept in an
+// "_invalid_field_<n>" field.
+func WithFieldValidator(fn func(key string) error) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithFieldValidator(fn)(o.base)
+	}
+}
+
+// WithMaxLineBytes truncates every rendered record to at most n bytes.
+// JSON records keep their leading fields and end with "_truncated":true.
+// See handler.WithMaxLineBytes.
+func WithMaxLineBytes(n int) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithMaxLineBytes(n)(o.base)
+	}
+}
+
+// WithKeyCase normalizes attribute keys and group names to the given case,
+// e.g. handler.KeyCaseSnake renders both "requestID" and "request-id" as
+// "request_id". The default value is handler.KeyCaseAsIs.
+func WithKeyCase(c handler.KeyCase) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithKeyCase(c)(o.base)
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record.
+func WithMetricsProvider(p handler.MetricsProvider) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithMetricsProvider(p)(o.base)
+	}
+}
+
+// WithLevelNames overrides the rendered value of the top-level "level"
+// attribute. Levels without an entry keep their canonical unilog names.
+// A function set with WithReplaceAttr still runs, after the rename.
+func WithLevelNames(names map[handler.LogLevel]string) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithLevelNames(names)(o.base)
+	}
+}
+
+// WithHumanMessageTemplate sets a text/template rendering a readable message
+// from each record's attributes, e.g. "{{.user}} logged in from {{.ip}}".
+// The text format shows it instead of the original message; the JSON format
+// keeps the original and adds the rendered one (see WithHumanMessageKey).
+// Returns error if the template cannot be parsed.
+func WithHumanMessageTemplate(tmpl string) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithHumanMessageTemplate(tmpl)(o.base)
+	}
+}
+
+// WithHumanMessageKey sets the key of the rendered message in JSON output.
+// The default value is handler.DefaultHumanMessageKey.
+func WithHumanMessageKey(key string) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithHumanMessageKey(key)(o.base)
+	}
+}
+
+// WithRedactPattern replaces every match of re in messages and string values
+// with replacement, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString. Patterns run on every record, so keep
+// them few. See handler.WithRedactPattern.
+func WithRedactPattern(re *regexp.Regexp, replacement string) SlogOption {
+	return func(o *slogOptions) error {
+		return handler.WithRedactPattern(re, replacement)(o.base)
+	}
+}
+
+// WithPrettyJSON indents each JSON record over several lines for reading
+// during local development. It has no effect with the text format.
+// Pretty output is no longer newline-delimited JSON and breaks log shippers
+// and parsers expecting one record per line: never enable it in production.
+// The default value is false.
+func WithPrettyJSON(enabled bool) SlogOption {
+	return func(o *slogOptions) error {
+		o.pretty = enabled
+		return nil
+	}
+}
+
 // WithReplaceAttr sets a custom attribute transformation function.
 func WithReplaceAttr(fn func([]string, slog.Attr) slog.Attr) SlogOption {
 	return func(o *slogOptions) error {
@@ -77,6 +243,7 @@ type slogHandler struct {
 	level       *slog.LevelVar
 	handler     slog.Handler
 	replaceAttr func([]string, slog.Attr) slog.Attr
+	pretty      bool
 
 	// Cached from base for lock-free hot-path
 	withCaller bool
@@ -91,6 +258,7 @@ var (
 	_ handler.CallerAdjuster = (*slogHandler)(nil)
 	_ handler.FeatureToggler = (*slogHandler)(nil)
 	_ handler.MutableConfig  = (*slogHandler)(nil)
+	_ handler.RawWriter      = (*slogHandler)(nil)
 )
 
 // levelMapper maps unilog log levels to slog log levels.
@@ -105,11 +273,89 @@ var levelMapper = handler.NewLevelMapper(
 	slog.Level(20),  // Panic
 )
 
+// slogLevelStep is the distance between adjacent mapped slog levels.
+const slogLevelStep = 4
+
+// unilogLevelToSlog converts a unilog level to the exact slog level used by this handler.
+func unilogLevelToSlog(level handler.LogLevel) slog.Level {
+	return levelMapper.Map(level)
+}
+
+// slogLevelToUnilog converts any slog level to the nearest unilog level.
+// slog allows arbitrary integer levels; values between the mapped levels are
+// rounded to the nearest one, with ties resolved toward the more severe level.
+// Values outside the mapped range are clamped to MinLevel or MaxLevel.
+func slogLevelToUnilog(level slog.Level) handler.LogLevel {
+	offset := int(level) - int(unilogLevelToSlog(handler.MinLevel))
+	mapped := handler.MinLevel + handler.LogLevel((offset+slogLevelStep/2)/slogLevelStep)
+
+	return min(max(mapped, handler.MinLevel), handler.MaxLevel)
+}
+
+// levelNameReplacer returns a ReplaceAttr function that renders the
+// top-level level attribute with base.LevelName and then delegates to next,
+// if any.
+func levelNameReplacer(base *handler.BaseHandler, next func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.LevelKey {
+			if level, ok := a.Value.Any().(slog.Level); ok {
+				a.Value = slog.StringValue(base.LevelName(slogLevelToUnilog(level)))
+			}
+		}
+		if next != nil {
+			return next(groups, a)
+		}
+
+		return a
+	}
+}
+
+// shortSourceReplacer returns a ReplaceAttr function that trims the file of
+// the top-level source attribute with handler.ShortCallerPath, then calls
+// next, if any.
+func shortSourceReplacer(next func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.SourceKey {
+			if src, ok := a.Value.Any().(*slog.Source); ok {
+				short := *src
+				short.File = handler.ShortCallerPath(src.File)
+				a.Value = slog.AnyValue(&short)
+			}
+		}
+		if next != nil {
+			return next(groups, a)
+		}
+
+		return a
+	}
+}
+
+// emptySourceReplacer returns a ReplaceAttr function that drops the
+// top-level source attribute of records without a caller, which unilog
+// sends below the caller level (see handler.WithCallerLevel), then calls
+// next, if any.
+func emptySourceReplacer(next func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.SourceKey {
+			if src, ok := a.Value.Any().(*slog.Source); ok && src.File == "" {
+				return slog.Attr{}
+			}
+		}
+		if next != nil {
+			return next(groups, a)
+		}
+
+		return a
+	}
+}
+
 // New creates a new handler.Handler instance backed by [log/slog].
 func New(opts ...SlogOption) (handler.Handler, error) {
 	o := &slogOptions{
 		base: &handler.BaseOptions{
 			Level:        handler.InfoLevel,
+			TraceLevel:   handler.DefaultTraceLevel,
+			CallerLevel:  handler.MinLevel,
 			Output:       os.Stderr,
 			Format:       "json",
 			ValidFormats: validFormats,
@@ -127,20 +373,31 @@ func New(opts ...SlogOption) (handler.Handler, error) {
 		return nil, err
 	}
 
+	replaceAttr := o.replaceAttr
+	if len(o.base.LevelNames) > 0 {
+		replaceAttr = levelNameReplacer(base, replaceAttr)
+	}
+	if base.CallerShortPath() {
+		replaceAttr = shortSourceReplacer(replaceAttr)
+	}
+	if base.CallerLevel() > handler.MinLevel {
+		replaceAttr = emptySourceReplacer(replaceAttr)
+	}
+
 	levelVar := new(slog.LevelVar)
-	levelVar.Set(levelMapper.Map(base.Level()))
+	levelVar.Set(unilogLevelToSlog(base.Level()))
 
 	handlerOpts := &slog.HandlerOptions{
 		Level:       levelVar,
 		AddSource:   base.CallerEnabled(),
-		ReplaceAttr: o.replaceAttr,
+		ReplaceAttr: replaceAttr,
 	}
 
 	var h slog.Handler
 	if base.Format() == "text" {
 		h = slog.NewTextHandler(base.AtomicWriter(), handlerOpts)
 	} else {
-		h = slog.NewJSONHandler(base.AtomicWriter(), handlerOpts)
+		h = slog.NewJSONHandler(jsonWriter(base, o.pretty), handlerOpts)
 	}
 
 	return &slogHandler{
@@ -148,7 +405,8 @@ func New(opts ...SlogOption) (handler.Handler, error) {
 		logger:      slog.New(h),
 		level:       levelVar,
 		handler:     h,
-		replaceAttr: o.replaceAttr,
+		replaceAttr: replaceAttr,
+		pretty:      o.pretty,
 		withCaller:  base.CallerEnabled(),
 		withTrace:   base.TraceEnabled(),
 	}, nil
@@ -156,25 +414,46 @@ func New(opts ...SlogOption) (handler.Handler, error) {
 
 // Handle implements the handler.Handler interface for slog.
 func (h *slogHandler) Handle(ctx context.Context, r *handler.Record) error {
-	if !h.Enabled(r.Level) {
+	if r.IsZero() {
+		return handler.ErrZeroRecord
+	}
+	if !h.base.EnabledContext(ctx, r.Level) {
 		return nil
 	}
 
+	keyValues := h.base.ValidateKeys(r.KeyValues)
+
 	// Convert keyValues to slog.Attr slice
-	attrs := keyValuesToSlogAttrs(r.KeyValues)
+	attrs := keyValuesToSlogAttrs(h.base.RedactValues(keyValues))
 
-	// Only add stack if enabled and error-level
-	if h.withTrace && r.Level >= handler.ErrorLevel {
-		attrs = append(attrs, slog.String("stack", string(debug.Stack())))
+	// Only add stack if enabled and at or above the trace level
+	if h.withTrace && r.Level >= h.base.TraceLevel() {
+		attrs = append(attrs, slog.String("stack", handler.CaptureStack(0, h.base.MaxStackDepth())))
+	}
+
+	msg := h.base.RedactString(r.Message)
+	if human, ok := h.base.HumanMessage(r.Message, keyValues); ok {
+		if h.base.Format() == "text" {
+			msg = human
+		} else {
+			attrs = append(attrs, slog.String(h.base.HumanMessageKey(), human))
+		}
 	}
 
 	// slog.NewRecord takes a PC. If unilog captured it (FeatNativeCaller=false), it is passed here.
 	// If AddSource is true in handlerOpts, slog uses this PC to resolve source.
-	rec := slog.NewRecord(r.Time, levelMapper.Map(r.Level), r.Message, r.PC)
+	rec := slog.NewRecord(r.Time, unilogLevelToSlog(r.Level), msg, r.PC)
 	rec.AddAttrs(attrs...)
 
 	// Use ctx for context propagation
-	return h.logger.Handler().Handle(ctx, rec)
+	if err := h.logger.Handler().Handle(ctx, rec); err != nil {
+		h.base.RecordError()
+		return err
+	}
+
+	h.base.RecordHandled(r.Level)
+
+	return nil
 }
 
 // Enabled checks if the given log level is enabled.
@@ -182,6 +461,11 @@ func (h *slogHandler) Enabled(level handler.LogLevel) bool {
 	return h.base.Enabled(level)
 }
 
+// WriteRaw writes a pre-rendered line to the output, bypassing the backend.
+func (h *slogHandler) WriteRaw(level handler.LogLevel, payload []byte) error {
+	return h.base.WriteRaw(level, payload)
+}
+
 // HandlerState returns the underlying BaseHandler.
 func (h *slogHandler) HandlerState() handler.HandlerState {
 	return h.base
@@ -199,7 +483,9 @@ func (h *slogHandler) Features() handler.HandlerFeatures {
 // WithAttrs returns a new logger with the provided keyValues added to the context.
 // If keyValues is empty, the original logger is returned.
 func (h *slogHandler) WithAttrs(keyValues []any) handler.Chainer {
-	attrs := keyValuesToSlogAttrs(keyValues)
+	keyValues = h.base.ValidateKeys(keyValues)
+
+	attrs := keyValuesToSlogAttrs(h.base.RedactValues(keyValues))
 	if len(attrs) == 0 {
 		return h
 	}
@@ -218,7 +504,7 @@ func (h *slogHandler) WithGroup(name string) handler.Chainer {
 	}
 
 	clone := h.clone()
-	clone.handler = h.handler.WithGroup(name)
+	clone.handler = h.handler.WithGroup(h.base.ConvertKey(name))
 	clone.logger = slog.New(clone.handler)
 
 	return clone
@@ -230,7 +516,7 @@ func (h *slogHandler) SetLevel(level handler.LogLevel) error {
 		return err
 	}
 
-	h.level.Set(levelMapper.Map(level))
+	h.level.Set(unilogLevelToSlog(level))
 
 	return nil
 }
@@ -323,6 +609,7 @@ func (h *slogHandler) clone() *slogHandler {
 		level:       h.level,
 		handler:     h.handler,
 		replaceAttr: h.replaceAttr,
+		pretty:      h.pretty,
 		withCaller:  h.withCaller,
 		withTrace:   h.withTrace,
 	}
@@ -331,7 +618,7 @@ func (h *slogHandler) clone() *slogHandler {
 // deepClone returns a deep copy of the logger with a new BaseHandler.
 func (h *slogHandler) deepClone(base *handler.BaseHandler) *slogHandler {
 	levelVar := new(slog.LevelVar)
-	levelVar.Set(levelMapper.Map(base.Level()))
+	levelVar.Set(unilogLevelToSlog(base.Level()))
 
 	handlerOpts := &slog.HandlerOptions{
 		Level:       levelVar,
@@ -343,7 +630,7 @@ func (h *slogHandler) deepClone(base *handler.BaseHandler) *slogHandler {
 	if base.Format() == "text" {
 		sh = slog.NewTextHandler(base.AtomicWriter(), handlerOpts)
 	} else {
-		sh = slog.NewJSONHandler(base.AtomicWriter(), handlerOpts)
+		sh = slog.NewJSONHandler(jsonWriter(base, h.pretty), handlerOpts)
 	}
 
 	return &slogHandler{
@@ -352,11 +639,44 @@ func (h *slogHandler) deepClone(base *handler.BaseHandler) *slogHandler {
 		level:       levelVar,
 		handler:     sh,
 		replaceAttr: h.replaceAttr,
+		pretty:      h.pretty,
 		withCaller:  base.CallerEnabled(),
 		withTrace:   base.TraceEnabled(),
 	}
 }
 
+// jsonWriter returns the writer of the JSON handler, indenting every record
+// if pretty is true.
+func jsonWriter(base *handler.BaseHandler, pretty bool) io.Writer {
+	if pretty {
+		return prettyJSONWriter{base.AtomicWriter()}
+	}
+
+	return base.AtomicWriter()
+}
+
+// prettyJSONWriter indents the JSON records written to it. slog writes every
+// record with a single Write call.
+type prettyJSONWriter struct {
+	w io.Writer
+}
+
+// Write indents the record in p and writes it. If indenting fails, p is
+// written unchanged.
+func (w prettyJSONWriter) Write(p []byte) (int, error) {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimRight(p, "\n"), "", "  "); err != nil {
+		return w.w.Write(p)
+	}
+	indented.WriteByte('\n')
+
+	if _, err := w.w.Write(indented.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
 // keyValuesToSlogAttrs transforms keyValues to slog.Attrs.
 func keyValuesToSlogAttrs(keyValues []any) []slog.Attr {
 	n := len(keyValues)
@@ -0,0 +1,81 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+func TestWithCallerShortPath(t *testing.T) {
+	t.Parallel()
+
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	record := &handler.Record{
+		Time:    time.Now(),
+		Level:   handler.InfoLevel,
+		Message: "msg",
+		PC:      pcs[0],
+	}
+
+	tests := []struct {
+		name  string
+		short bool
+		check func(file string) bool
+	}{
+		{"full path by default", false, func(file string) bool { return strings.HasPrefix(file, "/") }},
+		{"short path", true, func(file string) bool { return file == "slog/source_test.go" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			h, err := New(WithOutput(&buf), WithCaller(true), WithCallerShortPath(tt.short))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if err := h.Handle(context.Background(), record); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			var got struct {
+				Source struct {
+					File string `json:"file"`
+				} `json:"source"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			if !tt.check(got.Source.File) {
+				t.Errorf("source file = %q", got.Source.File)
+			}
+		})
+	}
+}
+
+func TestWithCallerLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h, err := New(WithOutput(&buf), WithCaller(true), WithCallerLevel(handler.WarnLevel))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// The logger leaves PC unset below the caller level
+	record := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg"}
+	if err := h.Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), `"source"`) {
+		t.Errorf("output = %q, want no source below the caller level", buf.String())
+	}
+}
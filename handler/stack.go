@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxStackDepth is the default maximum number of frames kept in a
+// captured stack trace.
+const DefaultMaxStackDepth = 32
+
+// unilogModulePath is the import path of the unilog module. Frames from this
+// module (excluding external test packages) are dropped from stack traces.
+const unilogModulePath = "github.com/balinomad/go-unilog"
+
+// ShortCallerPath returns the last two elements of the slash-separated
+// file path, i.e. the package directory and the file name:
+// "/home/ci/src/app/server/http.go" becomes "server/http.go".
+// Paths with fewer elements are returned unchanged.
+func ShortCallerPath(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i <= 0 {
+		return file
+	}
+
+	if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+		return file[j+1:]
+	}
+
+	return file
+}
+
+// CaptureStack returns a formatted stack trace of the calling goroutine.
+// The skip argument is the number of frames to skip above the caller of
+// CaptureStack, with 0 identifying the caller itself.
+//
+// Frames belonging to the Go runtime and to unilog packages are filtered out,
+// and at most maxDepth of the remaining frames are kept, starting with the
+// frame closest to the log call site. A non-positive maxDepth means no limit.
+//
+// Each frame is rendered on two lines, similar to [runtime/debug.Stack]:
+//
+//	main.handleRequest
+//		/app/server.go:42
+func CaptureStack(skip, maxDepth int) string {
+	if skip < 0 {
+		skip = 0
+	}
+
+	// +2 skips runtime.Callers and CaptureStack itself
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(skip+2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		if !isFilteredFrame(frame.Function) {
+			if maxDepth > 0 && depth >= maxDepth {
+				break
+			}
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteByte('\n')
+			depth++
+		}
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+// isFilteredFrame reports whether the frame of the given function should be
+// omitted from captured stack traces. Frames of the Go runtime and of unilog
+// packages carry no information about the logging call site.
+func isFilteredFrame(function string) bool {
+	pkg := FuncPackage(function)
+	switch {
+	case pkg == "runtime":
+		return true
+	case strings.HasSuffix(pkg, "_test"):
+		return false
+	default:
+		return pkg == unilogModulePath || strings.HasPrefix(pkg, unilogModulePath+"/")
+	}
+}
+
+// FuncPackage returns the import path of the package that declares the
+// fully qualified function name as reported by runtime.Frame.Function
+// (e.g., "github.com/org/pkg.(*T).Method.func1" yields "github.com/org/pkg").
+func FuncPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return function
+	}
+
+	return function[:slash+1+dot]
+}
@@ -0,0 +1,100 @@
+package handler_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// nestedStack calls CaptureStack through depth additional frames.
+func nestedStack(depth, skip, maxDepth int) string {
+	if depth == 0 {
+		return handler.CaptureStack(skip, maxDepth)
+	}
+	return nestedStack(depth-1, skip, maxDepth)
+}
+
+// frameCount returns the number of frames in a captured stack trace.
+func frameCount(stack string) int {
+	return strings.Count(stack, "\n\t")
+}
+
+func TestShortCallerPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"/home/ci/src/app/server/http.go", "server/http.go"},
+		{"server/http.go", "server/http.go"},
+		{"/http.go", "/http.go"},
+		{"http.go", "http.go"},
+		{"C:/src/app/main.go", "app/main.go"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := handler.ShortCallerPath(tt.file); got != tt.want {
+			t.Errorf("ShortCallerPath(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureStack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("includes caller", func(t *testing.T) {
+		t.Parallel()
+		stack := handler.CaptureStack(0, 0)
+		if !strings.Contains(stack, "TestCaptureStack") {
+			t.Errorf("stack does not contain caller:\n%s", stack)
+		}
+		if !strings.Contains(stack, "stack_test.go:") {
+			t.Errorf("stack does not contain file location:\n%s", stack)
+		}
+	})
+
+	t.Run("filters internal frames", func(t *testing.T) {
+		t.Parallel()
+		stack := handler.CaptureStack(0, 0)
+		if strings.Contains(stack, "go-unilog/handler.CaptureStack") {
+			t.Errorf("stack contains CaptureStack frame:\n%s", stack)
+		}
+		if strings.Contains(stack, "runtime.") {
+			t.Errorf("stack contains runtime frames:\n%s", stack)
+		}
+	})
+
+	t.Run("limits depth", func(t *testing.T) {
+		t.Parallel()
+		full := nestedStack(10, 0, 0)
+		if got := frameCount(full); got < 11 {
+			t.Fatalf("full stack has %d frames, want at least 11", got)
+		}
+
+		limited := nestedStack(10, 0, 3)
+		if got := frameCount(limited); got != 3 {
+			t.Errorf("limited stack has %d frames, want 3:\n%s", got, limited)
+		}
+		if !strings.HasPrefix(full, limited) {
+			t.Errorf("limited stack is not the top of the full stack:\nfull:\n%s\nlimited:\n%s", full, limited)
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		t.Parallel()
+		stack := nestedStack(0, 1, 1)
+		if strings.Contains(stack, "nestedStack") {
+			t.Errorf("skipped frame present:\n%s", stack)
+		}
+	})
+
+	t.Run("negative skip", func(t *testing.T) {
+		t.Parallel()
+		if got := handler.CaptureStack(-1, 1); frameCount(got) != 1 {
+			t.Errorf("CaptureStack(-1, 1) = %q, want one frame", got)
+		}
+	})
+}
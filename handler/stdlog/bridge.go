@@ -0,0 +1,104 @@
+package stdlog
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// bridgeOptions holds configuration for AsStdLogger.
+type bridgeOptions struct {
+	parsePrefix bool // Keep the log.Logger prefix in the message
+}
+
+// BridgeOption configures the *log.Logger returned by AsStdLogger.
+type BridgeOption func(*bridgeOptions)
+
+// WithParsePrefix controls whether the log.Logger prefix (see log.Logger.SetPrefix)
+// is kept as part of the unilog message or discarded.
+// The default value is true.
+func WithParsePrefix(enabled bool) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.parsePrefix = enabled
+	}
+}
+
+// AsStdLogger returns a *log.Logger whose output is sent to h as records at
+// the given level. It lets libraries that only accept a *log.Logger log
+// through unilog.
+//
+// The returned logger has no flags set, since the handler adds its own
+// timestamp and caller. Setting flags on it (e.g. log.Ldate) makes the
+// formatted header part of the message.
+func AsStdLogger(h handler.Handler, level handler.LogLevel, opts ...BridgeOption) *log.Logger {
+	o := &bridgeOptions{parsePrefix: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	w := &bridgeWriter{
+		h:           h,
+		level:       level,
+		parsePrefix: o.parsePrefix,
+	}
+	w.logger = log.New(w, "", 0)
+
+	return w.logger
+}
+
+// bridgeWriter converts lines written by a log.Logger into handler records.
+type bridgeWriter struct {
+	h           handler.Handler
+	logger      *log.Logger // Owning logger, used to read the current prefix
+	level       handler.LogLevel
+	parsePrefix bool
+}
+
+// Write handles one formatted log line: prefix + message + "\n".
+func (w *bridgeWriter) Write(p []byte) (int, error) {
+	if !w.h.Enabled(w.level) {
+		return len(p), nil
+	}
+
+	msg := string(bytes.TrimSuffix(p, []byte{'\n'}))
+	if !w.parsePrefix {
+		msg = strings.TrimPrefix(msg, w.logger.Prefix())
+	}
+
+	r := &handler.Record{
+		Time:    time.Now(),
+		Level:   w.level,
+		Message: msg,
+	}
+
+	if state := w.h.HandlerState(); state != nil && state.CallerEnabled() &&
+		!w.h.Features().Supports(handler.FeatNativeCaller) {
+		r.PC = callerPC()
+	}
+
+	if err := w.h.Handle(context.Background(), r); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// callerPC returns the program counter of the first frame outside
+// this bridge and the log package, i.e. the caller of the log.Logger method.
+func callerPC() uintptr {
+	var pcs [16]uintptr
+	// Skip runtime.Callers, callerPC and bridgeWriter.Write
+	n := runtime.Callers(3, pcs[:])
+	for _, pc := range pcs[:n] {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && !strings.HasPrefix(fn.Name(), "log.") {
+			return pc
+		}
+	}
+
+	return 0
+}
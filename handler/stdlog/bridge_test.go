@@ -0,0 +1,103 @@
+package stdlog_test
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/balinomad/go-unilog/handler"
+	"github.com/balinomad/go-unilog/handler/stdlog"
+)
+
+// captureHandler records every handled record.
+type captureHandler struct {
+	mu      sync.Mutex
+	level   handler.LogLevel
+	caller  bool
+	records []handler.Record
+}
+
+func (h *captureHandler) Handle(_ context.Context, r *handler.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, *r)
+	return nil
+}
+
+func (h *captureHandler) Enabled(level handler.LogLevel) bool { return level >= h.level }
+func (h *captureHandler) HandlerState() handler.HandlerState  { return h }
+func (h *captureHandler) Features() handler.HandlerFeatures   { return handler.HandlerFeatures{} }
+func (h *captureHandler) CallerEnabled() bool                 { return h.caller }
+func (h *captureHandler) TraceEnabled() bool                  { return false }
+func (h *captureHandler) CallerSkip() int                     { return 0 }
+
+func TestAsStdLogger(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		opts   []stdlog.BridgeOption
+		prefix string
+		want   string
+	}{
+		{"no prefix", nil, "", "hello 42"},
+		{"prefix kept by default", nil, "lib: ", "lib: hello 42"},
+		{"prefix kept", []stdlog.BridgeOption{stdlog.WithParsePrefix(true)}, "lib: ", "lib: hello 42"},
+		{"prefix discarded", []stdlog.BridgeOption{stdlog.WithParsePrefix(false)}, "lib: ", "hello 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := &captureHandler{level: handler.InfoLevel}
+			l := stdlog.AsStdLogger(h, handler.WarnLevel, tt.opts...)
+			l.SetPrefix(tt.prefix)
+
+			l.Printf("hello %d", 42)
+
+			if len(h.records) != 1 {
+				t.Fatalf("records = %d, want 1", len(h.records))
+			}
+			r := h.records[0]
+			if r.Level != handler.WarnLevel {
+				t.Errorf("Level = %v, want %v", r.Level, handler.WarnLevel)
+			}
+			if r.Message != tt.want {
+				t.Errorf("Message = %q, want %q", r.Message, tt.want)
+			}
+			if r.Time.IsZero() {
+				t.Error("Time is zero")
+			}
+		})
+	}
+}
+
+func TestAsStdLogger_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h := &captureHandler{level: handler.ErrorLevel}
+	l := stdlog.AsStdLogger(h, handler.InfoLevel)
+	l.Println("dropped")
+
+	if len(h.records) != 0 {
+		t.Errorf("records = %d, want 0", len(h.records))
+	}
+}
+
+func TestAsStdLogger_Caller(t *testing.T) {
+	t.Parallel()
+
+	h := &captureHandler{level: handler.InfoLevel, caller: true}
+	l := stdlog.AsStdLogger(h, handler.InfoLevel)
+	l.Print("with caller")
+
+	if len(h.records) != 1 || h.records[0].PC == 0 {
+		t.Fatal("expected a record with PC set")
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{h.records[0].PC}).Next()
+	if !strings.HasSuffix(frame.Function, "TestAsStdLogger_Caller") {
+		t.Errorf("caller = %s, want TestAsStdLogger_Caller", frame.Function)
+	}
+}
@@ -6,7 +6,8 @@ import (
 	"io"
 	"log"
 	"os"
-	"runtime/debug"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/balinomad/go-caller"
@@ -29,6 +30,15 @@ func WithLevel(level handler.LogLevel) StdLogOption {
 	}
 }
 
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR only.
+// The minimum level becomes the lowest enabled level.
+func WithEnabledLevels(levels ...handler.LogLevel) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithEnabledLevels(levels...)(o.base)
+	}
+}
+
 // WithOutput sets the output writer.
 func WithOutput(w io.Writer) StdLogOption {
 	return func(o *stdLogOptions) error {
@@ -36,6 +46,14 @@ func WithOutput(w io.Writer) StdLogOption {
 	}
 }
 
+// WithFailFast makes New return an error if the output is not writable,
+// instead of failing on the first log. The default value is false.
+func WithFailFast(enabled bool) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithFailFast(enabled)(o.base)
+	}
+}
+
 // WithSeparator sets the separator for group key prefixes.
 func WithSeparator(separator string) StdLogOption {
 	return func(o *stdLogOptions) error {
@@ -54,13 +72,135 @@ func WithCaller(enabled bool) StdLogOption {
 	}
 }
 
-// WithTrace enables stack traces for ERROR and above.
+// WithCallerShortPath trims the caller file to its package directory and
+// file name, e.g. "server/http.go", instead of the absolute path.
+// The default value is false.
+func WithCallerShortPath(enabled bool) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithCallerShortPath(enabled)(o.base)
+	}
+}
+
+// WithCallerLevel sets the minimum level that carries caller information
+// when caller reporting is enabled. The default value is handler.MinLevel.
+func WithCallerLevel(level handler.LogLevel) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithCallerLevel(level)(o.base)
+	}
+}
+
+// WithDebugFriendlyDefaults drops records below INFO, reports the caller
+// from WARN and adds a stack trace from ERROR.
+// See handler.WithDebugFriendlyDefaults.
+func WithDebugFriendlyDefaults() StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithDebugFriendlyDefaults()(o.base)
+	}
+}
+
+// WithTrace enables stack traces for ERROR and above, or for the level
+// set with WithTraceLevel.
 func WithTrace(enabled bool) StdLogOption {
 	return func(o *stdLogOptions) error {
 		return handler.WithTrace(enabled)(o.base)
 	}
 }
 
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. The default value is handler.DefaultTraceLevel.
+func WithTraceLevel(level handler.LogLevel) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithTraceLevel(level)(o.base)
+	}
+}
+
+// WithMaxStackDepth limits stack traces to the top n frames closest to the
+// log call site. The default value is handler.DefaultMaxStackDepth.
+func WithMaxStackDepth(n int) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithMaxStackDepth(n)(o.base)
+	}
+}
+
+// WithOutputSwapHook registers fn to be called after SetOutput replaced the
+// output writer, e.g. to close the old writer. Writes made after fn is
+// called go to the new writer.
+func WithOutputSwapHook(fn func(old, new io.Writer)) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithOutputSwapHook(fn)(o.base)
+	}
+}
+
+// WithFieldValidator registers fn to check every field key. Rejected keys
+// are logged in a sanitized snake_case form, with the original kept in an
+// "_invalid_field_<n>" field.
+func WithFieldValidator(fn func(key string) error) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithFieldValidator(fn)(o.base)
+	}
+}
+
+// WithMaxLineBytes truncates every rendered record to at most n bytes.
+// JSON records keep their leading fields and end with "_truncated":true.
+// See handler.WithMaxLineBytes.
+func WithMaxLineBytes(n int) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithMaxLineBytes(n)(o.base)
+	}
+}
+
+// WithKeyCase normalizes attribute keys and group names to the given case,
+// e.g. handler.KeyCaseSnake renders both "requestID" and "request-id" as
+// "request_id". The default value is handler.KeyCaseAsIs.
+func WithKeyCase(c handler.KeyCase) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithKeyCase(c)(o.base)
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record.
+func WithMetricsProvider(p handler.MetricsProvider) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithMetricsProvider(p)(o.base)
+	}
+}
+
+// WithLevelNames overrides the names printed in the level prefix.
+// Levels without an entry keep their canonical names.
+func WithLevelNames(names map[handler.LogLevel]string) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithLevelNames(names)(o.base)
+	}
+}
+
+// WithJSONValues renders map, slice and array attribute values as compact
+// JSON (e.g. {"a":1}) instead of Go syntax (map[a:1]). The default value is false.
+func WithJSONValues(enabled bool) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithJSONValues(enabled)(o.base)
+	}
+}
+
+// WithRedactPattern replaces every match of re in messages and string values
+// with replacement, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString. Patterns run on every record, so keep
+// them few. See handler.WithRedactPattern.
+func WithRedactPattern(re *regexp.Regexp, replacement string) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithRedactPattern(re, replacement)(o.base)
+	}
+}
+
+// WithHumanMessageTemplate sets a text/template rendering a readable message
+// from each record's attributes, e.g. "{{.user}} logged in from {{.ip}}".
+// The rendered message replaces the original one.
+// Returns error if the template cannot be parsed.
+func WithHumanMessageTemplate(tmpl string) StdLogOption {
+	return func(o *stdLogOptions) error {
+		return handler.WithHumanMessageTemplate(tmpl)(o.base)
+	}
+}
+
 // WithFlags sets the log flags.
 func WithFlags(flags int) StdLogOption {
 	return func(o *stdLogOptions) error {
@@ -89,14 +229,17 @@ var (
 	_ handler.CallerAdjuster = (*stdLogHandler)(nil)
 	_ handler.FeatureToggler = (*stdLogHandler)(nil)
 	_ handler.MutableConfig  = (*stdLogHandler)(nil)
+	_ handler.RawWriter      = (*stdLogHandler)(nil)
 )
 
 // New creates a new handler.Handler instance backed by the standard log.
 func New(opts ...StdLogOption) (handler.Handler, error) {
 	o := &stdLogOptions{
 		base: &handler.BaseOptions{
-			Level:  handler.DefaultLevel,
-			Output: os.Stderr,
+			Level:       handler.DefaultLevel,
+			TraceLevel:  handler.DefaultTraceLevel,
+			CallerLevel: handler.MinLevel,
+			Output:      os.Stderr,
 		},
 		flags: log.LstdFlags,
 	}
@@ -122,57 +265,74 @@ func New(opts ...StdLogOption) (handler.Handler, error) {
 }
 
 // Handle implements the handler.Handler interface for the standard logger.
-func (h *stdLogHandler) Handle(_ context.Context, r *handler.Record) error {
-	if !h.Enabled(r.Level) {
+func (h *stdLogHandler) Handle(ctx context.Context, r *handler.Record) error {
+	if r.IsZero() {
+		return handler.ErrZeroRecord
+	}
+	if !h.base.EnabledContext(ctx, r.Level) {
 		return nil
 	}
 
+	keyValues := h.base.ValidateKeys(r.KeyValues)
+
 	// Heuristic pre-allocation: message + existing attrs + new attrs + overhead
-	estSize := len(r.Message) + len(h.keyValues)*10 + len(r.KeyValues)*10 + 50
+	estSize := len(r.Message) + len(h.keyValues)*10 + len(keyValues)*10 + 50
 	var sb strings.Builder
 	sb.Grow(estSize)
 
 	// Level prefix
 	sb.WriteString("[")
-	sb.WriteString(r.Level.String())
+	sb.WriteString(h.base.LevelName(r.Level))
 	sb.WriteString("] ")
-	sb.WriteString(r.Message)
+	if msg, ok := h.base.HumanMessage(r.Message, keyValues); ok {
+		sb.WriteString(msg)
+	} else {
+		sb.WriteString(h.base.RedactString(r.Message))
+	}
 
 	// Write baked-in attributes (prefixes already applied)
-	writePairs(&sb, h.keyValues)
+	h.writePairs(&sb, h.keyValues)
 
 	// Write record attributes (apply current prefix)
 	currentPrefix := h.base.KeyPrefix()
 	separator := h.base.Separator()
 
-	for i := 0; i < len(r.KeyValues)-1; i += 2 {
+	for i := 0; i < len(keyValues)-1; i += 2 {
 		sb.WriteString(" ")
 		if currentPrefix != "" {
 			sb.WriteString(currentPrefix)
 			sb.WriteString(separator)
 		}
-		key, ok := r.KeyValues[i].(string)
+		key, ok := keyValues[i].(string)
 		if !ok {
-			key = fmt.Sprint(r.KeyValues[i])
+			key = fmt.Sprint(keyValues[i])
 		}
 		sb.WriteString(key)
 		sb.WriteString("=")
-		sb.WriteString(fmt.Sprint(r.KeyValues[i+1]))
+		sb.WriteString(h.base.FormatValue(keyValues[i+1]))
 	}
 
 	// Only compute caller if enabled
 	if h.withCaller && r.PC != 0 {
 		sb.WriteString(" source=")
-		sb.WriteString(caller.NewFromPC(r.PC).Location())
+		c := caller.NewFromPC(r.PC)
+		sb.WriteString(h.base.CallerPath(c.File()))
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(c.Line()))
 	}
 
-	// Only capture stack if enabled and error-level
-	if h.withTrace && r.Level >= handler.ErrorLevel {
+	// Only capture stack if enabled and at or above the trace level
+	if h.withTrace && r.Level >= h.base.TraceLevel() {
 		sb.WriteString(" stack=")
-		sb.WriteString(string(debug.Stack()))
+		sb.WriteString(handler.CaptureStack(0, h.base.MaxStackDepth()))
 	}
 
-	h.logger.Println(sb.String())
+	// Output, unlike Println, returns the write error
+	if err := h.logger.Output(1, sb.String()); err != nil {
+		h.base.RecordError()
+		return err
+	}
+	h.base.RecordHandled(r.Level)
 
 	return nil
 }
@@ -182,6 +342,11 @@ func (h *stdLogHandler) Enabled(level handler.LogLevel) bool {
 	return h.base.Enabled(level)
 }
 
+// WriteRaw writes a pre-rendered line to the output, bypassing the backend.
+func (h *stdLogHandler) WriteRaw(level handler.LogLevel, payload []byte) error {
+	return h.base.WriteRaw(level, payload)
+}
+
 // HandlerState returns the underlying BaseHandler.
 func (h *stdLogHandler) HandlerState() handler.HandlerState {
 	return h.base
@@ -195,6 +360,8 @@ func (h *stdLogHandler) Features() handler.HandlerFeatures {
 // WithAttrs returns a new logger with the provided keyValues added to the context.
 // If keyValues is empty, the original logger is returned.
 func (h *stdLogHandler) WithAttrs(keyValues []any) handler.Chainer {
+	keyValues = h.base.ValidateKeys(keyValues)
+
 	if len(keyValues) < 2 {
 		return h
 	}
@@ -234,7 +401,7 @@ func (h *stdLogHandler) WithGroup(name string) handler.Chainer {
 		return h
 	}
 
-	base, err := h.base.WithKeyPrefix(name)
+	base, err := h.base.WithKeyPrefix(h.base.ConvertKey(name))
 	if err != nil {
 		return h
 	}
@@ -358,7 +525,7 @@ func (h *stdLogHandler) deepClone(base *handler.BaseHandler) *stdLogHandler {
 }
 
 // writePairs writes key-value pairs to the provided strings.Builder.
-func writePairs(sb *strings.Builder, keyValues []any) {
+func (h *stdLogHandler) writePairs(sb *strings.Builder, keyValues []any) {
 	for i := 0; i < len(keyValues)-1; i += 2 {
 		key, ok := keyValues[i].(string)
 		if !ok {
@@ -367,6 +534,6 @@ func writePairs(sb *strings.Builder, keyValues []any) {
 		sb.WriteString(" ")
 		sb.WriteString(key)
 		sb.WriteString("=")
-		fmt.Fprint(sb, keyValues[i+1])
+		sb.WriteString(h.base.FormatValue(keyValues[i+1]))
 	}
 }
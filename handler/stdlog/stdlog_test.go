@@ -0,0 +1,98 @@
+package stdlog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog"
+	"github.com/balinomad/go-unilog/handler"
+	"github.com/balinomad/go-unilog/handler/stdlog"
+)
+
+// errorMetrics is a MetricsProvider that counts errors.
+type errorMetrics struct{ errors int }
+
+func (m *errorMetrics) RecordHandled(handler.LogLevel) {}
+func (m *errorMetrics) RecordError()                   { m.errors++ }
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestHandle_WriteError(t *testing.T) {
+	t.Parallel()
+
+	m := &errorMetrics{}
+	h, err := stdlog.New(stdlog.WithOutput(failingWriter{}), stdlog.WithMetricsProvider(m))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg"}
+	if err := h.Handle(context.Background(), r); err == nil {
+		t.Error("Handle() error = nil, want the write error")
+	}
+	if m.errors != 1 {
+		t.Errorf("errors = %d, want 1", m.errors)
+	}
+}
+
+func TestLogger_WithLevelFromContext(t *testing.T) {
+	t.Parallel()
+
+	type debugKey struct{}
+	fromCtx := func(ctx context.Context) (unilog.LogLevel, bool) {
+		if ctx.Value(debugKey{}) != nil {
+			return unilog.DebugLevel, true
+		}
+		return 0, false
+	}
+
+	var buf bytes.Buffer
+	h, err := stdlog.New(stdlog.WithOutput(&buf), stdlog.WithLevel(handler.InfoLevel))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	l, err := unilog.NewLogger(h, unilog.WithLevelFromContext(fromCtx))
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	debugCtx := context.WithValue(context.Background(), debugKey{}, true)
+	l.Debug(context.Background(), "dropped at the handler level")
+	l.Trace(debugCtx, "dropped below the context level")
+	l.Debug(debugCtx, "logged for this request")
+
+	out := buf.String()
+	if !strings.Contains(out, "logged for this request") {
+		t.Errorf("output %q does not contain the DEBUG record", out)
+	}
+	if strings.Contains(out, "dropped") {
+		t.Errorf("output %q contains a dropped record", out)
+	}
+}
+
+func TestWithKeyCase_Group(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h, err := stdlog.New(stdlog.WithOutput(&buf), stdlog.WithKeyCase(handler.KeyCaseSnake))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	g := h.(handler.Chainer).WithGroup("HTTPRequest")
+	r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", KeyValues: []any{"statusCode", 200}}
+	if err := g.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, "http_request_status_code=200") {
+		t.Errorf("output = %q, want key http_request_status_code", out)
+	}
+}
@@ -1,13 +1,18 @@
 package zap
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/balinomad/go-unilog/handler"
@@ -18,7 +23,8 @@ var validFormats = []string{"json", "console"}
 
 // zapOptions holds configuration for the Zap logger.
 type zapOptions struct {
-	base *handler.BaseOptions
+	base   *handler.BaseOptions
+	pretty bool // Indent JSON output
 }
 
 // ZapOption configures the Zap logger creation.
@@ -31,6 +37,15 @@ func WithLevel(level handler.LogLevel) ZapOption {
 	}
 }
 
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR only.
+// The minimum level becomes the lowest enabled level.
+func WithEnabledLevels(levels ...handler.LogLevel) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithEnabledLevels(levels...)(o.base)
+	}
+}
+
 // WithOutput sets the output writer.
 func WithOutput(w io.Writer) ZapOption {
 	return func(o *zapOptions) error {
@@ -38,6 +53,14 @@ func WithOutput(w io.Writer) ZapOption {
 	}
 }
 
+// WithFailFast makes New return an error if the output is not writable,
+// instead of failing on the first log. The default value is false.
+func WithFailFast(enabled bool) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithFailFast(enabled)(o.base)
+	}
+}
+
 // WithCaller enables or disables source location reporting.
 // If enabled, the handler will include the source location
 // of the log call site in the log record.
@@ -49,18 +72,147 @@ func WithCaller(enabled bool) ZapOption {
 	}
 }
 
-// WithTrace enables stack traces for ERROR and above.
+// WithCallerShortPath trims the caller file to its package directory and
+// file name, e.g. "server/http.go", instead of the absolute path.
+// The default value is false.
+func WithCallerShortPath(enabled bool) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithCallerShortPath(enabled)(o.base)
+	}
+}
+
+// WithCallerLevel sets the minimum level that carries caller information
+// when caller reporting is enabled. The default value is handler.MinLevel.
+func WithCallerLevel(level handler.LogLevel) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithCallerLevel(level)(o.base)
+	}
+}
+
+// WithDebugFriendlyDefaults drops records below INFO, reports the caller
+// from WARN and adds a stack trace from ERROR.
+// See handler.WithDebugFriendlyDefaults.
+func WithDebugFriendlyDefaults() ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithDebugFriendlyDefaults()(o.base)
+	}
+}
+
+// WithTrace enables stack traces for ERROR and above, or for the level
+// set with WithTraceLevel.
 func WithTrace(enabled bool) ZapOption {
 	return func(o *zapOptions) error {
 		return handler.WithTrace(enabled)(o.base)
 	}
 }
 
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. It maps to zap.AddStacktrace, so CRITICAL behaves like
+// ERROR. The default value is handler.DefaultTraceLevel.
+func WithTraceLevel(level handler.LogLevel) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithTraceLevel(level)(o.base)
+	}
+}
+
+// WithOutputSwapHook registers fn to be called after SetOutput replaced the
+// output writer, e.g. to close the old writer. Writes made after fn is
+// called go to the new writer.
+func WithOutputSwapHook(fn func(old, new io.Writer)) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithOutputSwapHook(fn)(o.base)
+	}
+}
+
+// WithFieldValidator registers fn to check every field key. Rejected keys
+// are logged in a sanitized snake_case form, with the original kept in an
+// "_invalid_field_<n>" field.
+func WithFieldValidator(fn func(key string) error) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithFieldValidator(fn)(o.base)
+	}
+}
+
+// WithMaxLineBytes truncates every rendered record to at most n bytes.
+// JSON records keep their leading fields and end with "_truncated":true.
+// See handler.WithMaxLineBytes.
+func WithMaxLineBytes(n int) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithMaxLineBytes(n)(o.base)
+	}
+}
+
+// WithKeyCase normalizes attribute keys and group names to the given case,
+// e.g. handler.KeyCaseSnake renders both "requestID" and "request-id" as
+// "request_id". The default value is handler.KeyCaseAsIs.
+func WithKeyCase(c handler.KeyCase) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithKeyCase(c)(o.base)
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record.
+func WithMetricsProvider(p handler.MetricsProvider) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithMetricsProvider(p)(o.base)
+	}
+}
+
+// WithLevelNames overrides the rendered value of the level field.
+// Levels without an entry keep zap's own lowercase names. Because zap has
+// fewer levels than unilog, TRACE records render with the DEBUG name and
+// CRITICAL records with the ERROR name.
+func WithLevelNames(names map[handler.LogLevel]string) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithLevelNames(names)(o.base)
+	}
+}
+
+// WithHumanMessageTemplate sets a text/template rendering a readable message
+// from each record's attributes, e.g. "{{.user}} logged in from {{.ip}}".
+// The console format shows it instead of the original message; the JSON
+// format keeps the original and adds the rendered one (see WithHumanMessageKey).
+// Returns error if the template cannot be parsed.
+func WithHumanMessageTemplate(tmpl string) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithHumanMessageTemplate(tmpl)(o.base)
+	}
+}
+
+// WithHumanMessageKey sets the key of the rendered message in JSON output.
+// The default value is handler.DefaultHumanMessageKey.
+func WithHumanMessageKey(key string) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithHumanMessageKey(key)(o.base)
+	}
+}
+
+// WithRedactPattern replaces every match of re in messages and string values
+// with replacement, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString. Patterns run on every record, so keep
+// them few. See handler.WithRedactPattern.
+func WithRedactPattern(re *regexp.Regexp, replacement string) ZapOption {
+	return func(o *zapOptions) error {
+		return handler.WithRedactPattern(re, replacement)(o.base)
+	}
+}
+
+// WithPrettyJSON indents each JSON record over several lines for reading
+// during local development. It has no effect with the console format.
+// Pretty output is no longer newline-delimited JSON and breaks log shippers
+// and parsers expecting one record per line: never enable it in production.
+// The default value is false.
+func WithPrettyJSON(enabled bool) ZapOption {
+	return func(o *zapOptions) error {
+		o.pretty = enabled
+		return nil
+	}
+}
+
 // zapHandler is a wrapper around Zap's logger.
 type zapHandler struct {
 	base           *handler.BaseHandler
 	logger         *zap.Logger
-	atomicLevel    zap.AtomicLevel
 	encoderFactory func() zapcore.Encoder
 	writeSyncer    zapcore.WriteSyncer
 	zapOpts        []zap.Option
@@ -69,8 +221,20 @@ type zapHandler struct {
 	withCaller bool
 	withTrace  bool
 	callerSkip int
+
+	// Loggers derived from logger for Handle, by record skip or noCallerKey.
+	// Records use few distinct skips, so it stays small. Never copied: every
+	// handler built from another starts with an empty cache.
+	derived sync.Map
 }
 
+// coreLevel is the level of every zap core. Handle filters records by the
+// handler's level, or by a context level, before they reach zap.
+var coreLevel = levelMapper.Map(handler.MinLevel)
+
+// noCallerKey is the derived logger key of records below the caller level.
+const noCallerKey = -1
+
 // Ensure zapHandler implements all interfaces explicitly.
 var (
 	_ handler.Handler        = (*zapHandler)(nil)
@@ -80,6 +244,7 @@ var (
 	_ handler.FeatureToggler = (*zapHandler)(nil)
 	_ handler.MutableConfig  = (*zapHandler)(nil)
 	_ handler.Syncer         = (*zapHandler)(nil)
+	_ handler.RawWriter      = (*zapHandler)(nil)
 )
 
 // levelMapper maps unilog log levels to zap log levels.
@@ -94,6 +259,60 @@ var levelMapper = handler.NewLevelMapper(
 	zapcore.PanicLevel, // Panic
 )
 
+// zapToUnilog maps each zap level back to the unilog level it renders as.
+var zapToUnilog = map[zapcore.Level]handler.LogLevel{
+	zapcore.DebugLevel: handler.DebugLevel,
+	zapcore.InfoLevel:  handler.InfoLevel,
+	zapcore.WarnLevel:  handler.WarnLevel,
+	zapcore.ErrorLevel: handler.ErrorLevel,
+	zapcore.PanicLevel: handler.PanicLevel,
+	zapcore.FatalLevel: handler.FatalLevel,
+}
+
+// levelNameEncoder returns a level encoder that writes the name configured
+// for the corresponding unilog level, falling back to next otherwise.
+func levelNameEncoder(names map[handler.LogLevel]string, next zapcore.LevelEncoder) zapcore.LevelEncoder {
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if level, ok := zapToUnilog[l]; ok {
+			if name, ok := names[level]; ok {
+				enc.AppendString(name)
+				return
+			}
+		}
+		next(l, enc)
+	}
+}
+
+// prettyJSONEncoder is a JSON encoder that indents every encoded entry.
+type prettyJSONEncoder struct {
+	zapcore.Encoder
+}
+
+// Clone returns a copy of the encoder that still indents its output.
+func (e prettyJSONEncoder) Clone() zapcore.Encoder {
+	return prettyJSONEncoder{e.Encoder.Clone()}
+}
+
+// EncodeEntry encodes the entry with the wrapped encoder and indents the result.
+// If indenting fails, the compact form is returned unchanged.
+func (e prettyJSONEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimRight(buf.Bytes(), "\n"), "", "  "); err != nil {
+		return buf, nil
+	}
+
+	buf.Reset()
+	_, _ = buf.Write(indented.Bytes())
+	buf.AppendByte('\n')
+
+	return buf, nil
+}
+
 // New creates a new handler.Handler instance backed by zap.
 // It also captures enough internal pieces to be able to recreate/clone
 // the embedded zap.Logger later with a different set of options.
@@ -101,6 +320,8 @@ func New(opts ...ZapOption) (handler.Handler, error) {
 	o := &zapOptions{
 		base: &handler.BaseOptions{
 			Level:        handler.DefaultLevel,
+			TraceLevel:   handler.DefaultTraceLevel,
+			CallerLevel:  handler.MinLevel,
 			Output:       os.Stderr,
 			Format:       "json",
 			ValidFormats: validFormats,
@@ -119,14 +340,18 @@ func New(opts ...ZapOption) (handler.Handler, error) {
 	}
 
 	// Create the write syncer once and keep it for future clones
-	writeSyncer := zapcore.AddSync(base.AtomicWriter())
-
-	// Create the initial atomic level and keep a value copy
-	initialLevel := zap.NewAtomicLevelAt(levelMapper.Map(base.Level()))
+	writeSyncer := zapcore.AddSync(base.ErrorRecordingWriter())
 
 	// Build encoder config
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeCaller = zapcore.FullCallerEncoder
+	if base.CallerShortPath() {
+		encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+	}
+	if len(o.base.LevelNames) > 0 {
+		encoderConfig.EncodeLevel = levelNameEncoder(o.base.LevelNames, encoderConfig.EncodeLevel)
+	}
 
 	// Create an encoderFactory so we can reproduce the same encoder later
 	var encoderFactory func() zapcore.Encoder
@@ -134,6 +359,10 @@ func New(opts ...ZapOption) (handler.Handler, error) {
 		encoderFactory = func() zapcore.Encoder {
 			return zapcore.NewConsoleEncoder(encoderConfig)
 		}
+	} else if o.pretty {
+		encoderFactory = func() zapcore.Encoder {
+			return prettyJSONEncoder{zapcore.NewJSONEncoder(encoderConfig)}
+		}
 	} else {
 		encoderFactory = func() zapcore.Encoder {
 			return zapcore.NewJSONEncoder(encoderConfig)
@@ -141,14 +370,13 @@ func New(opts ...ZapOption) (handler.Handler, error) {
 	}
 
 	// Build initial core and zap options
-	core := zapcore.NewCore(encoderFactory(), writeSyncer, initialLevel)
+	core := zapcore.NewCore(encoderFactory(), writeSyncer, coreLevel)
 	zapOpts := buildZapOpts(base)
 	zl := zap.New(core, zapOpts...)
 
 	return &zapHandler{
 		base:           base,
 		logger:         zl,
-		atomicLevel:    initialLevel,
 		encoderFactory: encoderFactory,
 		writeSyncer:    writeSyncer,
 		zapOpts:        zapOpts,
@@ -159,30 +387,74 @@ func New(opts ...ZapOption) (handler.Handler, error) {
 }
 
 // Handle implements the handler.Handler interface for zap.
-func (h *zapHandler) Handle(_ context.Context, r *handler.Record) error {
-	if !h.Enabled(r.Level) {
+func (h *zapHandler) Handle(ctx context.Context, r *handler.Record) error {
+	if r.IsZero() {
+		return handler.ErrZeroRecord
+	}
+	if !h.base.EnabledContext(ctx, r.Level) {
 		return nil
 	}
 
+	keyValues := h.base.ValidateKeys(r.KeyValues)
+
 	zl := h.logger
 
-	// Apply dynamic skip if needed
-	if h.withCaller && r.Skip != 0 {
-		zl = zl.WithOptions(zap.AddCallerSkip(r.Skip))
+	// Drop the caller below the caller level, or apply the dynamic skip
+	switch {
+	case h.withCaller && r.Level < h.base.CallerLevel():
+		zl = h.derivedLogger(noCallerKey)
+	case h.withCaller && r.Skip > 0:
+		zl = h.derivedLogger(r.Skip)
 	}
 
-	if ce := zl.Check(levelMapper.Map(r.Level), r.Message); ce != nil {
-		ce.Write(keyValuesToZapFields(r.KeyValues)...)
+	// The console format shows the rendered message, JSON adds it as a field
+	msg := h.base.RedactString(r.Message)
+	human, hasHuman := h.base.HumanMessage(r.Message, keyValues)
+	humanField := hasHuman && h.base.Format() != "console"
+	if hasHuman && !humanField {
+		msg = human
+	}
+
+	if ce := zl.Check(levelMapper.Map(r.Level), msg); ce != nil {
+		fields := keyValuesToZapFields(h.base.RedactValues(keyValues))
+		if humanField {
+			fields = append(fields, zap.String(h.base.HumanMessageKey(), human))
+		}
+		ce.Write(fields...)
+		h.base.RecordHandled(r.Level)
 	}
 
 	return nil
 }
 
+// derivedLogger returns the logger for records with the given skip, or
+// without caller for noCallerKey, building it on first use. Caching it
+// avoids cloning the logger with WithOptions for every record.
+func (h *zapHandler) derivedLogger(key int) *zap.Logger {
+	if zl, ok := h.derived.Load(key); ok {
+		return zl.(*zap.Logger)
+	}
+
+	// The record skip replaces the handler's, which the logger already has
+	opt := zap.AddCallerSkip(key - h.callerSkip)
+	if key == noCallerKey {
+		opt = zap.WithCaller(false)
+	}
+	zl, _ := h.derived.LoadOrStore(key, h.logger.WithOptions(opt))
+
+	return zl.(*zap.Logger)
+}
+
 // Enabled checks if the given log level is enabled.
 func (h *zapHandler) Enabled(level handler.LogLevel) bool {
 	return h.base.Enabled(level)
 }
 
+// WriteRaw writes a pre-rendered line to the output, bypassing the backend.
+func (h *zapHandler) WriteRaw(level handler.LogLevel, payload []byte) error {
+	return h.base.WriteRaw(level, payload)
+}
+
 // Base returns the underlying BaseHandler.
 func (h *zapHandler) HandlerState() handler.HandlerState {
 	return h.base
@@ -194,14 +466,16 @@ func (h *zapHandler) Features() handler.HandlerFeatures {
 		handler.FeatNativeCaller | // zap.AddCallerSkip
 			handler.FeatNativeGroup | // zap.Namespace
 			handler.FeatBufferedOutput | // zap.Sync()
-			handler.FeatDynamicLevel | // zap.AtomicLevel
+			handler.FeatDynamicLevel | // handler.BaseHandler level
 			handler.FeatDynamicOutput) // handler.BaseHandler.AtomicWriter
 }
 
 // WithAttrs returns a child handler with the provided keyValues added to the context.
 // If keyValues is empty, the original handler is returned.
 func (h *zapHandler) WithAttrs(keyValues []any) handler.Chainer {
-	fields := keyValuesToZapFields(keyValues)
+	keyValues = h.base.ValidateKeys(keyValues)
+
+	fields := keyValuesToZapFields(h.base.RedactValues(keyValues))
 	if len(fields) == 0 {
 		return h
 	}
@@ -219,20 +493,14 @@ func (h *zapHandler) WithGroup(name string) handler.Chainer {
 	}
 
 	clone := h.clone()
-	clone.logger = h.logger.With(zap.Namespace(name))
+	clone.logger = h.logger.With(zap.Namespace(h.base.ConvertKey(name)))
 
 	return clone
 }
 
 // SetLevel dynamically changes the minimum level of logs that will be processed.
 func (h *zapHandler) SetLevel(level handler.LogLevel) error {
-	if err := h.base.SetLevel(level); err != nil {
-		return err
-	}
-
-	h.atomicLevel.SetLevel(levelMapper.Map(level))
-
-	return nil
+	return h.base.SetLevel(level)
 }
 
 // SetOutput changes the destination for log output.
@@ -257,21 +525,22 @@ func (h *zapHandler) WithCaller(enabled bool) handler.FeatureToggler {
 	clone.base = newBase
 	clone.withCaller = enabled
 
-	// If enabling, we can use WithOptions to preserve existing fields/groups
+	// If enabling, we can use WithOptions to preserve existing fields/groups.
+	// The logger already has the caller skip.
 	if enabled {
-		clone.logger = h.logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(newBase.CallerSkip()))
+		clone.logger = h.logger.WithOptions(zap.AddCaller())
 		return clone
 	}
 
 	// If disabling, we must rebuild (zap has no RemoveCaller), losing contextual fields.
 	newZapOpts := buildZapOpts(newBase)
-	clone.logger = zap.New(zapcore.NewCore(h.encoderFactory(), h.writeSyncer, h.atomicLevel), newZapOpts...)
+	clone.logger = zap.New(zapcore.NewCore(h.encoderFactory(), h.writeSyncer, coreLevel), newZapOpts...)
 	clone.zapOpts = newZapOpts
 
 	return clone
 }
 
-// WithTrace returns a new handler that enables or disables stack trace logging for error-level logs.
+// WithTrace returns a new handler that enables or disables stack trace logging at the trace level and above.
 // It returns the original handler if the enabled value is unchanged.
 func (h *zapHandler) WithTrace(enabled bool) handler.FeatureToggler {
 	newBase := h.base.WithTrace(enabled)
@@ -285,13 +554,13 @@ func (h *zapHandler) WithTrace(enabled bool) handler.FeatureToggler {
 
 	// Enable via WithOptions
 	if enabled {
-		clone.logger = h.logger.WithOptions(zap.AddStacktrace(zapcore.ErrorLevel))
+		clone.logger = h.logger.WithOptions(zap.AddStacktrace(levelMapper.Map(newBase.TraceLevel())))
 		return clone
 	}
 
 	// Disable via rebuild
 	newZapOpts := buildZapOpts(newBase)
-	clone.logger = zap.New(zapcore.NewCore(h.encoderFactory(), h.writeSyncer, h.atomicLevel), newZapOpts...)
+	clone.logger = zap.New(zapcore.NewCore(h.encoderFactory(), h.writeSyncer, coreLevel), newZapOpts...)
 	clone.zapOpts = newZapOpts
 
 	return clone
@@ -305,24 +574,11 @@ func (h *zapHandler) WithLevel(level handler.LogLevel) handler.Configurable {
 		return h
 	}
 
-	newLevel := zap.NewAtomicLevelAt(levelMapper.Map(level))
-	// Deep clone required as we are changing the core's level ref
-	newZapOpts := make([]zap.Option, len(h.zapOpts))
-	copy(newZapOpts, h.zapOpts)
+	// The level is not part of the zap core, so fields and groups are kept
+	clone := h.clone()
+	clone.base = newBase
 
-	return &zapHandler{
-		base: newBase,
-		logger: zap.New(
-			zapcore.NewCore(h.encoderFactory(), h.writeSyncer, newLevel),
-			newZapOpts...),
-		atomicLevel:    newLevel,
-		encoderFactory: h.encoderFactory,
-		writeSyncer:    h.writeSyncer,
-		zapOpts:        newZapOpts,
-		withCaller:     h.withCaller,
-		withTrace:      h.withTrace,
-		callerSkip:     h.callerSkip,
-	}
+	return clone
 }
 
 // WithOutput returns a new handler with the output writer set permanently.
@@ -335,17 +591,14 @@ func (h *zapHandler) WithOutput(w io.Writer) handler.Configurable {
 		return h
 	}
 
-	newWriteSyncer := zapcore.AddSync(newBase.AtomicWriter())
-	newAtomicLevel := zap.NewAtomicLevelAt(h.atomicLevel.Level())
-	newZapOpts := make([]zap.Option, len(h.zapOpts))
-	copy(newZapOpts, h.zapOpts)
+	newWriteSyncer := zapcore.AddSync(newBase.ErrorRecordingWriter())
+	newZapOpts := buildZapOpts(newBase)
 
 	return &zapHandler{
 		base: newBase,
 		logger: zap.New(
-			zapcore.NewCore(h.encoderFactory(), newWriteSyncer, newAtomicLevel),
+			zapcore.NewCore(h.encoderFactory(), newWriteSyncer, coreLevel),
 			newZapOpts...),
-		atomicLevel:    newAtomicLevel,
 		encoderFactory: h.encoderFactory,
 		writeSyncer:    newWriteSyncer,
 		zapOpts:        newZapOpts,
@@ -393,7 +646,6 @@ func (h *zapHandler) clone() *zapHandler {
 	return &zapHandler{
 		base:           h.base,
 		logger:         h.logger,
-		atomicLevel:    h.atomicLevel,
 		encoderFactory: h.encoderFactory,
 		writeSyncer:    h.writeSyncer,
 		zapOpts:        h.zapOpts,
@@ -404,15 +656,18 @@ func (h *zapHandler) clone() *zapHandler {
 }
 
 // buildZapOpts creates zap.Option slice from BaseHandler state.
+// The logger always gets the caller skip, even with caller reporting
+// disabled, so that its skip is the handler's callerSkip.
 func buildZapOpts(base *handler.BaseHandler) []zap.Option {
-	opts := make([]zap.Option, 0, 2)
+	// AddCallerSkip needs to account for our adapter's internal frames
+	opts := make([]zap.Option, 0, 3)
+	opts = append(opts, zap.AddCallerSkip(base.CallerSkip()))
 	if base.CallerEnabled() {
-		// AddCallerSkip needs to account for our adapter's internal frames
-		opts = append(opts, zap.AddCaller(), zap.AddCallerSkip(base.CallerSkip()))
+		opts = append(opts, zap.AddCaller())
 	}
 	if base.TraceEnabled() {
-		// Add stack trace to logs at Error level and above
-		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
+		// Add stack trace to logs at the trace level and above
+		opts = append(opts, zap.AddStacktrace(levelMapper.Map(base.TraceLevel())))
 	}
 	return opts
 }
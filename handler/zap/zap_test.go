@@ -0,0 +1,251 @@
+package zap_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog"
+	"github.com/balinomad/go-unilog/handler"
+	"github.com/balinomad/go-unilog/handler/zap"
+)
+
+// BenchmarkHandle_CallerSkip measures records carrying a caller skip. A
+// repeated skip reuses the cached skip-adjusted logger; a new skip builds one.
+func BenchmarkHandle_CallerSkip(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("repeated skip", func(b *testing.B) {
+		h, err := zap.New(zap.WithOutput(io.Discard), zap.WithCaller(true))
+		if err != nil {
+			b.Fatalf("New() error = %v", err)
+		}
+		r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", Skip: 2}
+
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = h.Handle(ctx, r)
+		}
+	})
+
+	b.Run("new skip", func(b *testing.B) {
+		r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", Skip: 2}
+
+		b.ReportAllocs()
+		for b.Loop() {
+			// A fresh handler has no cached logger for the skip
+			b.StopTimer()
+			h, err := zap.New(zap.WithOutput(io.Discard), zap.WithCaller(true))
+			if err != nil {
+				b.Fatalf("New() error = %v", err)
+			}
+			b.StartTimer()
+
+			_ = h.Handle(ctx, r)
+		}
+	})
+}
+
+func TestLogger_WithLevelFromContext(t *testing.T) {
+	t.Parallel()
+
+	type debugKey struct{}
+	fromCtx := func(ctx context.Context) (unilog.LogLevel, bool) {
+		if ctx.Value(debugKey{}) != nil {
+			return unilog.DebugLevel, true
+		}
+		return 0, false
+	}
+
+	var buf bytes.Buffer
+	h, err := zap.New(zap.WithOutput(&buf), zap.WithLevel(handler.InfoLevel))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	l, err := unilog.NewLogger(h, unilog.WithLevelFromContext(fromCtx))
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	debugCtx := context.WithValue(context.Background(), debugKey{}, true)
+	l.Debug(context.Background(), "dropped at the handler level")
+	l.Trace(debugCtx, "dropped below the context level")
+	l.Debug(debugCtx, "logged for this request")
+	_ = h.(handler.Syncer).Sync()
+
+	out := buf.String()
+	if !strings.Contains(out, "logged for this request") {
+		t.Errorf("output %q does not contain the DEBUG record", out)
+	}
+	if strings.Contains(out, "dropped") {
+		t.Errorf("output %q contains a dropped record", out)
+	}
+}
+
+func TestWithKeyCase_Group(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h, err := zap.New(zap.WithOutput(&buf), zap.WithKeyCase(handler.KeyCaseSnake))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	g := h.(handler.Chainer).WithGroup("HTTPRequest")
+	r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", KeyValues: []any{"statusCode", 200}}
+	if err := g.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	_ = h.(handler.Syncer).Sync()
+
+	if out := buf.String(); !strings.Contains(out, `"http_request":{"status_code":200}`) {
+		t.Errorf("output = %s, want group http_request with key status_code", out)
+	}
+}
+
+// lastCaller returns the caller field of the last JSON line in buf,
+// or "" if it has none.
+func lastCaller(t *testing.T, buf *bytes.Buffer) string {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry struct {
+		Caller string `json:"caller"`
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+		t.Fatalf("json.Unmarshal(%q) error = %v", lines[len(lines)-1], err)
+	}
+
+	return entry.Caller
+}
+
+// here returns the file:line of its caller, as zap's short caller encoder
+// renders it, offset by delta lines.
+func here(delta int) string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), line+delta)
+}
+
+func TestHandle_Caller(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(t *testing.T, buf *bytes.Buffer, opts ...zap.ZapOption) handler.Handler {
+		t.Helper()
+		opts = append(opts, zap.WithOutput(buf), zap.WithCaller(true), zap.WithCallerShortPath(true))
+		h, err := zap.New(opts...)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		return h
+	}
+
+	t.Run("record skip", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		h := newHandler(t, &buf)
+
+		r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", Skip: 1}
+		want := here(1)
+		_ = h.Handle(context.Background(), r)
+		if got := lastCaller(t, &buf); got != want {
+			t.Errorf("caller = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("through logger", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		l, err := unilog.NewLogger(newHandler(t, &buf))
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		for range 2 { // The second record uses the cached logger
+			want := here(1)
+			l.Info(context.Background(), "msg")
+			if got := lastCaller(t, &buf); got != want {
+				t.Errorf("caller = %q, want %q", got, want)
+			}
+		}
+	})
+
+	t.Run("below caller level", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		l, err := unilog.NewLogger(newHandler(t, &buf, zap.WithCallerLevel(handler.WarnLevel)))
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		l.Info(context.Background(), "msg")
+		if got := lastCaller(t, &buf); got != "" {
+			t.Errorf("caller = %q, want none below the caller level", got)
+		}
+
+		want := here(1)
+		l.Warn(context.Background(), "msg")
+		if got := lastCaller(t, &buf); got != want {
+			t.Errorf("caller = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestWithPrettyJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		derive func(h handler.Handler, buf *bytes.Buffer) (handler.Handler, *bytes.Buffer)
+	}{
+		{"new", func(h handler.Handler, buf *bytes.Buffer) (handler.Handler, *bytes.Buffer) { return h, buf }},
+		{"with attrs", func(h handler.Handler, buf *bytes.Buffer) (handler.Handler, *bytes.Buffer) {
+			return h.(handler.Chainer).WithAttrs([]any{"service", "api"}).(handler.Handler), buf
+		}},
+		{"with output", func(h handler.Handler, _ *bytes.Buffer) (handler.Handler, *bytes.Buffer) {
+			var out bytes.Buffer
+			return h.(handler.Configurable).WithOutput(&out).(handler.Handler), &out
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var initial bytes.Buffer
+			h, err := zap.New(zap.WithOutput(&initial), zap.WithPrettyJSON(true))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			h, buf := tt.derive(h, &initial)
+
+			for range 2 {
+				r := &handler.Record{Time: time.Now(), Level: handler.InfoLevel, Message: "msg", KeyValues: []any{"k", "v"}}
+				if err := h.Handle(context.Background(), r); err != nil {
+					t.Fatalf("Handle() error = %v", err)
+				}
+			}
+			_ = h.(handler.Syncer).Sync()
+
+			out := buf.String()
+			if !strings.Contains(out, "{\n  \"level\": ") || !strings.Contains(out, "\n  \"k\": \"v\"") {
+				t.Errorf("output is not indented:\n%s", out)
+			}
+			if !strings.HasSuffix(out, "}\n") || strings.Count(out, "}\n{") != 1 {
+				t.Errorf("records do not end with a newline:\n%s", out)
+			}
+			dec := json.NewDecoder(buf)
+			for i := range 2 {
+				var v map[string]any
+				if err := dec.Decode(&v); err != nil {
+					t.Fatalf("record %d: Decode() error = %v", i, err)
+				}
+			}
+		})
+	}
+}
@@ -30,6 +30,15 @@ func WithLevel(level handler.LogLevel) ZerologOption {
 	}
 }
 
+// WithEnabledLevels restricts the handler to the given levels instead of
+// every level at or above the minimum, e.g. TRACE and ERROR only.
+// The minimum level becomes the lowest enabled level.
+func WithEnabledLevels(levels ...handler.LogLevel) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithEnabledLevels(levels...)(o.base)
+	}
+}
+
 // WithOutput sets the output writer.
 func WithOutput(w io.Writer) ZerologOption {
 	return func(o *zerologOptions) error {
@@ -37,6 +46,14 @@ func WithOutput(w io.Writer) ZerologOption {
 	}
 }
 
+// WithFailFast makes New return an error if the output is not writable,
+// instead of failing on the first log. The default value is false.
+func WithFailFast(enabled bool) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithFailFast(enabled)(o.base)
+	}
+}
+
 // WithFormat sets the output format ("json" or "console").
 func WithFormat(format string) ZerologOption {
 	return func(o *zerologOptions) error {
@@ -55,13 +72,73 @@ func WithCaller(enabled bool) ZerologOption {
 	}
 }
 
-// WithTrace enables stack traces for ERROR and above.
+// WithTrace enables stack traces for ERROR and above, or for the level
+// set with WithTraceLevel.
 func WithTrace(enabled bool) ZerologOption {
 	return func(o *zerologOptions) error {
 		return handler.WithTrace(enabled)(o.base)
 	}
 }
 
+// WithTraceLevel sets the minimum level that carries a stack trace when
+// tracing is enabled. The default value is handler.DefaultTraceLevel.
+func WithTraceLevel(level handler.LogLevel) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithTraceLevel(level)(o.base)
+	}
+}
+
+// WithMaxStackDepth limits stack traces to the top n frames closest to the
+// log call site. The default value is handler.DefaultMaxStackDepth.
+func WithMaxStackDepth(n int) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithMaxStackDepth(n)(o.base)
+	}
+}
+
+// WithOutputSwapHook registers fn to be called after SetOutput replaced the
+// output writer, e.g. to close the old writer. Writes made after fn is
+// called go to the new writer.
+func WithOutputSwapHook(fn func(old, new io.Writer)) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithOutputSwapHook(fn)(o.base)
+	}
+}
+
+// WithFieldValidator registers fn to check every field key. Rejected keys
+// are logged in a sanitized snake_case form, with the original kept in an
+// "_invalid_field_<n>" field.
+func WithFieldValidator(fn func(key string) error) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithFieldValidator(fn)(o.base)
+	}
+}
+
+// WithMaxLineBytes truncates every rendered record to at most n bytes.
+// JSON records keep their leading fields and end with "_truncated":true.
+// See handler.WithMaxLineBytes.
+func WithMaxLineBytes(n int) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithMaxLineBytes(n)(o.base)
+	}
+}
+
+// WithKeyCase normalizes attribute keys and group names to the given case,
+// e.g. handler.KeyCaseSnake renders both "requestID" and "request-id" as
+// "request_id". The default value is handler.KeyCaseAsIs.
+func WithKeyCase(c handler.KeyCase) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithKeyCase(c)(o.base)
+	}
+}
+
+// WithMetricsProvider registers a provider notified of every handled record.
+func WithMetricsProvider(p handler.MetricsProvider) ZerologOption {
+	return func(o *zerologOptions) error {
+		return handler.WithMetricsProvider(p)(o.base)
+	}
+}
+
 // historyOp is a closure that applies attributes or groups to a zerolog Context.
 type historyOp func(zerolog.Context) zerolog.Context
 
@@ -84,6 +161,7 @@ var (
 	_ handler.CallerAdjuster = (*zerologHandler)(nil)
 	_ handler.FeatureToggler = (*zerologHandler)(nil)
 	_ handler.MutableConfig  = (*zerologHandler)(nil)
+	_ handler.RawWriter      = (*zerologHandler)(nil)
 )
 
 // levelMapper maps unilog log levels to zerolog log levels.
@@ -103,6 +181,8 @@ func New(opts ...ZerologOption) (handler.Handler, error) {
 	o := &zerologOptions{
 		base: &handler.BaseOptions{
 			Level:        handler.InfoLevel,
+			TraceLevel:   handler.DefaultTraceLevel,
+			CallerLevel:  handler.MinLevel,
 			Output:       os.Stderr,
 			Format:       "json",
 			ValidFormats: validFormats,
@@ -132,11 +212,16 @@ func New(opts ...ZerologOption) (handler.Handler, error) {
 }
 
 // Handle implements the handler.Handler interface for zerolog.
-func (h *zerologHandler) Handle(_ context.Context, r *handler.Record) error {
-	if !h.Enabled(r.Level) {
+func (h *zerologHandler) Handle(ctx context.Context, r *handler.Record) error {
+	if r.IsZero() {
+		return handler.ErrZeroRecord
+	}
+	if !h.base.EnabledContext(ctx, r.Level) {
 		return nil
 	}
 
+	keyValues := h.base.ValidateKeys(r.KeyValues)
+
 	// Use cached logger if no dynamic skip is needed
 	l := h.logger
 	if h.withCaller && r.Skip > 0 {
@@ -149,18 +234,19 @@ func (h *zerologHandler) Handle(_ context.Context, r *handler.Record) error {
 	event.Time(zerolog.TimestampFieldName, r.Time)
 
 	// Add key-value pairs
-	for i := 0; i < len(r.KeyValues)-1; i += 2 {
-		key := fmt.Sprint(r.KeyValues[i])
-		addField(event, key, r.KeyValues[i+1])
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		key := fmt.Sprint(keyValues[i])
+		addField(event, key, keyValues[i+1])
 	}
 
 	// Add stack trace if enabled
-	if h.withTrace && r.Level >= handler.ErrorLevel {
-		event.Stack()
+	if h.withTrace && r.Level >= h.base.TraceLevel() {
+		event.Str("stack", handler.CaptureStack(0, h.base.MaxStackDepth()))
 	}
 
 	// Send message
 	event.Msg(r.Message)
+	h.base.RecordHandled(r.Level)
 
 	return nil
 }
@@ -170,6 +256,11 @@ func (h *zerologHandler) Enabled(level handler.LogLevel) bool {
 	return h.base.Enabled(level)
 }
 
+// WriteRaw writes a pre-rendered line to the output, bypassing the backend.
+func (h *zerologHandler) WriteRaw(level handler.LogLevel, payload []byte) error {
+	return h.base.WriteRaw(level, payload)
+}
+
 // HandlerState returns the underlying BaseHandler.
 func (h *zerologHandler) HandlerState() handler.HandlerState {
 	return h.base
@@ -187,6 +278,8 @@ func (h *zerologHandler) Features() handler.HandlerFeatures {
 
 // WithAttrs returns a new logger with the provided keyValues added to the context.
 func (h *zerologHandler) WithAttrs(keyValues []any) handler.Chainer {
+	keyValues = h.base.ValidateKeys(keyValues)
+
 	if len(keyValues) < 2 {
 		return h
 	}
@@ -215,6 +308,7 @@ func (h *zerologHandler) WithGroup(name string) handler.Chainer {
 	}
 
 	clone := h.clone()
+	name = h.base.ConvertKey(name)
 
 	// Create closure for replay
 	op := func(ctx zerolog.Context) zerolog.Context {
@@ -229,13 +323,7 @@ func (h *zerologHandler) WithGroup(name string) handler.Chainer {
 
 // SetLevel dynamically changes the minimum level of logs that will be processed.
 func (h *zerologHandler) SetLevel(level handler.LogLevel) error {
-	if err := h.base.SetLevel(level); err != nil {
-		return err
-	}
-
-	h.logger = h.logger.Level(levelMapper.Map(level))
-
-	return nil
+	return h.base.SetLevel(level)
 }
 
 // SetOutput sets the log destination.
@@ -314,15 +402,16 @@ func (h *zerologHandler) WithCallerSkipDelta(delta int) handler.CallerAdjuster {
 
 // rebuildLogger rebuilds the zerolog logger.
 func (h *zerologHandler) rebuildLogger() {
-	var w io.Writer = h.base.AtomicWriter()
+	var w io.Writer = h.base.ErrorRecordingWriter()
 	if h.base.Format() == "console" {
 		w = zerolog.ConsoleWriter{
-			Out:        h.base.AtomicWriter(),
+			Out:        h.base.ErrorRecordingWriter(),
 			TimeFormat: time.RFC3339,
 		}
 	}
 
-	cx := zerolog.New(w).Level(levelMapper.Map(h.base.Level())).With().Timestamp()
+	// Handle filters by level, honoring a context level
+	cx := zerolog.New(w).Level(zerolog.TraceLevel).With().Timestamp()
 
 	if h.base.CallerEnabled() {
 		cx = cx.CallerWithSkipFrameCount(h.base.CallerSkip())
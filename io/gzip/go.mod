@@ -0,0 +1,3 @@
+module github.com/balinomad/go-unilog/io/gzip
+
+go 1.24
@@ -0,0 +1,175 @@
+// Package gzip provides a thread-safe io.Writer that gzip-compresses a log
+// stream on the fly, e.g. for archiving NDJSON output directly to a
+// .ndjson.gz file.
+//
+// Unlike compressing rotated backups, this compresses the live stream.
+// Each Write is treated as a record boundary: the compressor is flushed
+// after every write, so a reader can decompress everything written so far
+// even while the file is still open (it will report io.ErrUnexpectedEOF at
+// the end of the data instead of a clean EOF until the stream is closed).
+//
+// CPU cost
+//
+//	Compression runs synchronously in the caller of Write. Flushing at every
+//	record boundary also lowers the compression ratio, since the compressor
+//	cannot look across records as far as it otherwise would, and adds a few
+//	bytes of sync marker per record. Expect a noticeable CPU overhead per
+//	log call compared to plain file output; use gzip.BestSpeed (see
+//	WithLevel) for high-volume logs.
+//
+// Rotation
+//
+//	Rotate finalizes the current gzip member (writing its footer) and starts a
+//	new one. If the underlying writer has a Rotate() error method, such as
+//	rotating.RotatingWriter, it is called in between, so each file holds a
+//	complete gzip stream. Do not let the underlying writer rotate on its own
+//	(e.g. by size): a file split in the middle of a member is not valid gzip.
+package gzip
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// options holds the configuration for a Writer.
+type options struct {
+	level int // Compression level
+}
+
+// Option sets optional configuration for New.
+type Option func(*options)
+
+// WithLevel sets the compression level, from gzip.HuffmanOnly to gzip.BestCompression.
+// The default is gzip.DefaultCompression.
+func WithLevel(level int) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// rotator is implemented by writers that support manual rotation.
+type rotator interface {
+	Rotate() error
+}
+
+// syncer is implemented by writers that can flush to stable storage.
+type syncer interface {
+	Sync() error
+}
+
+// Writer is an io.WriteCloser that gzip-compresses everything written to it.
+// It is safe for concurrent use by multiple goroutines.
+type Writer struct {
+	mu     sync.Mutex
+	out    io.Writer
+	zw     *gzip.Writer
+	closed bool
+}
+
+// Ensure interface conformance.
+var _ io.WriteCloser = (*Writer)(nil)
+
+// New creates a Writer that writes the compressed stream to w.
+// Returns error if w is nil or the compression level is invalid.
+func New(w io.Writer, opts ...Option) (*Writer, error) {
+	if w == nil {
+		return nil, errors.New("writer cannot be nil")
+	}
+
+	o := &options{level: gzip.DefaultCompression}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	zw, err := gzip.NewWriterLevel(w, o.level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compression level: %w", err)
+	}
+
+	return &Writer{out: w, zw: zw}, nil
+}
+
+// Write compresses p and flushes the compressor, so that p is fully
+// decodable from the output once Write returns.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errors.New("write attempt on closed writer")
+	}
+
+	n, err := w.zw.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	return n, w.zw.Flush()
+}
+
+// Sync flushes the compressor and, if the underlying writer supports it,
+// syncs the underlying writer. The gzip member is not finalized.
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	if err := w.zw.Flush(); err != nil {
+		return err
+	}
+
+	if s, ok := w.out.(syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Rotate finalizes the current gzip member, rotates the underlying writer
+// if it supports rotation, and starts a new gzip member.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return errors.New("rotate attempt on closed writer")
+	}
+
+	if err := w.zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize gzip member: %w", err)
+	}
+
+	// Start the new member even if rotation fails, so the writer stays usable
+	defer w.zw.Reset(w.out)
+
+	if r, ok := w.out.(rotator); ok {
+		return r.Rotate()
+	}
+
+	return nil
+}
+
+// Close finalizes the gzip stream and closes the underlying writer
+// if it implements io.Closer. Safe to call multiple times.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	err := w.zw.Close()
+	if c, ok := w.out.(io.Closer); ok {
+		err = errors.Join(err, c.Close())
+	}
+
+	return err
+}
@@ -0,0 +1,182 @@
+package gzip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rotatingBuffer collects each rotated segment as a separate buffer.
+type rotatingBuffer struct {
+	segments []*bytes.Buffer
+	syncs    int
+	closed   bool
+}
+
+func newRotatingBuffer() *rotatingBuffer {
+	return &rotatingBuffer{segments: []*bytes.Buffer{{}}}
+}
+
+func (b *rotatingBuffer) Write(p []byte) (int, error) {
+	return b.segments[len(b.segments)-1].Write(p)
+}
+
+func (b *rotatingBuffer) Rotate() error {
+	b.segments = append(b.segments, &bytes.Buffer{})
+	return nil
+}
+
+func (b *rotatingBuffer) Sync() error {
+	b.syncs++
+	return nil
+}
+
+func (b *rotatingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+// decompress reads everything decodable from data and reports whether the stream was complete.
+func decompress(t *testing.T, data []byte) (string, bool) {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	return string(out), err == nil
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(nil); err == nil {
+		t.Error("New(nil) error = nil, want error")
+	}
+	if _, err := New(io.Discard, WithLevel(42)); err == nil {
+		t.Error("New() with invalid level error = nil, want error")
+	}
+	if _, err := New(io.Discard, WithLevel(gzip.BestSpeed)); err != nil {
+		t.Errorf("New() with BestSpeed error = %v, want nil", err)
+	}
+}
+
+func TestWriter_PartialReadsValid(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, _ := New(&buf)
+
+	records := []string{`{"msg":"a"}` + "\n", `{"msg":"b"}` + "\n"}
+	for i, rec := range records {
+		if _, err := w.Write([]byte(rec)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		got, complete := decompress(t, buf.Bytes())
+		if want := strings.Join(records[:i+1], ""); got != want {
+			t.Errorf("partial read = %q, want %q", got, want)
+		}
+		if complete {
+			t.Error("stream reported complete before Close")
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got, complete := decompress(t, buf.Bytes()); !complete || got != strings.Join(records, "") {
+		t.Errorf("after Close = %q (complete %v), want full stream", got, complete)
+	}
+
+	if _, err := w.Write([]byte("late\n")); err == nil {
+		t.Error("Write() after Close error = nil, want error")
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close() error = %v, want nil", err)
+	}
+}
+
+func TestWriter_Rotate(t *testing.T) {
+	t.Parallel()
+
+	out := newRotatingBuffer()
+	w, _ := New(out)
+
+	_, _ = w.Write([]byte("first\n"))
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	_, _ = w.Write([]byte("second\n"))
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	_ = w.Close()
+
+	if len(out.segments) != 2 {
+		t.Fatalf("segments = %d, want 2", len(out.segments))
+	}
+	for i, want := range []string{"first\n", "second\n"} {
+		got, complete := decompress(t, out.segments[i].Bytes())
+		if !complete || got != want {
+			t.Errorf("segment %d = %q (complete %v), want %q", i, got, complete, want)
+		}
+	}
+	if out.syncs != 1 {
+		t.Errorf("underlying syncs = %d, want 1", out.syncs)
+	}
+	if !out.closed {
+		t.Error("underlying writer not closed")
+	}
+}
+
+func TestWriter_RotateWithoutRotator(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, _ := New(&buf)
+
+	_, _ = w.Write([]byte("a\n"))
+	_ = w.Rotate()
+	_, _ = w.Write([]byte("b\n"))
+	_ = w.Close()
+
+	// Multiple members in one stream decode as a concatenation
+	if got, complete := decompress(t, buf.Bytes()); !complete || got != "a\nb\n" {
+		t.Errorf("multistream = %q (complete %v), want %q", got, complete, "a\nb\n")
+	}
+}
+
+func TestWriter_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, _ := New(&buf)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_, _ = w.Write([]byte("line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	_ = w.Close()
+
+	got, _ := decompress(t, buf.Bytes())
+	if n := strings.Count(got, "line\n"); n != 1000 {
+		t.Errorf("lines = %d, want 1000", n)
+	}
+}
@@ -1,5 +1,3 @@
 module github.com/balinomad/go-unilog/io/rotating
 
 go 1.24
-
-require github.com/balinomad/go-mockfs/v2 v2.0.0-rc.1
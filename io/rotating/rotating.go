@@ -1,9 +1,9 @@
-// Package rotating provides a safe, durable, size-based log file writer with automatic rotation.
+// Package rotating provides a safe, durable, size- or line-based log file writer with automatic rotation.
 //
 // Purpose
 //
 //	rotating.RotatingWriter is a simple, concurrent-safe writer that appends to a file
-//	and rotates it when it grows beyond a configured size. Rotation is performed
+//	and rotates it when it grows beyond a configured size or line count. Rotation is performed
 //	durably (fsync on files) using atomic renames so that after rotation there is
 //	always a usable active file.
 //
@@ -30,22 +30,47 @@
 package rotating
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
+// DefaultMaxBackups is the number of rotated backups New retains unless
+// changed with WithMaxBackups.
+const DefaultMaxBackups = 7
+
+// DefaultFileMode is the permission of active files created by New unless
+// changed with WithFileMode.
+const DefaultFileMode os.FileMode = 0o644
+
+// backupTimeFormat is the timestamp suffix of rotated backups.
+const backupTimeFormat = "2006-01-02T15-04-05.000000"
+
+// compressedSuffix is appended to the name of compressed backups.
+const compressedSuffix = ".gz"
+
 // options holds the configuration for a RotatingWriter.
 type options struct {
-	maxSizeMB  int         // 0 => no size-based rotation
-	maxBackups int         // 0 => keep all backups (no cleanup)
-	errHandler func(error) // optional non-fatal error handler
+	maxSizeMB  int           // 0 => no size-based rotation
+	maxLines   int           // 0 => no line-based rotation
+	maxBackups int           // 0 => keep all backups (no cleanup)
+	maxAge     time.Duration // 0 => keep backups of any age
+	compress   bool          // gzip rotated backups
+	utc        bool          // UTC backup timestamps instead of local time
+	mode       os.FileMode   // permission of new active files
+	interval   time.Duration // 0 => clean up after every rotation
+	marker     string        // "" => no rotation marker
+	uid, gid   int           // -1 => keep the process owner
+	errHandler func(error)   // optional non-fatal error handler
 }
 
 // Option sets optional configuration for New.
@@ -59,6 +84,16 @@ func WithMaxSizeMB(mb int) Option {
 	}
 }
 
+// WithMaxLines sets the maximum number of lines in the active file.
+// The file is rotated as soon as it contains n lines, counted as '\n' bytes written.
+// It can be combined with WithMaxSizeMB, in which case either limit triggers rotation.
+// Zero disables line-based rotation. Must be non-negative.
+func WithMaxLines(n int) Option {
+	return func(o *options) {
+		o.maxLines = n
+	}
+}
+
 // WithMaxBackups sets how many rotated backups to retain.
 // Zero means keep all rotated files. Must be non-negative.
 func WithMaxBackups(n int) Option {
@@ -67,6 +102,76 @@ func WithMaxBackups(n int) Option {
 	}
 }
 
+// WithMaxAge removes rotated backups whose timestamp is older than d, in
+// addition to the WithMaxBackups limit. Zero keeps backups of any age, which
+// is the default. Must be non-negative.
+func WithMaxAge(d time.Duration) Option {
+	return func(o *options) {
+		o.maxAge = d
+	}
+}
+
+// WithCompress makes rotated backups gzip-compressed, adding ".gz" to their
+// name. Compression runs in the background after rotation, with cleanup.
+// The default value is false.
+func WithCompress(enabled bool) Option {
+	return func(o *options) {
+		o.compress = enabled
+	}
+}
+
+// WithUTC makes backup timestamps use UTC instead of local time.
+// The default value is false.
+func WithUTC(enabled bool) Option {
+	return func(o *options) {
+		o.utc = enabled
+	}
+}
+
+// WithFileMode sets the permission of the active file when it is created.
+// Existing files keep their permission. The default value is DefaultFileMode.
+func WithFileMode(mode os.FileMode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithCleanupInterval makes backup cleanup run at most once per d instead
+// of after every rotation, so that a burst of rotations reads the directory
+// once. Rotations within d of the last cleanup schedule a single cleanup at
+// the end of the interval, and Close runs a pending cleanup before returning,
+// so at most maxBackups backups remain eventually. Zero cleans up after every
+// rotation, which is the default. Must be non-negative.
+func WithCleanupInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithRotationMarker sets a line written as the first bytes of every new
+// active file created by rotation, so that humans and simple parsers tailing
+// the file can notice the boundary. A trailing newline is added if missing.
+// The marker counts toward the size and line limits of the new file.
+// Empty disables the marker, which is the default.
+func WithRotationMarker(line string) Option {
+	return func(o *options) {
+		o.marker = line
+	}
+}
+
+// WithFileOwner sets the owner applied with os.Chown to the active file every
+// time it is opened, e.g. so that a log-shipping sidecar running as another
+// user can read it. Rotated backups are renamed, not copied, so they keep the
+// ownership. Either id may be -1 to leave it unchanged, which is the default.
+// Changing the owner usually requires privileges; a failure is returned by
+// New or by the rotation that opened the file. It is ignored on Windows.
+func WithFileOwner(uid, gid int) Option {
+	return func(o *options) {
+		o.uid = uid
+		o.gid = gid
+	}
+}
+
 // WithErrorHandler sets an optional handler for non-fatal internal errors.
 // The handler will be called asynchronously and must not call back into this writer.
 // If nil, internal problems are printed to os.Stderr.
@@ -84,10 +189,21 @@ func WithErrorHandler(h func(error)) Option {
 type RotatingWriter struct {
 	mu          sync.Mutex     // Protects all mutable state
 	filename    string         // Active log file path
-	maxSize     int64          // bytes; 0 => no rotation
-	maxBackups  int            // 0 => no cleanup
+	maxSize     int64          // bytes; 0 => no size-based rotation
+	maxLines    int64          // 0 => no line-based rotation
+	maxBackups  int            // 0 => keep all backups
+	maxAge      time.Duration  // 0 => keep backups of any age
+	compress    bool           // gzip rotated backups
+	utc         bool           // UTC backup timestamps
+	mode        os.FileMode    // Permission of new active files
+	interval    time.Duration  // Minimum time between cleanups; 0 => no debounce
+	lastCleanup time.Time      // Start of the last cleanup, tracked only if interval > 0
+	cleanupT    *time.Timer    // Pending debounced cleanup; nil => none
+	marker      []byte         // Written after rotation; nil => no marker
+	uid, gid    int            // File owner; -1 => unchanged
 	file        io.WriteCloser // Active log file handle
 	currentSize int64          // Current file size in bytes
+	currentLine int64          // Current number of lines in file, tracked only if maxLines > 0
 	errHandler  func(error)    // Optional error handler, fallback to stderr
 }
 
@@ -96,7 +212,8 @@ var _ io.WriteCloser = (*RotatingWriter)(nil)
 
 // New constructs a RotatingWriter.
 // filename must be non-empty. Options customize behavior.
-// Defaults: no size-based rotation, 7 backups retained, errors to stderr.
+// Defaults: no size- or line-based rotation, DefaultMaxBackups backups retained,
+// errors to stderr.
 func New(filename string, opts ...Option) (*RotatingWriter, error) {
 	if filename == "" {
 		return nil, fmt.Errorf("filename cannot be empty")
@@ -104,7 +221,10 @@ func New(filename string, opts ...Option) (*RotatingWriter, error) {
 
 	o := &options{
 		maxSizeMB:  0,
-		maxBackups: 7,
+		maxBackups: DefaultMaxBackups,
+		mode:       DefaultFileMode,
+		uid:        -1,
+		gid:        -1,
 		errHandler: nil,
 	}
 
@@ -114,16 +234,39 @@ func New(filename string, opts ...Option) (*RotatingWriter, error) {
 	if o.maxSizeMB < 0 {
 		return nil, fmt.Errorf("max size must be non-negative")
 	}
+	if o.maxLines < 0 {
+		return nil, fmt.Errorf("max lines must be non-negative")
+	}
 	if o.maxBackups < 0 {
 		return nil, fmt.Errorf("max backups must be non-negative")
 	}
+	if o.maxAge < 0 {
+		return nil, fmt.Errorf("max age must be non-negative")
+	}
+	if o.interval < 0 {
+		return nil, fmt.Errorf("cleanup interval must be non-negative")
+	}
 
 	w := &RotatingWriter{
 		filename:   filename,
 		maxSize:    int64(o.maxSizeMB) * 1024 * 1024,
+		maxLines:   int64(o.maxLines),
 		maxBackups: o.maxBackups,
+		maxAge:     o.maxAge,
+		compress:   o.compress,
+		utc:        o.utc,
+		mode:       o.mode,
+		interval:   o.interval,
+		uid:        o.uid,
+		gid:        o.gid,
 		errHandler: o.errHandler,
 	}
+	if o.marker != "" {
+		w.marker = []byte(o.marker)
+		if !bytes.HasSuffix(w.marker, []byte{'\n'}) {
+			w.marker = append(w.marker, '\n')
+		}
+	}
 
 	if err := w.openExistingOrNew(); err != nil {
 		return nil, err
@@ -131,8 +274,110 @@ func New(filename string, opts ...Option) (*RotatingWriter, error) {
 	return w, nil
 }
 
+// KeepAllBackups is the Config.MaxBackups value that keeps every rotated
+// backup, like WithMaxBackups(0).
+const KeepAllBackups = -1
+
+// Config is a declarative, serializable form of the writer options, e.g. for
+// a logging section of an application's JSON or YAML configuration. Its zero
+// value, apart from Filename, has the defaults of New: no rotation and
+// DefaultMaxBackups backups. Use NewFromConfig to create a writer from it.
+type Config struct {
+	Filename        string        `json:"filename" yaml:"filename"`
+	MaxSizeMB       int           `json:"max_size_mb,omitempty" yaml:"max_size_mb,omitempty"`           // See WithMaxSizeMB
+	MaxLines        int           `json:"max_lines,omitempty" yaml:"max_lines,omitempty"`               // See WithMaxLines
+	MaxBackups      int           `json:"max_backups,omitempty" yaml:"max_backups,omitempty"`           // See WithMaxBackups; 0 uses DefaultMaxBackups, KeepAllBackups keeps all
+	MaxAge          time.Duration `json:"max_age,omitempty" yaml:"max_age,omitempty"`                   // See WithMaxAge
+	Compress        bool          `json:"compress,omitempty" yaml:"compress,omitempty"`                 // See WithCompress
+	UTC             bool          `json:"utc,omitempty" yaml:"utc,omitempty"`                           // See WithUTC
+	FileMode        os.FileMode   `json:"file_mode,omitempty" yaml:"file_mode,omitempty"`               // See WithFileMode; 0 uses DefaultFileMode
+	CleanupInterval time.Duration `json:"cleanup_interval,omitempty" yaml:"cleanup_interval,omitempty"` // See WithCleanupInterval
+	RotationMarker  string        `json:"rotation_marker,omitempty" yaml:"rotation_marker,omitempty"`   // See WithRotationMarker
+	Owner           *FileOwner    `json:"owner,omitempty" yaml:"owner,omitempty"`                       // See WithFileOwner; nil keeps the process owner
+}
+
+// FileOwner is the owner of the active file in a Config.
+type FileOwner struct {
+	UID int `json:"uid" yaml:"uid"` // -1 leaves the user unchanged
+	GID int `json:"gid" yaml:"gid"` // -1 leaves the group unchanged
+}
+
+// Validate reports every invalid setting of c, joined into one error,
+// or nil if c is valid.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Filename == "" {
+		errs = append(errs, errors.New("filename cannot be empty"))
+	}
+	if c.MaxSizeMB < 0 {
+		errs = append(errs, fmt.Errorf("max size must be non-negative, got %d MB", c.MaxSizeMB))
+	}
+	if c.MaxLines < 0 {
+		errs = append(errs, fmt.Errorf("max lines must be non-negative, got %d", c.MaxLines))
+	}
+	if c.MaxBackups < KeepAllBackups {
+		errs = append(errs, fmt.Errorf("max backups must be non-negative or %d to keep all, got %d", KeepAllBackups, c.MaxBackups))
+	}
+	if c.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("max age must be non-negative, got %v", c.MaxAge))
+	}
+	if c.FileMode&^os.ModePerm != 0 {
+		errs = append(errs, fmt.Errorf("file mode must only have permission bits, got %v", c.FileMode))
+	}
+	if c.CleanupInterval < 0 {
+		errs = append(errs, fmt.Errorf("cleanup interval must be non-negative, got %v", c.CleanupInterval))
+	}
+	if c.CleanupInterval > 0 && c.MaxBackups == KeepAllBackups && c.MaxAge == 0 && !c.Compress {
+		errs = append(errs, errors.New("cleanup interval requires max backups, max age or compress, since backups are never cleaned up"))
+	}
+	if c.RotationMarker != "" && c.MaxSizeMB == 0 && c.MaxLines == 0 {
+		errs = append(errs, errors.New("rotation marker requires max size or max lines, since the file never rotates"))
+	}
+	if o := c.Owner; o != nil && (o.UID < -1 || o.GID < -1) {
+		errs = append(errs, fmt.Errorf("owner ids must be -1 or non-negative, got uid %d and gid %d", o.UID, o.GID))
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewFromConfig validates cfg and constructs a RotatingWriter from it.
+// opts are applied after cfg, for settings that cannot be serialized, such
+// as WithErrorHandler.
+func NewFromConfig(cfg Config, opts ...Option) (*RotatingWriter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rotating writer config: %w", err)
+	}
+
+	cfgOpts := []Option{
+		WithMaxSizeMB(cfg.MaxSizeMB),
+		WithMaxLines(cfg.MaxLines),
+		WithMaxAge(cfg.MaxAge),
+		WithCompress(cfg.Compress),
+		WithUTC(cfg.UTC),
+		WithCleanupInterval(cfg.CleanupInterval),
+		WithRotationMarker(cfg.RotationMarker),
+	}
+	switch cfg.MaxBackups {
+	case 0:
+		// Keep the default of New
+	case KeepAllBackups:
+		cfgOpts = append(cfgOpts, WithMaxBackups(0))
+	default:
+		cfgOpts = append(cfgOpts, WithMaxBackups(cfg.MaxBackups))
+	}
+	if cfg.FileMode != 0 {
+		cfgOpts = append(cfgOpts, WithFileMode(cfg.FileMode))
+	}
+	if cfg.Owner != nil {
+		cfgOpts = append(cfgOpts, WithFileOwner(cfg.Owner.UID, cfg.Owner.GID))
+	}
+
+	return New(cfg.Filename, append(cfgOpts, opts...)...)
+}
+
 // Write appends p to the active file. If the write would exceed maximum size,
-// rotation is attempted first. Write is safe for concurrent callers.
+// rotation is attempted first. If the active file reaches the maximum number
+// of lines after the write, it is rotated. Write is safe for concurrent callers.
 func (w *RotatingWriter) Write(p []byte) (n int, err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -145,17 +390,11 @@ func (w *RotatingWriter) Write(p []byte) (n int, err error) {
 	// If rotation is needed before writing, try to rotate
 	if w.maxSize > 0 && w.currentSize+int64(len(p)) > w.maxSize {
 		if rerr := w.rotate(); rerr != nil {
-			// Rotation failed: ensure file handle exists before attempting write
-			if w.file == nil {
-				if oerr := w.openExistingOrNew(); oerr != nil {
-					// Can't reopen: return rotation and reopen errors
-					return 0, errors.Join(
-						fmt.Errorf("reopen failed: %w", oerr),
-						fmt.Errorf("rotation failed: %w", rerr))
-				}
+			// Can't reopen: return rotation and reopen errors
+			if err := w.recoverRotation(rerr); err != nil {
+				return 0, err
 			}
 			// File handle exists: proceed with write despite rotation failure
-			w.report(fmt.Errorf("rotation failed: %w", rerr))
 		}
 	}
 
@@ -166,9 +405,50 @@ func (w *RotatingWriter) Write(p []byte) (n int, err error) {
 	}
 	w.currentSize += int64(n)
 
+	if w.maxLines > 0 {
+		w.currentLine += int64(bytes.Count(p[:n], []byte{'\n'}))
+		if w.currentLine >= w.maxLines {
+			if rerr := w.rotate(); rerr != nil {
+				// The record is already written: report, don't fail the write
+				if err := w.recoverRotation(rerr); err != nil {
+					w.report(err)
+				}
+				// Retry after another maxLines lines, not on every write
+				w.currentLine = 0
+			}
+		}
+	}
+
 	return n, nil
 }
 
+// recoverRotation reopens the active file if the failed rotation rerr left
+// it closed, so that later writes do not fail. It reports rerr and returns
+// nil if the file is usable, or returns both errors if it cannot be reopened.
+// Caller must hold the lock.
+func (w *RotatingWriter) recoverRotation(rerr error) error {
+	if w.file == nil {
+		if oerr := w.openExistingOrNew(); oerr != nil {
+			return errors.Join(
+				fmt.Errorf("reopen failed: %w", oerr),
+				fmt.Errorf("rotation failed: %w", rerr))
+		}
+	}
+	w.report(fmt.Errorf("rotation failed: %w", rerr))
+
+	return nil
+}
+
+// CurrentLineCount returns the number of lines in the active file.
+// Lines are only tracked when line-based rotation is enabled (see WithMaxLines);
+// otherwise it returns 0.
+func (w *RotatingWriter) CurrentLineCount() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.currentLine
+}
+
 // Rotate triggers log file rotation manually. It is safe to call multiple times.
 // It returns an error if rotation fails.
 // Some rotation errors are reported and the writer may still be usable.
@@ -180,11 +460,20 @@ func (w *RotatingWriter) Rotate() error {
 }
 
 // Close closes the underlying file. Safe to call multiple times.
+// A cleanup delayed by WithCleanupInterval runs before Close returns.
 func (w *RotatingWriter) Close() error {
 	w.mu.Lock()
-	defer w.mu.Unlock()
+	err := w.close()
+	pending := w.cleanupT != nil && w.cleanupT.Stop()
+	w.cleanupT = nil
+	w.mu.Unlock()
 
-	return w.close()
+	// cleanup takes the lock itself
+	if pending {
+		w.cleanup()
+	}
+
+	return err
 }
 
 // close closes the file. Caller must hold the lock.
@@ -208,7 +497,8 @@ func (w *RotatingWriter) close() error {
 //   - close current file
 //   - rename current -> X.TIMESTAMP
 //   - create new active file
-//   - trigger async cleanup if maxBackups > 0
+//   - write the rotation marker, if any
+//   - trigger async cleanup and compression, if any, debounced by interval
 func (w *RotatingWriter) rotate() error {
 	// Best-effort sync current file
 	if err := w.trySync(); err != nil {
@@ -225,7 +515,11 @@ func (w *RotatingWriter) rotate() error {
 	// Collision risk is negligible in practice: requires rotating twice within
 	// the same microsecond on the same machine, which is prevented by the serial
 	// nature of rotate() under mutex lock.
-	timestamp := time.Now().Format("2006-01-02T15-04-05.000000")
+	now := time.Now()
+	if w.utc {
+		now = now.UTC()
+	}
+	timestamp := now.Format(backupTimeFormat)
 	backupFilename := fmt.Sprintf("%s.%s", w.filename, timestamp)
 
 	// Rename current file to timestamped backup
@@ -240,20 +534,82 @@ func (w *RotatingWriter) rotate() error {
 		return fmt.Errorf("failed to rename log file: %w", err)
 	}
 
-	// Trigger async cleanup if maxBackups > 0
-	if w.maxBackups > 0 {
+	// Trigger async cleanup if backups are limited or compressed
+	if w.maxBackups > 0 || w.maxAge > 0 || w.compress {
+		w.scheduleCleanup()
+	}
+
+	if err := w.openExistingOrNew(); err != nil {
+		return err
+	}
+
+	w.writeMarker()
+
+	return nil
+}
+
+// writeMarker writes the rotation marker to the active file and accounts
+// for it in the size and line counters. Failures are reported, not returned,
+// since the new file is usable without the marker.
+// Caller must hold the lock.
+func (w *RotatingWriter) writeMarker() {
+	if w.marker == nil {
+		return
+	}
+
+	n, err := w.file.Write(w.marker)
+	w.currentSize += int64(n)
+	if w.maxLines > 0 {
+		w.currentLine += int64(bytes.Count(w.marker[:n], []byte{'\n'}))
+	}
+	if err != nil {
+		w.report(fmt.Errorf("failed to write rotation marker: %w", err))
+	}
+}
+
+// scheduleCleanup starts an async cleanup, or delays it until interval has
+// elapsed since the last one. Rotations while a cleanup is delayed share it.
+// Caller must hold the lock.
+func (w *RotatingWriter) scheduleCleanup() {
+	if w.interval <= 0 {
+		go w.cleanup()
+		return
+	}
+	if w.cleanupT != nil {
+		return
+	}
+
+	now := time.Now()
+	wait := w.lastCleanup.Add(w.interval).Sub(now)
+	if wait <= 0 {
+		w.lastCleanup = now
 		go w.cleanup()
+		return
 	}
 
-	return w.openExistingOrNew()
+	w.cleanupT = time.AfterFunc(wait, func() {
+		w.mu.Lock()
+		w.cleanupT = nil
+		w.lastCleanup = time.Now()
+		w.mu.Unlock()
+
+		w.cleanup()
+	})
 }
 
-// cleanup removes old backup files beyond maxBackups limit.
+// cleanup compresses backups if enabled, then removes the backups beyond
+// the maxBackups limit or older than maxAge.
 // Must be called asynchronously to avoid blocking Write.
 func (w *RotatingWriter) cleanup() {
 	w.mu.Lock()
 	filename := w.filename
 	maxBackups := w.maxBackups
+	maxAge := w.maxAge
+	compress := w.compress
+	loc := time.Local
+	if w.utc {
+		loc = time.UTC
+	}
 	w.mu.Unlock()
 
 	dir := filepath.Dir(filename)
@@ -269,6 +625,7 @@ func (w *RotatingWriter) cleanup() {
 	type backup struct {
 		name      string
 		timestamp string
+		time      time.Time
 	}
 
 	var backups []backup
@@ -283,30 +640,47 @@ func (w *RotatingWriter) cleanup() {
 			continue
 		}
 
-		suffix := name[len(prefix):]
+		suffix := strings.TrimSuffix(name[len(prefix):], compressedSuffix)
 
 		// Validate by attempting to parse as timestamp
-		if _, err := time.Parse("2006-01-02T15-04-05.000000", suffix); err != nil {
+		t, err := time.ParseInLocation(backupTimeFormat, suffix, loc)
+		if err != nil {
 			continue
 		}
 
+		if compress && !strings.HasSuffix(name, compressedSuffix) {
+			if err := compressFile(filepath.Join(dir, name)); err != nil {
+				w.report(fmt.Errorf("cleanup failed to compress %s: %w", name, err))
+			} else {
+				name += compressedSuffix
+			}
+		}
+
 		backups = append(backups, backup{
 			name:      name,
 			timestamp: suffix,
+			time:      t,
 		})
 	}
 
-	if len(backups) <= w.maxBackups {
-		return
-	}
-
 	// Sort by timestamp descending (newest first)
 	sort.Slice(backups, func(i, j int) bool {
 		return backups[i].timestamp > backups[j].timestamp
 	})
 
-	// Remove oldest backups beyond maxBackups
-	for _, b := range backups[maxBackups:] {
+	// Remove oldest backups beyond maxBackups, then those older than maxAge
+	keep := len(backups)
+	if maxBackups > 0 && keep > maxBackups {
+		keep = maxBackups
+	}
+	if maxAge > 0 {
+		cutoff := time.Now().Add(-maxAge)
+		for keep > 0 && backups[keep-1].time.Before(cutoff) {
+			keep--
+		}
+	}
+
+	for _, b := range backups[keep:] {
 		path := filepath.Join(dir, b.name)
 		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 			w.report(fmt.Errorf("cleanup failed to remove %s: %w", b.name, err))
@@ -314,6 +688,55 @@ func (w *RotatingWriter) cleanup() {
 	}
 }
 
+// compressFile gzips the named file to name+".gz" and removes the original.
+// It leaves the file alone if the compressed file already exists, e.g.
+// because a concurrent cleanup is compressing it.
+func compressFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	gzName := name + compressedSuffix
+	dst, err := os.OpenFile(gzName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		if os.IsExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(gzName)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(name)
+}
+
 // trySync calls Sync on the underlying *os.File if possible.
 // Returns error on failure.
 func (w *RotatingWriter) trySync() error {
@@ -362,11 +785,19 @@ func (w *RotatingWriter) openExistingOrNew() error {
 	}
 
 	// Open the file for writing, create if it doesn't exist, and append
-	f, err := os.OpenFile(w.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	f, err := os.OpenFile(w.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, w.mode)
 	if err != nil {
 		return fmt.Errorf("failed to open file %s: %w", w.filename, err)
 	}
 
+	// Apply the configured owner; Windows has no POSIX ownership
+	if (w.uid != -1 || w.gid != -1) && runtime.GOOS != "windows" {
+		if err := f.Chown(w.uid, w.gid); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to change owner of file %s: %w", w.filename, err)
+		}
+	}
+
 	// Get current size from opened file
 	info, err := f.Stat()
 	if err != nil {
@@ -376,9 +807,43 @@ func (w *RotatingWriter) openExistingOrNew() error {
 
 	w.file = f
 	w.currentSize = info.Size()
+	w.currentLine = 0
+	if w.maxLines > 0 && w.currentSize > 0 {
+		lines, err := countLines(w.filename)
+		if err != nil {
+			// Non-fatal: line-based rotation restarts counting from zero
+			w.report(fmt.Errorf("failed to count lines in %s: %w", w.filename, err))
+		}
+		w.currentLine = lines
+	}
+
 	return nil
 }
 
+// countLines returns the number of '\n' bytes in the named file.
+func countLines(filename string) (int64, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var (
+		count int64
+		buf   = make([]byte, 32*1024)
+	)
+	for {
+		n, err := f.Read(buf)
+		count += int64(bytes.Count(buf[:n], []byte{'\n'}))
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
 // safeRename is a wrapper around os.Rename that first removes the destination
 // path if it already exists. This is necessary on Windows because os.Rename
 // will fail if the destination path already exists.
@@ -0,0 +1,57 @@
+package rotating_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/io/rotating"
+)
+
+func TestWithCleanupInterval(t *testing.T) {
+	t.Parallel()
+
+	t.Run("negative interval", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "app.log")
+		if _, err := rotating.New(filename, rotating.WithCleanupInterval(-time.Second)); err == nil {
+			t.Error("New() error = nil, want error for negative interval")
+		}
+	})
+
+	t.Run("delayed cleanup runs on close", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "app.log")
+
+		w, err := rotating.New(filename,
+			rotating.WithMaxBackups(1),
+			rotating.WithCleanupInterval(time.Hour),
+			// The first cleanup is async and may outlive the temp dir
+			rotating.WithErrorHandler(func(error) {}),
+		)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		for range 4 {
+			if _, err := w.Write([]byte("line\n")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := w.Rotate(); err != nil {
+				t.Fatalf("Rotate() error = %v", err)
+			}
+		}
+
+		// Only the first rotation cleans up; later ones wait for the interval
+		if matches, _ := filepath.Glob(filename + ".*"); len(matches) < 2 {
+			t.Errorf("backups before Close = %d, want the delayed cleanup pending", len(matches))
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if matches, _ := filepath.Glob(filename + ".*"); len(matches) != 1 {
+			t.Errorf("backups after Close = %d, want 1", len(matches))
+		}
+	})
+}
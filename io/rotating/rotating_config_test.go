@@ -0,0 +1,149 @@
+package rotating_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/io/rotating"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("from json", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "app.log")
+
+		var cfg rotating.Config
+		data := `{"filename":` + strconv.Quote(filename) + `,"max_lines":2,"rotation_marker":"---"}`
+		if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+
+		// The default backup limit cleans up asynchronously, possibly after
+		// the temp dir is gone
+		w, err := rotating.NewFromConfig(cfg, rotating.WithErrorHandler(func(error) {}))
+		if err != nil {
+			t.Fatalf("NewFromConfig() error = %v", err)
+		}
+		defer w.Close()
+
+		if _, err := w.Write([]byte("a\nb\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		// Rotated at two lines; the marker starts the new file
+		if got := w.CurrentLineCount(); got != 1 {
+			t.Errorf("CurrentLineCount() = %d, want 1", got)
+		}
+	})
+
+	t.Run("invalid settings are all reported", func(t *testing.T) {
+		t.Parallel()
+		_, err := rotating.NewFromConfig(rotating.Config{
+			MaxBackups:     -2,
+			RotationMarker: "---",
+			Owner:          &rotating.FileOwner{UID: -2, GID: -1},
+		})
+		if err == nil {
+			t.Fatal("NewFromConfig() error = nil, want error")
+		}
+		for _, want := range []string{"filename", "max backups", "rotation marker", "owner"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error = %q, want it to mention %q", err, want)
+			}
+		}
+	})
+
+	t.Run("cleanup interval while keeping all backups", func(t *testing.T) {
+		t.Parallel()
+		err := rotating.Config{Filename: "app.log", MaxBackups: rotating.KeepAllBackups, CleanupInterval: 1}.Validate()
+		if err == nil || !strings.Contains(err.Error(), "max backups") {
+			t.Errorf("Validate() error = %v, want max backups error", err)
+		}
+	})
+
+	t.Run("max backups", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name       string
+			maxBackups int
+			interval   time.Duration
+			want       int
+		}{
+			{"omitted uses the default", 0, time.Hour, rotating.DefaultMaxBackups},
+			{"keep all", rotating.KeepAllBackups, 0, rotating.DefaultMaxBackups + 2},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				filename := filepath.Join(t.TempDir(), "app.log")
+				w, err := rotating.NewFromConfig(rotating.Config{
+					Filename:        filename,
+					MaxBackups:      tt.maxBackups,
+					CleanupInterval: tt.interval,
+				}, rotating.WithErrorHandler(func(error) {}))
+				if err != nil {
+					t.Fatalf("NewFromConfig() error = %v", err)
+				}
+
+				// Distinct timestamps keep every rotation's backup
+				for range rotating.DefaultMaxBackups + 2 {
+					if err := w.Rotate(); err != nil {
+						t.Fatalf("Rotate() error = %v", err)
+					}
+					time.Sleep(time.Millisecond)
+				}
+				// Close runs the delayed cleanup
+				if err := w.Close(); err != nil {
+					t.Fatalf("Close() error = %v", err)
+				}
+
+				if matches, _ := filepath.Glob(filename + ".*"); len(matches) != tt.want {
+					t.Errorf("backups = %d, want %d", len(matches), tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("writer settings", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "app.log")
+
+		var cfg rotating.Config
+		data := `{"filename":` + strconv.Quote(filename) + `,"max_age":3600000000000,"compress":true,"utc":true,"file_mode":384}`
+		if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		want := rotating.Config{Filename: filename, MaxAge: time.Hour, Compress: true, UTC: true, FileMode: 0o600}
+		if cfg != want {
+			t.Fatalf("config = %+v, want %+v", cfg, want)
+		}
+
+		w, err := rotating.NewFromConfig(cfg)
+		if err != nil {
+			t.Fatalf("NewFromConfig() error = %v", err)
+		}
+		defer w.Close()
+
+		if info, err := os.Stat(filename); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("file mode = %v, %v, want %v", info.Mode().Perm(), err, os.FileMode(0o600))
+		}
+	})
+
+	t.Run("invalid writer settings", func(t *testing.T) {
+		t.Parallel()
+		err := rotating.Config{Filename: "app.log", MaxAge: -1, FileMode: os.ModeDir | 0o644}.Validate()
+		for _, want := range []string{"max age", "file mode"} {
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package rotating_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/balinomad/go-unilog/io/rotating"
+)
+
+// countBackups returns the number of rotated backups of filename.
+func countBackups(t *testing.T, filename string) int {
+	t.Helper()
+
+	matches, err := filepath.Glob(filename + ".*")
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+
+	return len(matches)
+}
+
+func TestWithMaxLines(t *testing.T) {
+	t.Parallel()
+
+	const maxLines = 5
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	w, err := rotating.New(filename, rotating.WithMaxLines(maxLines), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	for range maxLines - 1 {
+		if _, err := w.Write([]byte("record\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if got := w.CurrentLineCount(); got != maxLines-1 {
+		t.Errorf("CurrentLineCount() = %d, want %d", got, maxLines-1)
+	}
+	if got := countBackups(t, filename); got != 0 {
+		t.Fatalf("backups before limit = %d, want 0", got)
+	}
+
+	if _, err := w.Write([]byte("record\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if got := countBackups(t, filename); got != 1 {
+		t.Fatalf("backups after limit = %d, want 1", got)
+	}
+	if got := w.CurrentLineCount(); got != 0 {
+		t.Errorf("CurrentLineCount() after rotation = %d, want 0", got)
+	}
+
+	matches, _ := filepath.Glob(filename + ".*")
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got := strings.Count(string(data), "\n"); got != maxLines {
+		t.Errorf("backup lines = %d, want %d", got, maxLines)
+	}
+}
+
+func TestWithMaxLines_MultiLineWrite(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	w, err := rotating.New(filename, rotating.WithMaxLines(3), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("a\nb\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := w.CurrentLineCount(); got != 2 {
+		t.Errorf("CurrentLineCount() = %d, want 2", got)
+	}
+
+	if _, err := w.Write([]byte("c\nd\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := countBackups(t, filename); got != 1 {
+		t.Errorf("backups = %d, want 1", got)
+	}
+}
+
+func TestWithMaxLines_ExistingFile(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(filename, []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	w, err := rotating.New(filename, rotating.WithMaxLines(3), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if got := w.CurrentLineCount(); got != 2 {
+		t.Errorf("CurrentLineCount() = %d, want 2", got)
+	}
+
+	if _, err := w.Write([]byte("three\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := countBackups(t, filename); got != 1 {
+		t.Errorf("backups = %d, want 1", got)
+	}
+}
+
+func TestWithMaxLines_RotationFailure(t *testing.T) {
+	t.Parallel()
+
+	const maxLines = 2
+	filename := filepath.Join(t.TempDir(), "app.log")
+	errs := make(chan error, 10)
+
+	w, err := rotating.New(filename,
+		rotating.WithMaxLines(maxLines),
+		rotating.WithMaxBackups(0),
+		rotating.WithErrorHandler(func(err error) { errs <- err }),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	// Removing the active file makes the rename in the next rotation fail
+	if _, err := w.Write([]byte("one\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := os.Remove(filename); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := w.Write([]byte("two\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := <-errs; !strings.Contains(err.Error(), "rotation failed") {
+		t.Errorf("reported error = %v, want rotation failure", err)
+	}
+
+	// The writer stays usable and backs off until maxLines more lines
+	if _, err := w.Write([]byte("three\n")); err != nil {
+		t.Fatalf("Write() after failed rotation error = %v", err)
+	}
+	if got := w.CurrentLineCount(); got != 1 {
+		t.Errorf("CurrentLineCount() after failed rotation = %d, want 1", got)
+	}
+	if got := countBackups(t, filename); got != 0 {
+		t.Errorf("backups = %d, want 0", got)
+	}
+
+	if _, err := w.Write([]byte("four\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := countBackups(t, filename); got != 1 {
+		t.Errorf("backups after retry = %d, want 1", got)
+	}
+}
+
+func TestWithMaxLines_Negative(t *testing.T) {
+	t.Parallel()
+
+	if _, err := rotating.New(filepath.Join(t.TempDir(), "app.log"), rotating.WithMaxLines(-1)); err == nil {
+		t.Error("New() with negative max lines error = nil, want error")
+	}
+}
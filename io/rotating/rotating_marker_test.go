@@ -0,0 +1,85 @@
+package rotating_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/balinomad/go-unilog/io/rotating"
+)
+
+func TestWithRotationMarker(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	w, err := rotating.New(filename,
+		rotating.WithMaxLines(3),
+		rotating.WithMaxBackups(0),
+		rotating.WithRotationMarker("--- rotated ---"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	// The marker counts toward the line limit of the new file
+	if got := w.CurrentLineCount(); got != 1 {
+		t.Errorf("CurrentLineCount() after rotation = %d, want 1", got)
+	}
+
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if want := "--- rotated ---\nafter\n"; string(data) != want {
+		t.Errorf("active file = %q, want %q", data, want)
+	}
+
+	matches, _ := filepath.Glob(filename + ".*")
+	if len(matches) != 1 {
+		t.Fatalf("backups = %d, want 1", len(matches))
+	}
+	backup, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if want := "before\n"; string(backup) != want {
+		t.Errorf("backup file = %q, want %q (no marker on the initial file)", backup, want)
+	}
+}
+
+func TestWithRotationMarker_Empty(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	w, err := rotating.New(filename, rotating.WithRotationMarker(""), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("active file = %q, want empty", data)
+	}
+}
@@ -0,0 +1,82 @@
+//go:build unix
+
+package rotating_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/balinomad/go-unilog/io/rotating"
+)
+
+// fileOwner returns the uid and gid of the named file.
+func fileOwner(t *testing.T, name string) (uid, gid int) {
+	t.Helper()
+
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatalf("Stat(%s) error = %v", name, err)
+	}
+	st := info.Sys().(*syscall.Stat_t)
+
+	return int(st.Uid), int(st.Gid)
+}
+
+func TestWithFileOwner(t *testing.T) {
+	t.Parallel()
+
+	// Without privileges, only the current owner can be applied
+	uid, gid := os.Getuid(), os.Getgid()
+	if uid == 0 {
+		uid, gid = 65534, 65534
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+
+	w, err := rotating.New(filename, rotating.WithFileOwner(uid, gid), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if gotUID, gotGID := fileOwner(t, filename); gotUID != uid || gotGID != gid {
+		t.Errorf("active file owner = %d:%d, want %d:%d", gotUID, gotGID, uid, gid)
+	}
+
+	if _, err := w.Write([]byte("line\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("found %d files after rotation, want 2", len(entries))
+	}
+	for _, e := range entries {
+		name := filepath.Join(dir, e.Name())
+		if gotUID, gotGID := fileOwner(t, name); gotUID != uid || gotGID != gid {
+			t.Errorf("%s owner = %d:%d, want %d:%d", e.Name(), gotUID, gotGID, uid, gid)
+		}
+	}
+}
+
+func TestWithFileOwner_Unprivileged(t *testing.T) {
+	t.Parallel()
+
+	if os.Getuid() == 0 {
+		t.Skip("root may change file ownership")
+	}
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	if _, err := rotating.New(filename, rotating.WithFileOwner(0, 0)); err == nil {
+		t.Error("New() error = nil, want error when changing owner without privileges")
+	}
+}
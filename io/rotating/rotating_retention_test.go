@@ -0,0 +1,143 @@
+package rotating_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/balinomad/go-unilog/io/rotating"
+)
+
+// waitFor polls cond until it holds or a second has passed, for effects of
+// the asynchronous cleanup.
+func waitFor(t *testing.T, cond func() bool) bool {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+
+	return cond()
+}
+
+func TestWithCompress(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	w, err := rotating.New(filename, rotating.WithCompress(true), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("record\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	var backups []string
+	if !waitFor(t, func() bool {
+		backups, _ = filepath.Glob(filename + ".*")
+		return len(backups) == 1 && strings.HasSuffix(backups[0], ".gz")
+	}) {
+		t.Fatalf("backups = %v, want one compressed backup", backups)
+	}
+
+	f, err := os.Open(backups[0])
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	if data, err := io.ReadAll(zr); err != nil || string(data) != "record\n" {
+		t.Errorf("backup content = %q, %v, want %q", data, err, "record\n")
+	}
+}
+
+func TestWithMaxAge(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	old := filename + "." + time.Now().Add(-48*time.Hour).Format("2006-01-02T15-04-05.000000")
+	if err := os.WriteFile(old, []byte("old\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	w, err := rotating.New(filename, rotating.WithMaxAge(24*time.Hour), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	if !waitFor(t, func() bool {
+		_, err := os.Stat(old)
+		return os.IsNotExist(err)
+	}) {
+		t.Error("backup older than max age was not removed")
+	}
+	if matches, _ := filepath.Glob(filename + ".*"); len(matches) != 1 {
+		t.Errorf("backups = %v, want the new backup only", matches)
+	}
+}
+
+func TestWithUTC(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	w, err := rotating.New(filename, rotating.WithUTC(true), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	before := time.Now().UTC().Add(-time.Second)
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	matches, _ := filepath.Glob(filename + ".*")
+	if len(matches) != 1 {
+		t.Fatalf("backups = %v, want 1", matches)
+	}
+	ts, err := time.Parse("2006-01-02T15-04-05.000000", strings.TrimPrefix(matches[0], filename+"."))
+	if err != nil {
+		t.Fatalf("backup timestamp error = %v", err)
+	}
+	if ts.Before(before) || ts.After(time.Now().UTC().Add(time.Second)) {
+		t.Errorf("backup timestamp = %v, want the current UTC time", ts)
+	}
+}
+
+func TestWithFileMode(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	w, err := rotating.New(filename, rotating.WithFileMode(0o600))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0o600 {
+		t.Errorf("file mode = %v, want %v", got, os.FileMode(0o600))
+	}
+}
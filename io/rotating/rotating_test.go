@@ -0,0 +1,205 @@
+package rotating_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/balinomad/go-unilog/io/rotating"
+)
+
+// assertFileContent checks that the content of filename is want.
+func assertFileContent(t *testing.T, filename, want string) {
+	t.Helper()
+
+	got, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", filename, err)
+	}
+	if string(got) != want {
+		t.Errorf("content of %s = %q, want %q", filename, got, want)
+	}
+}
+
+// backups returns the rotated backups of filename in name order, which is
+// rotation order.
+func backups(t *testing.T, filename string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filename + ".*")
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+
+	return matches
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		opts     []rotating.Option
+		wantErr  string
+	}{
+		{"defaults", "app.log", nil, ""},
+		{"subdirectory", filepath.Join("logs", "app.log"), nil, ""},
+		{"limits", "app.log", []rotating.Option{rotating.WithMaxSizeMB(10), rotating.WithMaxBackups(3)}, ""},
+		{"empty filename", "", nil, "filename cannot be empty"},
+		{"negative max size", "app.log", []rotating.Option{rotating.WithMaxSizeMB(-1)}, "max size must be non-negative"},
+		{"negative max backups", "app.log", []rotating.Option{rotating.WithMaxBackups(-1)}, "max backups must be non-negative"},
+		{"negative max age", "app.log", []rotating.Option{rotating.WithMaxAge(-1)}, "max age must be non-negative"},
+		{"negative cleanup interval", "app.log", []rotating.Option{rotating.WithCleanupInterval(-1)}, "cleanup interval must be non-negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			filename := tt.filename
+			if filename != "" {
+				filename = filepath.Join(t.TempDir(), filename)
+			}
+
+			w, err := rotating.New(filename, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("New() error = %v, want %q", err, tt.wantErr)
+				}
+				if w != nil {
+					t.Error("New() writer is not nil on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer w.Close()
+
+			if _, err := os.Stat(filename); err != nil {
+				t.Errorf("active file not created: %v", err)
+			}
+		})
+	}
+}
+
+func TestRotatingWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	over1MB := strings.Repeat("a", 1024*1024+1)
+
+	tests := []struct {
+		name        string
+		maxSizeMB   int
+		initial     string
+		writes      []string
+		wantFile    string
+		wantBackups []string // Contents, oldest first
+	}{
+		{"small write", 1, "", []string{"hello world"}, "hello world", nil},
+		{"appends to existing file", 1, "existing data. ", []string{"new data."}, "existing data. new data.", nil},
+		{"rotation", 1, "", []string{"initial write. ", over1MB}, over1MB, []string{"initial write. "}},
+		{"no rotation with zero max size", 0, "", []string{"content1", over1MB}, "content1" + over1MB, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			filename := filepath.Join(t.TempDir(), "app.log")
+			if tt.initial != "" {
+				if err := os.WriteFile(filename, []byte(tt.initial), 0o644); err != nil {
+					t.Fatalf("WriteFile() error = %v", err)
+				}
+			}
+
+			w, err := rotating.New(filename, rotating.WithMaxSizeMB(tt.maxSizeMB))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			for _, data := range tt.writes {
+				if _, err := w.Write([]byte(data)); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			assertFileContent(t, filename, tt.wantFile)
+			got := backups(t, filename)
+			if len(got) != len(tt.wantBackups) {
+				t.Fatalf("backups = %v, want %d", got, len(tt.wantBackups))
+			}
+			for i, want := range tt.wantBackups {
+				assertFileContent(t, got[i], want)
+			}
+		})
+	}
+}
+
+func TestRotatingWriter_Close(t *testing.T) {
+	t.Parallel()
+
+	w, err := rotating.New(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("first Close() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+	if _, err := w.Write([]byte("write after close")); err == nil {
+		t.Error("Write() after Close() error = nil, want error")
+	}
+}
+
+func TestRotatingWriter_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	w, err := rotating.New(filename, rotating.WithMaxSizeMB(1), rotating.WithMaxBackups(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	const (
+		goroutines = 20
+		writes     = 10
+	)
+	data := []byte(strings.Repeat("x", 50*1024))
+
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range writes {
+				if _, err := w.Write(data); err != nil {
+					t.Errorf("Write() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Rotation neither loses nor duplicates data
+	var total int64
+	for _, name := range append(backups(t, filename), filename) {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		total += info.Size()
+	}
+	if want := int64(goroutines * writes * len(data)); total != want {
+		t.Errorf("total size = %d, want %d", total, want)
+	}
+}
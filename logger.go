@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -47,12 +49,23 @@ type logger struct {
 	tog   handler.FeatureToggler
 	mcfg  handler.MutableConfig
 	snc   handler.Syncer
+	raw   handler.RawWriter
 	state handler.HandlerState
 
 	// Caller detection flags
-	needsPC   bool
-	needsSkip bool
-	skip      int
+	needsPC     bool
+	needsSkip   bool
+	skip        int
+	callerLevel LogLevel // Minimum level that gets caller information
+
+	// Logger-level options, inherited by derived loggers
+	opts loggerOptions
+
+	// Attributes added with With, by group-qualified key, and the current
+	// group path; tracked only with WithUniqueConstantAttrs. The map is
+	// shared by derived loggers and never modified.
+	constAttrs map[string]any
+	groupPath  string
 }
 
 // Ensure logger implements required interfaces.
@@ -71,27 +84,61 @@ var (
 //  3. logger.log()                       ← Skip
 //  4. [runtime.Callers called here]      ← Skip (implicit in Callers)
 //
-// To capture frame 1 from frame 3, we need skip=2 in the runtime.Callers() call.
-// However, runtime.Callers(skip) skips 'skip' frames BEFORE the Callers call itself.
-// Since we call runtime.Callers from frame 3, we use skip=2.
-//
-// But we store this as 3 to represent the total depth from user to logger.log(),
-// then subtract 1 when calling runtime.Callers: runtime.Callers(skip-1).
+// runtime.Callers(skip) counts its own frame as 0 and its caller (logger.log)
+// as 1, so frame 1 above is reached with runtime.Callers(3), i.e. the stored
+// skip value is passed to runtime.Callers unchanged.
 const internalSkipFrames = 3
 
 // NewLogger creates a new logger that wraps the given handler.
-func NewLogger(h handler.Handler) (Logger, error) {
-	return NewAdvancedLogger(h)
+func NewLogger(h handler.Handler, opts ...LoggerOption) (Logger, error) {
+	return NewAdvancedLogger(h, opts...)
+}
+
+// NewMultiBackendLogger creates a new logger that sends every record to each
+// of the given handlers, combined with handler.NewMultiHandler. A single
+// handler is used directly. Returns error if no handler is given or any
+// handler is nil.
+func NewMultiBackendLogger(handlers ...handler.Handler) (Logger, error) {
+	switch len(handlers) {
+	case 0:
+		return nil, errors.New("at least one handler is required")
+	case 1:
+		return NewLogger(handlers[0])
+	}
+
+	h, err := handler.NewMultiHandler(handlers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogger(h)
 }
 
 // NewAdvancedLogger creates a new advanced logger that wraps the given handler.
-// Returns error if handler is nil or skip is negative.
-func NewAdvancedLogger(h handler.Handler) (AdvancedLogger, error) {
+// Returns error if handler is nil, any option fails, or WithHandleTimeout is
+// used with a handler that reports the caller natively.
+func NewAdvancedLogger(h handler.Handler, opts ...LoggerOption) (AdvancedLogger, error) {
 	if h == nil {
 		return nil, errors.New("handler cannot be nil")
 	}
 
-	return newLogger(h, internalSkipFrames), nil
+	var o loggerOptions
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	// Native caller resolution walks the stack of the goroutine that calls
+	// Handle, which the timeout moves off the logging goroutine
+	if o.handleTimeout > 0 && h.Features().Supports(handler.FeatNativeCaller) {
+		return nil, errors.New("handle timeout is not supported by handlers with native caller reporting")
+	}
+
+	l := newLogger(h, internalSkipFrames)
+	l.opts = o
+
+	return l, nil
 }
 
 // newLogger creates a logger with specified skip offset.
@@ -120,6 +167,11 @@ func newLogger(h handler.Handler, skip int) *logger {
 		needsSkip: features.Supports(handler.FeatNativeCaller) && state.CallerEnabled(),
 	}
 
+	l.callerLevel = handler.MinLevel
+	if s, ok := state.(interface{ CallerLevel() LogLevel }); ok {
+		l.callerLevel = s.CallerLevel()
+	}
+
 	// Cache optional interfaces
 	l.ch, _ = h.(handler.Chainer)
 	l.cfg, _ = h.(handler.Configurable)
@@ -127,6 +179,7 @@ func newLogger(h handler.Handler, skip int) *logger {
 	l.tog, _ = h.(handler.FeatureToggler)
 	l.mcfg, _ = h.(handler.MutableConfig)
 	l.snc, _ = h.(handler.Syncer)
+	l.raw, _ = h.(handler.RawWriter)
 
 	return l
 }
@@ -134,7 +187,8 @@ func newLogger(h handler.Handler, skip int) *logger {
 // log logs a message at the given level with optional skip adjustment.
 func (l *logger) log(ctx context.Context, level LogLevel, msg string, skipDelta int, keyValues ...any) {
 	// Fast path: check level before allocations
-	if !l.h.Enabled(level) {
+	ctx, ok := l.enabled(ctx, level)
+	if !ok {
 		return
 	}
 
@@ -149,9 +203,54 @@ func (l *logger) log(ctx context.Context, level LogLevel, msg string, skipDelta
 	needsSkip := l.needsSkip
 	l.mu.RUnlock()
 
-	// Ensure keyValues is even
-	if len(keyValues)%2 != 0 {
-		keyValues = keyValues[:len(keyValues)-1]
+	// Report misuse before the arguments are normalized
+	if l.opts.strict {
+		if err := validateKeyValues(keyValues); err != nil {
+			if l.opts.strictPanic {
+				panic(err)
+			}
+			// Point the fallback at the original call site, as for handler errors below
+			getGlobalFallback().LogWithSkip(ctx, ErrorLevel, "invalid log arguments", currentSkip+skipDelta-1,
+				"original_level", level.String(),
+				"original_msg", msg,
+				"strict_error", err.Error())
+		}
+	}
+
+	// Records below the handler's caller level carry no caller
+	if level < l.callerLevel {
+		needsPC, needsSkip = false, false
+	}
+
+	if len(keyValues) == 0 {
+		// Fast path: message-only calls skip normalization entirely
+		keyValues = nil
+	} else {
+		// Ensure keyValues is even
+		if len(keyValues)%2 != 0 {
+			keyValues = keyValues[:len(keyValues)-1]
+		}
+
+		// Apply the logger's own limit, counting the dropped pairs
+		if n := l.opts.maxAttrs; n > 0 && len(keyValues) > 2*n {
+			keyValues = append(keyValues[:2*n:2*n], handler.TruncatedAttrsKey, len(keyValues)/2-n)
+		}
+
+		// Cap runaway key-value lists, flagging the truncation
+		if limit := handler.MaxKeyValuesPerRecord(); limit > 0 && len(keyValues) > 2*limit {
+			keyValues = append(keyValues[:2*limit:2*limit],
+				handler.TruncatedKey, true,
+				handler.KeyValueCountKey, len(keyValues)/2)
+		}
+
+		keyValues = resolveLogValues(keyValues)
+	}
+
+	// Attach the time left before the call's deadline, never truncated
+	if key := l.opts.deadlineKey; key != "" && ctx != nil {
+		if deadline, ok := ctx.Deadline(); ok {
+			keyValues = append(keyValues[:len(keyValues):len(keyValues)], key, time.Until(deadline).Milliseconds())
+		}
 	}
 
 	// Use sync.Pool to avoid heap allocations
@@ -165,43 +264,142 @@ func (l *logger) log(ctx context.Context, level LogLevel, msg string, skipDelta
 
 	// Handle caller detection
 	skip := currentSkip + skipDelta
-	if needsPC && skip > 0 {
-		var pcs [1]uintptr
-		// skip-1 because runtime.Callers itself adds implicit frame
-		if runtime.Callers(skip-1, pcs[:]) > 0 {
-			r.PC = pcs[0]
+	if (needsPC || needsSkip) && skip > 0 && len(l.opts.skipPackages) > 0 {
+		// Walk past frames of the configured packages
+		var pcs [maxCallerSearchDepth]uintptr
+		n := runtime.Callers(skip, pcs[:])
+		pc, extra := skipPackageFrames(pcs[:n], l.opts.skipPackages)
+		if needsPC {
+			r.PC = pc
+		}
+		if needsSkip {
+			r.Skip = skip + extra
+		}
+	} else {
+		if needsPC && skip > 0 {
+			var pcs [1]uintptr
+			if runtime.Callers(skip, pcs[:]) > 0 {
+				r.PC = pcs[0]
+			}
+		}
+		if needsSkip && skip > 0 {
+			r.Skip = skip
 		}
 	}
-	if needsSkip && skip > 0 {
-		r.Skip = skip
+
+	for _, modify := range l.opts.modifiers {
+		modify(r)
 	}
 
-	// Handle errors with global fallback logger
-	if err := l.h.Handle(ctx, r); err != nil {
-		fb := getGlobalFallback()
-		fb.Log(ctx, ErrorLevel, "log handler failed",
+	// Handle errors with global fallback logger. The handler is called
+	// directly when there is no timeout, keeping the call depth that
+	// handlers with native caller reporting rely on.
+	var err error
+	completed := true
+	if l.opts.handleTimeout > 0 {
+		completed, err = l.handleWithTimeout(ctx, r)
+	} else {
+		err = l.h.Handle(ctx, r)
+	}
+	// Point the fallback at the original call site: a LogWithSkip delta of 0
+	// reports this frame, which is frame 1 for runtime.Callers, hence skip-1.
+	switch {
+	case !completed:
+		getGlobalFallback().LogWithSkip(ctx, ErrorLevel, "log handler timed out", skip-1,
+			"original_level", level.String(),
+			"original_msg", msg,
+			"handle_timeout", true)
+	case err != nil:
+		getGlobalFallback().LogWithSkip(ctx, ErrorLevel, "log handler failed", skip-1,
 			"original_level", level.String(),
 			"original_msg", msg,
 			"handler_error", err.Error())
 	}
 
-	// Cleanup and return to pool
+	// Cleanup and return to pool, unless an abandoned handler still uses r
 	// Important: We do not nullify KeyValues here as the slice backing array
 	// might be retained by the caller of Log(). We just detach the pointer.
-	r.KeyValues = nil
-	recordPool.Put(r)
+	if completed {
+		r.KeyValues = nil
+		recordPool.Put(r)
+	}
+
+	l.terminate(level, msg, keyValues)
+}
 
-	// Handle termination levels
+// terminate exits the process or panics for Fatal and Panic levels.
+func (l *logger) terminate(level LogLevel, msg string, keyValues []any) {
 	switch level {
 	case FatalLevel:
 		// Note: specific handlers (like zap) might have their own
 		// exit logic, but we enforce it here to guarantee contract.
+		if l.opts.exitFunc != nil {
+			l.opts.exitFunc(1)
+			return
+		}
 		osExit(1)
 	case PanicLevel:
+		if l.opts.panicFunc != nil {
+			l.opts.panicFunc(msg)
+			return
+		}
+		if l.opts.panicValue != nil {
+			panic(l.opts.panicValue(msg, keyValues))
+		}
 		panic(msg)
 	}
 }
 
+// enabled reports whether a call at level with ctx is logged, consulting the
+// level set with WithLevelFromContext before the handler's. If that level
+// applies, the returned context carries it to the handler, as set with
+// handler.WithContextLevel. Otherwise ctx is returned unchanged.
+func (l *logger) enabled(ctx context.Context, level LogLevel) (context.Context, bool) {
+	if fn := l.opts.levelFromCtx; fn != nil && ctx != nil {
+		if lvl, ok := fn(ctx); ok {
+			if level < lvl {
+				return ctx, false
+			}
+			return handler.WithContextLevel(ctx, lvl), true
+		}
+	}
+
+	return ctx, l.h.Enabled(level)
+}
+
+// validateKeyValues reports the first key in keyValues that is not a string,
+// or a final key without a value.
+func validateKeyValues(keyValues []any) error {
+	for i := 0; i < len(keyValues); i += 2 {
+		if _, ok := keyValues[i].(string); !ok {
+			return fmt.Errorf("key at position %d is %T, not a string", i, keyValues[i])
+		}
+	}
+	if len(keyValues)%2 != 0 {
+		return fmt.Errorf("key %q has no value", keyValues[len(keyValues)-1])
+	}
+
+	return nil
+}
+
+// handleWithTimeout passes r to the handler, bounded by the timeout set with
+// WithHandleTimeout. It reports false if the call timed out and was
+// abandoned; the handler may then still be using r.
+func (l *logger) handleWithTimeout(ctx context.Context, r *handler.Record) (completed bool, err error) {
+	done := make(chan error, 1)
+	go func() { done <- l.h.Handle(ctx, r) }()
+
+	timer := time.NewTimer(l.opts.handleTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return true, err
+	case <-timer.C:
+		return false, nil
+	}
+}
+
 // Log is the generic logging entry point.
 func (l *logger) Log(ctx context.Context, level LogLevel, msg string, keyValues ...any) {
 	l.log(ctx, level, msg, 0, keyValues...)
@@ -212,6 +410,24 @@ func (l *logger) Enabled(level LogLevel) bool {
 	return l.h.Enabled(level)
 }
 
+// Level returns the handler's current minimum level. It is read from the
+// handler state when the state exposes a Level method, as BaseHandler does.
+// Otherwise it is the lowest level the handler reports as enabled, or
+// MaxLevel if the handler enables none.
+func (l *logger) Level() LogLevel {
+	if s, ok := l.h.HandlerState().(interface{ Level() LogLevel }); ok {
+		return s.Level()
+	}
+
+	for level := handler.MinLevel; level < handler.MaxLevel; level++ {
+		if l.h.Enabled(level) {
+			return level
+		}
+	}
+
+	return handler.MaxLevel
+}
+
 // With returns a new Logger with the given key-value pairs added.
 func (l *logger) With(keyValues ...any) Logger {
 	l.mu.RLock()
@@ -221,7 +437,59 @@ func (l *logger) With(keyValues ...any) Logger {
 		return l
 	}
 
-	return l.cloneWithHandler(l.ch.WithAttrs(keyValues))
+	keyValues = resolveLogValues(keyValues)
+	if !l.opts.uniqueAttrs {
+		return l.cloneWithHandler(l.ch.WithAttrs(keyValues))
+	}
+
+	keyValues, attrs := l.newConstAttrs(keyValues)
+	if len(keyValues) < 2 {
+		return l
+	}
+
+	nl := l.derive(l.ch.WithAttrs(keyValues), l.skip)
+	nl.constAttrs = attrs
+
+	return nl
+}
+
+// newConstAttrs returns keyValues without the pairs l already carries with
+// the same value, and l's constant attributes with the remaining ones added.
+func (l *logger) newConstAttrs(keyValues []any) ([]any, map[string]any) {
+	attrs := maps.Clone(l.constAttrs)
+	if attrs == nil {
+		attrs = make(map[string]any, len(keyValues)/2)
+	}
+
+	kept := make([]any, 0, len(keyValues))
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok {
+			kept = append(kept, keyValues[i], keyValues[i+1])
+			continue
+		}
+
+		qualified := l.groupPath + key
+		if old, ok := attrs[qualified]; ok && sameValue(old, keyValues[i+1]) {
+			continue
+		}
+		attrs[qualified] = keyValues[i+1]
+		kept = append(kept, key, keyValues[i+1])
+	}
+
+	return kept, attrs
+}
+
+// sameValue reports whether a and b are equal, treating values that cannot
+// be compared as different.
+func sameValue(a, b any) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+
+	return a == b
 }
 
 // WithGroup returns a new Logger that starts a key-value group.
@@ -233,7 +501,20 @@ func (l *logger) WithGroup(name string) Logger {
 		return l
 	}
 
-	return l.cloneWithHandler(l.ch.WithGroup(name))
+	nl := l.derive(l.ch.WithGroup(name), l.skip)
+	if l.opts.uniqueAttrs {
+		nl.groupPath = l.groupPath + name + "\x00" // Cannot clash with dots in keys
+	}
+
+	return nl
+}
+
+// WithGroupf returns a new Logger that starts a key-value group named by
+// formatting args with format. The name is handled exactly as by WithGroup,
+// including the handler's key prefix length limit; an empty name returns
+// the receiver.
+func (l *logger) WithGroupf(format string, args ...any) Logger {
+	return l.WithGroup(fmt.Sprintf(format, args...))
 }
 
 // Trace logs a message at the trace level.
@@ -267,11 +548,13 @@ func (l *logger) Critical(ctx context.Context, msg string, keyValues ...any) {
 }
 
 // Fatal logs a message at the fatal level and exits the process.
+// The exit function can be overridden with WithExitFunc.
 func (l *logger) Fatal(ctx context.Context, msg string, keyValues ...any) {
 	l.log(ctx, FatalLevel, msg, 0, keyValues...)
 }
 
 // Panic logs a message at the panic level and panics.
+// The panic behavior can be overridden with WithPanicFunc or WithPanicValue.
 func (l *logger) Panic(ctx context.Context, msg string, keyValues ...any) {
 	l.log(ctx, PanicLevel, msg, 0, keyValues...)
 }
@@ -301,6 +584,34 @@ func (l *logger) LogWithSkip(ctx context.Context, level LogLevel, msg string, sk
 	l.log(ctx, level, msg, skipDelta, keyValues...)
 }
 
+// LogRaw writes a pre-rendered payload through the handler's RawWriter,
+// or logs it as the message if the handler has none.
+func (l *logger) LogRaw(ctx context.Context, level LogLevel, payload []byte) {
+	if l.raw == nil {
+		l.log(ctx, level, strings.TrimSuffix(string(payload), "\n"), 0)
+		return
+	}
+
+	if _, ok := l.enabled(ctx, level); !ok {
+		return
+	}
+	if ctx != nil && ctx.Err() != nil {
+		return
+	}
+
+	if err := l.raw.WriteRaw(level, payload); err != nil {
+		getGlobalFallback().LogWithSkip(ctx, ErrorLevel, "log handler failed", 1,
+			"original_level", level.String(),
+			"raw_bytes", len(payload),
+			"handler_error", err.Error())
+	}
+
+	// Convert the payload only when it becomes the panic message
+	if level >= FatalLevel {
+		l.terminate(level, strings.TrimSuffix(string(payload), "\n"), nil)
+	}
+}
+
 // Sync flushes buffered log entries if the handler supports it.
 func (l *logger) Sync() error {
 	if l.snc != nil {
@@ -309,6 +620,14 @@ func (l *logger) Sync() error {
 	return nil
 }
 
+// Handler returns the underlying handler.
+func (l *logger) Handler() handler.Handler {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.h
+}
+
 // WithCallerSkip returns a new logger with absolute caller skip set.
 func (l *logger) WithCallerSkip(skip int) AdvancedLogger {
 	if skip < 0 {
@@ -327,11 +646,11 @@ func (l *logger) WithCallerSkip(skip int) AdvancedLogger {
 
 	// If handler supports caller adjustment, apply it
 	if adj != nil {
-		return newLogger(l.adj.WithCallerSkip(skip), skip)
+		return l.derive(l.adj.WithCallerSkip(skip), skip)
 	}
 
 	// Otherwise clone with new skip (PC capture will use it)
-	return newLogger(l.h, skip)
+	return l.derive(l.h, skip)
 }
 
 // WithCallerSkipDelta returns a new logger with relative caller skip adjustment.
@@ -343,6 +662,12 @@ func (l *logger) WithCallerSkipDelta(delta int) AdvancedLogger {
 	return l.WithCallerSkip(currentSkip - internalSkipFrames + delta)
 }
 
+// Skip returns a new logger with caller skip adjusted by n.
+// It is shorthand for WithCallerSkipDelta that returns a Logger.
+func (l *logger) Skip(n int) Logger {
+	return l.WithCallerSkipDelta(n)
+}
+
 // WithCaller returns a new logger with caller reporting enabled/disabled.
 func (l *logger) WithCaller(enabled bool) AdvancedLogger {
 	l.mu.RLock()
@@ -393,7 +718,58 @@ func (l *logger) WithOutput(w io.Writer) AdvancedLogger {
 
 // --- Helper Methods ---
 
+// maxCallerSearchDepth bounds the number of frames inspected when walking
+// past frames of packages configured with WithCallerSkipPackages.
+const maxCallerSearchDepth = 32
+
+// skipPackageFrames walks the frames of pcs past those whose function
+// belongs to one of the given packages or their subpackages. It returns the
+// PC of the first other frame, or 0 if there is none, and the number of
+// frames skipped. Frames are walked with runtime.CallersFrames, so inlined
+// functions count as frames of their own, as for runtime.Caller.
+func skipPackageFrames(pcs []uintptr, packages []string) (pc uintptr, skipped int) {
+	if len(pcs) == 0 {
+		return 0, 0
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !inPackages(handler.FuncPackage(frame.Function), packages) {
+			// Frame.PC is one less than the PC that runtime.CallersFrames
+			// resolves back to this frame, also for inlined frames
+			return frame.PC + 1, skipped
+		}
+		skipped++
+		if !more {
+			return 0, skipped
+		}
+	}
+}
+
+// inPackages reports whether pkg is one of packages or a subpackage of one.
+func inPackages(pkg string, packages []string) bool {
+	for _, p := range packages {
+		if pkg == p || strings.HasPrefix(pkg, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
 // cloneWithHandler creates a new logger with the given handler.
 func (l *logger) cloneWithHandler(h handler.Handler) Logger {
-	return newLogger(h, l.skip)
+	return l.derive(h, l.skip)
+}
+
+// derive creates a new logger with the given handler and skip,
+// inheriting the logger-level options.
+func (l *logger) derive(h handler.Handler, skip int) *logger {
+	nl := newLogger(h, skip)
+	nl.opts = l.opts
+	nl.constAttrs = l.constAttrs
+	nl.groupPath = l.groupPath
+
+	return nl
 }
@@ -7,6 +7,9 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -304,6 +307,19 @@ func TestLogger_Delegation(t *testing.T) {
 		}
 	})
 
+	t.Run("Skip", func(t *testing.T) {
+		// Skip is a delta, so successive calls add up
+		l2 := l.Skip(1).Skip(2)
+		wh := getMockHandler(t, l2)
+		expected := 3 + unilog.XInternalSkipFrames
+		if wh.LastOp() != "WithCallerSkip" || wh.LastVal() != expected {
+			t.Errorf("expected WithCallerSkip(%d), got %v(%v)", expected, wh.LastOp(), wh.LastVal())
+		}
+		if l.Skip(0) != unilog.Logger(l) {
+			t.Error("Skip(0) should return the original logger")
+		}
+	})
+
 	t.Run("WithCaller", func(t *testing.T) {
 		l2 := l.WithCaller(true)
 		wh := getMockHandler(t, l2)
@@ -451,6 +467,21 @@ func TestLogger_Sync(t *testing.T) {
 	}
 }
 
+func TestLogger_Handler(t *testing.T) {
+	t.Parallel()
+	h := newMockHandler()
+	l, _ := unilog.NewAdvancedLogger(h)
+
+	if got, want := l.Handler(), unilog.XLoggerHandler(l); got != want {
+		t.Errorf("Handler() = %p, want %p", got, want)
+	}
+
+	derived := l.With("k", "v").(unilog.AdvancedLogger)
+	if derived.Handler() == l.Handler() {
+		t.Error("derived logger should return its own chained handler")
+	}
+}
+
 func TestLogger_Fatal_Panic_Process(t *testing.T) {
 	// Uses sub-process execution to check os.Exit(1)
 	if os.Getenv("TEST_LOGGER_FATAL") == "1" {
@@ -637,6 +668,27 @@ func TestLogger_WithGroup_Optimization(t *testing.T) {
 	}
 }
 
+func TestLogger_WithGroupf(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewMemoryHandler(1024)
+	l, _ := unilog.NewLogger(h)
+
+	if l.WithGroupf("%s", "") != l {
+		t.Error("WithGroupf() with empty name should return same logger")
+	}
+
+	g := l.WithGroupf("shard-%d", 3)
+	if g == l {
+		t.Fatal("WithGroupf() should return new logger")
+	}
+
+	g.Info(context.Background(), "msg", "k", "v")
+	if got := string(h.Bytes()); !strings.Contains(got, "shard-3_k=v") {
+		t.Errorf("output = %q, want key shard-3_k", got)
+	}
+}
+
 func TestLogger_Timestamp(t *testing.T) {
 	t.Parallel()
 
@@ -777,6 +829,37 @@ func TestLogger_Fatal_Coverage(t *testing.T) {
 	}
 }
 
+func TestLogger_CallerPC(t *testing.T) {
+	t.Parallel()
+
+	h := newMockHandler()
+	h.state = &mockHandlerState{caller: true}
+	l, _ := unilog.NewAdvancedLogger(h)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		log  func() int // Logs and returns the line of the logging call
+	}{
+		{"Info", func() int { _, _, line, _ := runtime.Caller(0); l.Info(ctx, "msg"); return line }},
+		{"Log", func() int { _, _, line, _ := runtime.Caller(0); l.Log(ctx, unilog.InfoLevel, "msg"); return line }},
+		{"LogWithSkip", func() int {
+			_, _, line, _ := runtime.Caller(0)
+			l.LogWithSkip(ctx, unilog.InfoLevel, "msg", 0)
+			return line + 1
+		}},
+	}
+
+	// The captured PC is the logging call, not the logger method
+	for _, tt := range tests {
+		wantLine := tt.log()
+		frame, _ := runtime.CallersFrames([]uintptr{getMockHandler(t, l).LastRecord().PC}).Next()
+		if !strings.HasSuffix(frame.File, "logger_test.go") || frame.Line != wantLine {
+			t.Errorf("%s: caller = %s:%d (%s), want logger_test.go:%d", tt.name, frame.File, frame.Line, frame.Function, wantLine)
+		}
+	}
+}
+
 func TestLogger_CallerCapture_Exhaustion(t *testing.T) {
 	t.Parallel()
 
@@ -799,3 +882,807 @@ func TestLogger_CallerCapture_Exhaustion(t *testing.T) {
 		t.Errorf("expected PC=0 due to stack exhaustion, got %v", r.PC)
 	}
 }
+
+func TestLogger_WithExitFunc(t *testing.T) {
+	t.Parallel()
+
+	var codes []int
+	h := newMockHandler()
+	l, err := unilog.NewAdvancedLogger(h, unilog.WithExitFunc(func(code int) {
+		codes = append(codes, code)
+	}))
+	if err != nil {
+		t.Fatalf("NewAdvancedLogger() error = %v", err)
+	}
+
+	l.Fatal(context.Background(), "fatal message")
+
+	// Derived loggers inherit the exit function
+	l.With("k", "v").Fatal(context.Background(), "derived")
+	l.WithCallerSkip(1).Fatal(context.Background(), "skipped")
+
+	if len(codes) != 3 {
+		t.Fatalf("exit function called %d times, want 3", len(codes))
+	}
+	for _, code := range codes {
+		if code != 1 {
+			t.Errorf("exit code = %d, want 1", code)
+		}
+	}
+	if got := getMockHandler(t, l).LastRecord(); got.Message != "fatal message" {
+		t.Errorf("record message = %q, want %q (record must be logged before exit)", got.Message, "fatal message")
+	}
+}
+
+func TestLogger_WithPanicFunc(t *testing.T) {
+	t.Parallel()
+
+	var msgs []string
+	h := newMockHandler()
+	l, err := unilog.NewLogger(h, unilog.WithPanicFunc(func(msg string) {
+		msgs = append(msgs, msg)
+	}))
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	l.Panic(context.Background(), "boom")
+	l.WithGroup("g").Panic(context.Background(), "grouped")
+
+	if len(msgs) != 2 || msgs[0] != "boom" || msgs[1] != "grouped" {
+		t.Errorf("panic function messages = %v, want [boom grouped]", msgs)
+	}
+}
+
+func TestLogger_WithPanicValue(t *testing.T) {
+	t.Parallel()
+
+	type panicValue struct {
+		msg string
+		kv  []any
+	}
+
+	h := newMockHandler()
+	l, err := unilog.NewLogger(h, unilog.WithPanicValue(func(msg string, kv []any) any {
+		return panicValue{msg: msg, kv: slices.Clone(kv)}
+	}))
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	defer func() {
+		r := recover()
+		got, ok := r.(panicValue)
+		if !ok {
+			t.Fatalf("recovered %#v, want panicValue", r)
+		}
+		if got.msg != "boom" || !slices.Equal(got.kv, []any{"user", 42}) {
+			t.Errorf("panic value = %+v, want boom with [user 42]", got)
+		}
+	}()
+
+	l.Panic(context.Background(), "boom", "user", 42)
+}
+
+func TestLogger_TerminationOptions_Nil(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opt  unilog.LoggerOption
+	}{
+		{"WithExitFunc", unilog.WithExitFunc(nil)},
+		{"WithPanicFunc", unilog.WithPanicFunc(nil)},
+		{"WithPanicValue", unilog.WithPanicValue(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := unilog.NewLogger(newMockHandler(), tt.opt); err == nil {
+				t.Errorf("NewLogger(%s(nil)) error = nil, want error", tt.name)
+			}
+		})
+	}
+}
+
+func TestLogger_WithCallerSkipPackages(t *testing.T) {
+	t.Parallel()
+
+	// The test package acts as the facade: all its frames are skipped and the
+	// first frame outside it (the testing package) becomes the caller.
+	const testPkg = "github.com/balinomad/go-unilog_test"
+
+	callerOf := func(r *handler.Record) string {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		return frame.Function
+	}
+
+	t.Run("PC walks past packages", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		h.state = &mockHandlerState{caller: true}
+		l, err := unilog.NewLogger(h, unilog.WithCallerSkipPackages(testPkg))
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		l.Info(context.Background(), "msg")
+
+		if got := callerOf(getMockHandler(t, l).LastRecord()); !strings.HasPrefix(got, "testing.") {
+			t.Errorf("caller = %s, want a testing package frame", got)
+		}
+	})
+
+	t.Run("unrelated package keeps caller", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		h.state = &mockHandlerState{caller: true}
+		l, _ := unilog.NewLogger(h, unilog.WithCallerSkipPackages("example.com/other", testPkg+"x"))
+
+		l.Info(context.Background(), "msg")
+
+		if got := callerOf(getMockHandler(t, l).LastRecord()); !strings.Contains(got, "TestLogger_WithCallerSkipPackages") {
+			t.Errorf("caller = %s, want the test function", got)
+		}
+	})
+
+	t.Run("native caller skip is increased", func(t *testing.T) {
+		t.Parallel()
+		plain := newMockHandler()
+		plain.state = &mockHandlerState{caller: true}
+		plain.features = handler.NewHandlerFeatures(handler.FeatNativeCaller)
+		filtered := newMockHandler()
+		filtered.state = &mockHandlerState{caller: true}
+		filtered.features = handler.NewHandlerFeatures(handler.FeatNativeCaller)
+
+		l1, _ := unilog.NewLogger(plain)
+		l2, _ := unilog.NewLogger(filtered, unilog.WithCallerSkipPackages(testPkg))
+		l1.Info(context.Background(), "msg")
+		l2.Info(context.Background(), "msg")
+
+		base := getMockHandler(t, l1).LastRecord().Skip
+		got := getMockHandler(t, l2).LastRecord().Skip
+		if got <= base {
+			t.Errorf("Skip = %d, want greater than %d", got, base)
+		}
+	})
+
+	t.Run("inherited by derived loggers", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		h.state = &mockHandlerState{caller: true}
+		l, _ := unilog.NewLogger(h, unilog.WithCallerSkipPackages(testPkg))
+		child := l.With("k", "v")
+
+		child.Info(context.Background(), "msg")
+
+		if got := callerOf(getMockHandler(t, child).LastRecord()); !strings.HasPrefix(got, "testing.") {
+			t.Errorf("caller = %s, want a testing package frame", got)
+		}
+	})
+
+	t.Run("empty package", func(t *testing.T) {
+		t.Parallel()
+		if _, err := unilog.NewLogger(newMockHandler(), unilog.WithCallerSkipPackages("")); err == nil {
+			t.Error("NewLogger() error = nil, want error")
+		}
+	})
+}
+
+func TestLogger_MaxKeyValuesPerRecord(t *testing.T) {
+	t.Parallel()
+
+	limit := handler.MaxKeyValuesPerRecord()
+
+	t.Run("within limit", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		l, _ := unilog.NewLogger(h)
+		kv := make([]any, 0, 2*limit)
+		for i := range limit {
+			kv = append(kv, fmt.Sprintf("k%d", i), i)
+		}
+
+		l.Info(context.Background(), "msg", kv...)
+
+		if got := len(getMockHandler(t, l).LastRecord().KeyValues); got != 2*limit {
+			t.Errorf("len(KeyValues) = %d, want %d", got, 2*limit)
+		}
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		l, _ := unilog.NewLogger(h)
+		kv := make([]any, 0, 2*(limit+1))
+		for i := range limit + 1 {
+			kv = append(kv, fmt.Sprintf("k%d", i), i)
+		}
+
+		l.Info(context.Background(), "msg", kv...)
+
+		got := getMockHandler(t, l).LastRecord().KeyValues
+		if len(got) != 2*limit+4 {
+			t.Fatalf("len(KeyValues) = %d, want %d", len(got), 2*limit+4)
+		}
+		if got[2*limit-2] != fmt.Sprintf("k%d", limit-1) {
+			t.Errorf("last kept key = %v, want k%d", got[2*limit-2], limit-1)
+		}
+		tail := got[2*limit:]
+		if tail[0] != handler.TruncatedKey || tail[1] != true ||
+			tail[2] != handler.KeyValueCountKey || tail[3] != limit+1 {
+			t.Errorf("truncation fields = %v", tail)
+		}
+		if kv[2*limit] != fmt.Sprintf("k%d", limit) {
+			t.Error("caller's slice was modified")
+		}
+	})
+}
+
+func TestLogger_WithMaxAttrs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("negative limit", func(t *testing.T) {
+		t.Parallel()
+		if _, err := unilog.NewLogger(newMockHandler(), unilog.WithMaxAttrs(-1)); err == nil {
+			t.Error("NewLogger() error = nil, want error for negative limit")
+		}
+	})
+
+	t.Run("truncated and inherited", func(t *testing.T) {
+		t.Parallel()
+		l, err := unilog.NewLogger(newMockHandler(), unilog.WithMaxAttrs(2))
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		kv := []any{"a", 1, "b", 2, "c", 3, "d", 4}
+		l.Info(context.Background(), "msg", kv...)
+
+		want := []any{"a", 1, "b", 2, handler.TruncatedAttrsKey, 2}
+		if got := getMockHandler(t, l).LastRecord().KeyValues; !slices.Equal(got, want) {
+			t.Errorf("KeyValues = %v, want %v", got, want)
+		}
+		if kv[4] != "c" {
+			t.Error("caller's slice was modified")
+		}
+
+		derived := l.With("k", "v")
+		derived.Info(context.Background(), "msg", kv...)
+		if got := getMockHandler(t, derived).LastRecord().KeyValues; !slices.Equal(got, want) {
+			t.Errorf("derived KeyValues = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("within limit", func(t *testing.T) {
+		t.Parallel()
+		l, _ := unilog.NewLogger(newMockHandler(), unilog.WithMaxAttrs(2))
+
+		l.Info(context.Background(), "msg", "a", 1, "b", 2)
+
+		if got := len(getMockHandler(t, l).LastRecord().KeyValues); got != 4 {
+			t.Errorf("len(KeyValues) = %d, want 4", got)
+		}
+	})
+}
+
+func TestLogger_WithDeadlineField(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty key", func(t *testing.T) {
+		t.Parallel()
+		if _, err := unilog.NewLogger(newMockHandler(), unilog.WithDeadlineField("")); err == nil {
+			t.Error("NewLogger() error = nil, want error for empty key")
+		}
+	})
+
+	t.Run("added per call", func(t *testing.T) {
+		t.Parallel()
+		l, err := unilog.NewLogger(newMockHandler(), unilog.WithDeadlineField("deadline_ms"))
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		kv := make([]any, 2, 4)
+		kv[0], kv[1] = "a", 1
+		l.Info(ctx, "msg", kv...)
+
+		got := getMockHandler(t, l).LastRecord().KeyValues
+		if len(got) != 4 || got[2] != "deadline_ms" {
+			t.Fatalf("KeyValues = %v, want a deadline_ms field", got)
+		}
+		if ms, ok := got[3].(int64); !ok || ms <= 0 || ms > time.Minute.Milliseconds() {
+			t.Errorf("deadline_ms = %v, want within (0, 60000]", got[3])
+		}
+		if kv[:4][2] != nil {
+			t.Error("caller's slice was modified")
+		}
+
+		l.Info(context.Background(), "msg")
+		if got := getMockHandler(t, l).LastRecord().KeyValues; len(got) != 0 {
+			t.Errorf("KeyValues without deadline = %v, want none", got)
+		}
+	})
+}
+
+func TestLogger_WithRecordModifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil modifier", func(t *testing.T) {
+		t.Parallel()
+		if _, err := unilog.NewLogger(newMockHandler(), unilog.WithRecordModifier(nil)); err == nil {
+			t.Error("NewLogger() error = nil, want error for nil modifier")
+		}
+	})
+
+	t.Run("applied in order and inherited", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		l, err := unilog.NewLogger(h,
+			unilog.WithRecordModifier(func(r *handler.Record) {
+				r.Message = "[prod] " + r.Message
+			}),
+			unilog.WithRecordModifier(func(r *handler.Record) {
+				kv := make([]any, len(r.KeyValues))
+				for i, v := range r.KeyValues {
+					if s, ok := v.(string); ok && i%2 == 0 {
+						v = strings.ToLower(s)
+					}
+					kv[i] = v
+				}
+				r.KeyValues = kv
+				r.Message += "!"
+			}),
+		)
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		kv := []any{"UserID", 42}
+		l.Info(context.Background(), "login", kv...)
+
+		got := getMockHandler(t, l).LastRecord()
+		if got.Message != "[prod] login!" {
+			t.Errorf("Message = %q, want %q", got.Message, "[prod] login!")
+		}
+		if len(got.KeyValues) != 2 || got.KeyValues[0] != "userid" {
+			t.Errorf("KeyValues = %v, want lowercased keys", got.KeyValues)
+		}
+		if kv[0] != "UserID" {
+			t.Error("caller's slice was modified")
+		}
+
+		derived := l.With("k", "v")
+		derived.Warn(context.Background(), "derived")
+		if got := getMockHandler(t, derived).LastRecord().Message; got != "[prod] derived!" {
+			t.Errorf("derived Message = %q, want %q", got, "[prod] derived!")
+		}
+	})
+}
+
+func TestLogger_Level(t *testing.T) {
+	t.Parallel()
+
+	t.Run("from handler state", func(t *testing.T) {
+		t.Parallel()
+		base, err := handler.NewBaseHandlerFromOptions(nil,
+			handler.WithOutput(io.Discard),
+			handler.WithLevel(handler.WarnLevel),
+		)
+		if err != nil {
+			t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+		}
+		h := newMockHandler()
+		h.state = base
+		l, _ := unilog.NewLogger(h)
+
+		if got := l.Level(); got != unilog.WarnLevel {
+			t.Errorf("Level() = %v, want %v", got, unilog.WarnLevel)
+		}
+		_ = base.SetLevel(handler.DebugLevel)
+		if got := l.Level(); got != unilog.DebugLevel {
+			t.Errorf("Level() after SetLevel = %v, want %v", got, unilog.DebugLevel)
+		}
+	})
+
+	t.Run("probed from Enabled", func(t *testing.T) {
+		t.Parallel()
+		h, _ := handler.NewMemoryHandler(1024)
+		_ = h.SetLevel(handler.ErrorLevel)
+		l, _ := unilog.NewLogger(h)
+
+		if got := l.Level(); got != unilog.ErrorLevel {
+			t.Errorf("Level() = %v, want %v", got, unilog.ErrorLevel)
+		}
+	})
+
+	t.Run("nothing enabled", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		h.enabled = false
+		l, _ := unilog.NewLogger(h)
+
+		if got := l.Level(); got != unilog.PanicLevel {
+			t.Errorf("Level() = %v, want %v", got, unilog.PanicLevel)
+		}
+	})
+}
+
+// discardHandler is an enabled handler that drops every record without
+// retaining it, so benchmarks measure the logger alone.
+type discardHandler struct{ state mockHandlerState }
+
+func (h *discardHandler) Handle(context.Context, *handler.Record) error { return nil }
+func (h *discardHandler) Enabled(unilog.LogLevel) bool                  { return true }
+func (h *discardHandler) HandlerState() handler.HandlerState            { return &h.state }
+func (h *discardHandler) Features() handler.HandlerFeatures             { return handler.HandlerFeatures{} }
+
+func TestLogger_NoAttributes(t *testing.T) {
+	t.Run("key values are nil", func(t *testing.T) {
+		t.Parallel()
+		l, _ := unilog.NewLogger(newMockHandler())
+
+		l.Info(context.Background(), "msg")
+		r := getMockHandler(t, l).LastRecord()
+		if r == nil {
+			t.Fatal("no record handled")
+		}
+		if r.KeyValues != nil {
+			t.Errorf("KeyValues = %#v, want nil", r.KeyValues)
+		}
+	})
+
+	// AllocsPerRun must not run in parallel with other tests
+	t.Run("zero allocations", func(t *testing.T) {
+		l, _ := unilog.NewLogger(&discardHandler{})
+		ctx := context.Background()
+
+		if allocs := testing.AllocsPerRun(100, func() { l.Info(ctx, "msg") }); allocs != 0 {
+			t.Errorf("Info() without attributes allocated %v times, want 0", allocs)
+		}
+	})
+}
+
+func BenchmarkLogger_NoAttributes(b *testing.B) {
+	l, _ := unilog.NewLogger(&discardHandler{})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Info(ctx, "msg")
+	}
+}
+
+func BenchmarkLogger_WithAttributes(b *testing.B) {
+	l, _ := unilog.NewLogger(&discardHandler{})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Info(ctx, "msg", "key", "value", "n", 42)
+	}
+}
+
+// blockingHandler blocks in Handle until release is closed, then reports
+// the message of the record it was given.
+type blockingHandler struct {
+	discardHandler
+	release chan struct{}
+	seen    chan string
+}
+
+func (h *blockingHandler) Handle(_ context.Context, r *handler.Record) error {
+	<-h.release
+	h.seen <- r.Message
+	return nil
+}
+
+func TestLogger_WithHandleTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		t.Parallel()
+		for _, d := range []time.Duration{0, -time.Second} {
+			if _, err := unilog.NewLogger(newMockHandler(), unilog.WithHandleTimeout(d)); err == nil {
+				t.Errorf("WithHandleTimeout(%v) error = nil, want error", d)
+			}
+		}
+	})
+
+	t.Run("native caller handler", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		h.features = handler.NewHandlerFeatures(handler.FeatNativeCaller)
+		if _, err := unilog.NewLogger(h, unilog.WithHandleTimeout(time.Second)); err == nil {
+			t.Error("NewLogger() error = nil, want error for a native caller handler")
+		}
+	})
+
+	t.Run("fast handler", func(t *testing.T) {
+		t.Parallel()
+		l, err := unilog.NewLogger(newMockHandler(), unilog.WithHandleTimeout(time.Second))
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		l.Info(context.Background(), "msg", "k", "v")
+		r := getMockHandler(t, l).LastRecord()
+		if r == nil || r.Message != "msg" {
+			t.Fatalf("LastRecord() = %+v, want message %q", r, "msg")
+		}
+	})
+
+	t.Run("slow handler is abandoned", func(t *testing.T) {
+		t.Parallel()
+		h := &blockingHandler{release: make(chan struct{}), seen: make(chan string, 2)}
+		l, _ := unilog.NewLogger(h, unilog.WithHandleTimeout(10*time.Millisecond))
+
+		returned := make(chan struct{})
+		go func() {
+			l.Info(context.Background(), "slow")
+			close(returned)
+		}()
+
+		select {
+		case <-returned:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Info() did not return after the handle timeout")
+		}
+
+		// Abandoned records are not recycled: each call completes later
+		// with its own record intact
+		l.Info(context.Background(), "next")
+		close(h.release)
+		got := []string{<-h.seen, <-h.seen}
+		slices.Sort(got)
+		if want := []string{"next", "slow"}; !slices.Equal(got, want) {
+			t.Errorf("abandoned handlers saw messages %v, want %v", got, want)
+		}
+	})
+}
+
+func TestNewMultiBackendLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		if _, err := unilog.NewMultiBackendLogger(); err == nil {
+			t.Error("NewMultiBackendLogger() error = nil, want error")
+		}
+		if _, err := unilog.NewMultiBackendLogger(newMockHandler(), nil); err == nil {
+			t.Error("NewMultiBackendLogger(nil) error = nil, want error")
+		}
+	})
+
+	t.Run("single handler is used directly", func(t *testing.T) {
+		t.Parallel()
+		l, err := unilog.NewMultiBackendLogger(newMockHandler())
+		if err != nil {
+			t.Fatalf("NewMultiBackendLogger() error = %v", err)
+		}
+		l.Info(context.Background(), "msg")
+		if getMockHandler(t, l).CallCount() != 1 {
+			t.Error("handler was not called")
+		}
+	})
+
+	t.Run("records reach every handler", func(t *testing.T) {
+		t.Parallel()
+		first, _ := handler.NewMemoryHandler(1024)
+		second, _ := handler.NewMemoryHandler(1024)
+		l, err := unilog.NewMultiBackendLogger(first, second)
+		if err != nil {
+			t.Fatalf("NewMultiBackendLogger() error = %v", err)
+		}
+
+		l.With("k", "v").Info(context.Background(), "msg")
+		for i, h := range []*handler.MemoryHandler{first, second} {
+			if got := h.Lines(); len(got) != 1 || !strings.HasSuffix(got[0], "INFO msg k=v") {
+				t.Errorf("handler %d lines = %v, want the record", i, got)
+			}
+		}
+	})
+}
+
+func TestLogger_CallerLevel(t *testing.T) {
+	t.Parallel()
+
+	base, err := handler.NewBaseHandlerFromOptions(nil,
+		handler.WithOutput(io.Discard),
+		handler.WithCaller(true),
+		handler.WithCallerLevel(handler.WarnLevel),
+	)
+	if err != nil {
+		t.Fatalf("NewBaseHandlerFromOptions() error = %v", err)
+	}
+	h := newMockHandler()
+	h.state = base
+	l, _ := unilog.NewLogger(h)
+
+	l.Info(context.Background(), "routine")
+	if pc := getMockHandler(t, l).LastRecord().PC; pc != 0 {
+		t.Errorf("INFO record PC = %d, want 0 below the caller level", pc)
+	}
+	l.Warn(context.Background(), "unusual")
+	if pc := getMockHandler(t, l).LastRecord().PC; pc == 0 {
+		t.Error("WARN record PC = 0, want caller at the caller level")
+	}
+}
+
+func TestValidateKeyValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		kv      []any
+		wantErr string
+	}{
+		{"valid", []any{"a", 1, "b", 2}, ""},
+		{"empty", nil, ""},
+		{"non-string key", []any{"a", 1, 42, 2}, "key at position 2 is int"},
+		{"dangling key", []any{"a", 1, "b"}, `key "b" has no value`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := unilog.XValidateKeyValues(tt.kv)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("validateKeyValues() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("validateKeyValues() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogger_WithStrictMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports and logs", func(t *testing.T) {
+		t.Parallel()
+		l, _ := unilog.NewLogger(newMockHandler(), unilog.WithStrictMode(true))
+
+		l.Info(context.Background(), "msg", 42, "value")
+		if got := getMockHandler(t, l).CallCount(); got != 1 {
+			t.Errorf("CallCount() = %d, want the record logged anyway", got)
+		}
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		t.Parallel()
+		l, _ := unilog.NewLogger(newMockHandler(), unilog.WithStrictMode(true), unilog.WithStrictPanic(true))
+
+		defer func() {
+			if recover() == nil {
+				t.Error("Info() with a dangling key did not panic")
+			}
+			if got := getMockHandler(t, l).CallCount(); got != 0 {
+				t.Errorf("CallCount() = %d, want 0 after the panic", got)
+			}
+		}()
+		l.Info(context.Background(), "msg", "key")
+	})
+
+	t.Run("valid arguments", func(t *testing.T) {
+		t.Parallel()
+		l, _ := unilog.NewLogger(newMockHandler(), unilog.WithStrictMode(true), unilog.WithStrictPanic(true))
+
+		l.Info(context.Background(), "msg", "key", "value")
+		if got := getMockHandler(t, l).CallCount(); got != 1 {
+			t.Errorf("CallCount() = %d, want 1", got)
+		}
+	})
+}
+
+func TestLogger_WithLevelFromContext(t *testing.T) {
+	t.Parallel()
+
+	type debugKey struct{}
+	fromCtx := func(ctx context.Context) (unilog.LogLevel, bool) {
+		if ctx.Value(debugKey{}) != nil {
+			return unilog.DebugLevel, true
+		}
+		return 0, false
+	}
+
+	if _, err := unilog.NewLogger(newMockHandler(), unilog.WithLevelFromContext(nil)); err == nil {
+		t.Error("WithLevelFromContext(nil) error = nil, want error")
+	}
+
+	// The backends' filtering is tested with the backends; here only that
+	// the level reaches the handler through the context
+	h := newMockHandler()
+	h.enabled = false
+	l, _ := unilog.NewLogger(h, unilog.WithLevelFromContext(fromCtx))
+
+	var nilCtx context.Context
+	l.Debug(nilCtx, "dropped, no context")
+	l.Debug(context.WithValue(context.Background(), debugKey{}, true), "logged for this request")
+
+	mh := getMockHandler(t, l)
+	if got := mh.CallCount(); got != 1 {
+		t.Fatalf("CallCount() = %d, want 1", got)
+	}
+	if lvl, ok := handler.ContextLevel(mh.LastContext()); !ok || lvl != unilog.DebugLevel {
+		t.Errorf("ContextLevel() = %v, %t; want %v, true", lvl, ok, unilog.DebugLevel)
+	}
+}
+
+func TestLogger_WithUniqueConstantAttrs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		unique bool
+		derive func(l unilog.Logger) unilog.Logger
+		want   int // Occurrences of "service"
+	}{
+		{"identical re-add", true, func(l unilog.Logger) unilog.Logger {
+			return l.With("service", "api").With("region", "eu").With("service", "api")
+		}, 1},
+		{"identical re-add, option off", false, func(l unilog.Logger) unilog.Logger {
+			return l.With("service", "api").With("service", "api")
+		}, 2},
+		{"new value", true, func(l unilog.Logger) unilog.Logger {
+			return l.With("service", "api").With("service", "web")
+		}, 2},
+		{"other group", true, func(l unilog.Logger) unilog.Logger {
+			return l.With("service", "api").WithGroup("peer").With("service", "api")
+		}, 2},
+		{"through other derivations", true, func(l unilog.Logger) unilog.Logger {
+			return l.With("service", "api").Skip(1).With("service", "api")
+		}, 1},
+		{"uncomparable value", true, func(l unilog.Logger) unilog.Logger {
+			return l.With("service", []string{"api"}).With("service", []string{"api"})
+		}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, _ := handler.NewMemoryHandler(1024)
+			l, _ := unilog.NewLogger(h, unilog.WithUniqueConstantAttrs(tt.unique))
+
+			tt.derive(l).Info(context.Background(), "msg")
+
+			if got := strings.Count(string(h.Bytes()), "service"); got != tt.want {
+				t.Errorf("output %q has %d service fields, want %d", h.Bytes(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_LogRaw(t *testing.T) {
+	t.Parallel()
+
+	t.Run("raw writer", func(t *testing.T) {
+		t.Parallel()
+		var buf strings.Builder
+		l := newBaseLogger(t, handler.WithOutput(&buf), handler.WithLevel(unilog.InfoLevel)).(unilog.AdvancedLogger)
+
+		l.LogRaw(context.Background(), unilog.InfoLevel, []byte("GET /users 200\n"))
+		l.LogRaw(context.Background(), unilog.DebugLevel, []byte("GET /health 200\n"))
+
+		if got, want := buf.String(), "GET /users 200\n"; got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("fallback to message", func(t *testing.T) {
+		t.Parallel()
+		l, _ := unilog.NewAdvancedLogger(newMockHandler())
+
+		l.LogRaw(context.Background(), unilog.WarnLevel, []byte("GET /users 200\n"))
+
+		r := getMockHandler(t, l).LastRecord()
+		if r == nil || r.Message != "GET /users 200" || r.Level != unilog.WarnLevel {
+			t.Errorf("record = %+v, want message %q at %v", r, "GET /users 200", unilog.WarnLevel)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package unilog
+
+import "fmt"
+
+// LogValuer is implemented by types that control their own logged
+// representation, like [log/slog.LogValuer]. A User type can log as its ID
+// and name instead of every field:
+//
+//	func (u User) LogValue() any {
+//		return map[string]any{"id": u.ID, "name": u.Name}
+//	}
+//
+// Attribute values implementing LogValuer are replaced by the result of
+// LogValue before the record reaches the handler, so every backend sees the
+// same form. A result that is itself a LogValuer is resolved in turn.
+type LogValuer interface {
+	LogValue() any
+}
+
+// maxLogValueDepth bounds the LogValue calls made to resolve a single value,
+// breaking cycles such as a LogValue returning its receiver.
+const maxLogValueDepth = 100
+
+// resolveLogValues returns keyValues with every LogValuer value resolved.
+// It returns keyValues itself if no value is a LogValuer, or a copy otherwise,
+// leaving the caller's slice untouched.
+func resolveLogValues(keyValues []any) []any {
+	var out []any
+	for i := 1; i < len(keyValues); i += 2 {
+		v, ok := keyValues[i].(LogValuer)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]any, len(keyValues))
+			copy(out, keyValues)
+		}
+		out[i] = resolveLogValue(v)
+	}
+	if out == nil {
+		return keyValues
+	}
+
+	return out
+}
+
+// resolveLogValue calls LogValue until the result is not a LogValuer.
+// A value still unresolved after maxLogValueDepth calls, or whose LogValue
+// panics, is replaced by a string describing the problem.
+func resolveLogValue(v LogValuer) (resolved any) {
+	defer func() {
+		if r := recover(); r != nil {
+			resolved = fmt.Sprintf("!PANIC in LogValue of %T: %v", v, r)
+		}
+	}()
+
+	for range maxLogValueDepth {
+		next := v.LogValue()
+		lv, ok := next.(LogValuer)
+		if !ok {
+			return next
+		}
+		v = lv
+	}
+
+	return fmt.Sprintf("!LogValue of %T not resolved after %d calls", v, maxLogValueDepth)
+}
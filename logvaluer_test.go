@@ -0,0 +1,96 @@
+package unilog_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/balinomad/go-unilog"
+	"github.com/balinomad/go-unilog/handler"
+)
+
+type user struct {
+	ID       int
+	Name     string
+	Password string
+}
+
+func (u user) LogValue() any { return map[string]any{"id": u.ID, "name": u.Name} }
+
+// wrapped resolves to the user it wraps.
+type wrapped struct{ u user }
+
+func (w wrapped) LogValue() any { return w.u }
+
+// cyclic resolves to itself.
+type cyclic struct{}
+
+func (c cyclic) LogValue() any { return c }
+
+// panicky panics when resolved.
+type panicky struct{}
+
+func (panicky) LogValue() any { panic("boom") }
+
+func TestLogger_LogValuer(t *testing.T) {
+	t.Parallel()
+
+	u := user{ID: 7, Name: "jane", Password: "secret"}
+
+	tests := []struct {
+		name  string
+		value any
+		check func(any) bool
+	}{
+		{"resolved", u, func(v any) bool {
+			m, ok := v.(map[string]any)
+			return ok && m["id"] == 7 && m["name"] == "jane" && len(m) == 2
+		}},
+		{"nested", wrapped{u}, func(v any) bool {
+			_, ok := v.(map[string]any)
+			return ok
+		}},
+		{"cycle", cyclic{}, func(v any) bool {
+			s, ok := v.(string)
+			return ok && strings.Contains(s, "not resolved")
+		}},
+		{"panic", panicky{}, func(v any) bool {
+			s, ok := v.(string)
+			return ok && strings.Contains(s, "boom")
+		}},
+		{"plain value", 42, func(v any) bool { return v == 42 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l, _ := unilog.NewLogger(newMockHandler())
+
+			kv := []any{"v", tt.value}
+			l.Info(context.Background(), "msg", kv...)
+
+			got := getMockHandler(t, l).LastRecord().KeyValues
+			if len(got) != 2 || !tt.check(got[1]) {
+				t.Errorf("KeyValues = %v", got)
+			}
+			if kv[1] != tt.value {
+				t.Error("caller's slice was modified")
+			}
+		})
+	}
+}
+
+func TestLogger_LogValuer_With(t *testing.T) {
+	t.Parallel()
+
+	h, _ := handler.NewMemoryHandler(1024)
+	l, _ := unilog.NewLogger(h)
+	l.With("user", user{ID: 7, Name: "jane", Password: "secret"}).Info(context.Background(), "msg")
+
+	out := string(h.Bytes())
+	if strings.Contains(out, "secret") || !strings.Contains(out, "jane") {
+		t.Errorf("output = %q, want the resolved user", out)
+	}
+}
+
+var _ unilog.LogValuer = user{}
@@ -27,6 +27,7 @@ type mockFullHandler struct {
 	// State verification
 	callCount  int
 	lastRecord *handler.Record
+	lastCtx    context.Context
 	lastOp     string
 	lastVal    any
 	history    []string // Trace of operations
@@ -94,11 +95,12 @@ func (h *mockFullHandler) recordOp(op string, val any) {
 }
 
 // Handle captures the record by value to ensure safety against sync.Pool recycling.
-func (h *mockFullHandler) Handle(_ context.Context, r *handler.Record) error {
+func (h *mockFullHandler) Handle(ctx context.Context, r *handler.Record) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	h.callCount++
+	h.lastCtx = ctx
 
 	// DEEP COPY: Create a new Record instance and copy fields.
 	// Since r is pooled, we cannot keep the pointer 'r'.
@@ -149,6 +151,14 @@ func (h *mockFullHandler) LastRecord() *handler.Record {
 	return h.lastRecord
 }
 
+// LastContext is a helper to safely get the context of the last record.
+func (h *mockFullHandler) LastContext() context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.lastCtx
+}
+
 // LastOp is a helper to safely get last operation.
 func (h *mockFullHandler) LastOp() string {
 	h.mu.Lock()
@@ -347,6 +357,16 @@ func (l *mockLogger) Enabled(level unilog.LogLevel) bool {
 	return true
 }
 
+// Level returns TraceLevel as all levels are enabled.
+func (l *mockLogger) Level() unilog.LogLevel {
+	return unilog.TraceLevel
+}
+
+// Skip returns the logger unchanged.
+func (l *mockLogger) Skip(n int) unilog.Logger {
+	return l
+}
+
 // With returns the logger unchanged.
 func (l *mockLogger) With(keyValues ...any) unilog.Logger {
 	return l
@@ -357,6 +377,11 @@ func (l *mockLogger) WithGroup(name string) unilog.Logger {
 	return l
 }
 
+// WithGroupf returns the logger unchanged.
+func (l *mockLogger) WithGroupf(format string, args ...any) unilog.Logger {
+	return l
+}
+
 // Trace is a convenience method for logging at the trace level.
 func (l *mockLogger) Trace(ctx context.Context, msg string, keyValues ...any) {
 	l.Log(ctx, unilog.TraceLevel, msg, keyValues...)
@@ -446,6 +471,11 @@ func (l *mockAdvancedLogger) LogWithSkip(ctx context.Context, level unilog.LogLe
 	l.buf.WriteString(level.String() + ": " + msg + " [skip:" + string(rune(skip+'0')) + "]")
 }
 
+// LogRaw logs the payload as the message.
+func (l *mockAdvancedLogger) LogRaw(ctx context.Context, level unilog.LogLevel, payload []byte) {
+	l.Log(ctx, level, string(payload))
+}
+
 // CallerSkip returns the number of stack frames to skip.
 func (l *mockAdvancedLogger) CallerSkip() int {
 	return l.callerSkip
@@ -493,6 +523,11 @@ func (l *mockAdvancedLogger) Sync() error {
 	return nil
 }
 
+// Handler returns nil; mockAdvancedLogger writes to its buffer directly.
+func (l *mockAdvancedLogger) Handler() handler.Handler {
+	return nil
+}
+
 // resetDefault resets the global state for tests.
 // TODO: This must be fixed.
 func resetDefault() {
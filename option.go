@@ -0,0 +1,245 @@
+package unilog
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// LoggerOption configures a logger created by NewLogger or NewAdvancedLogger.
+type LoggerOption func(*loggerOptions) error
+
+// loggerOptions holds logger-level configuration that is independent of the handler.
+// Derived loggers (With, WithGroup, WithCallerSkip, ...) inherit these options.
+type loggerOptions struct {
+	exitFunc  func(code int)   // Called after logging at FatalLevel; nil uses os.Exit
+	panicFunc func(msg string) // Called after logging at PanicLevel; nil uses panic
+
+	// panicValue builds the value Panic panics with; nil panics with the message.
+	panicValue func(msg string, keyValues []any) any
+
+	// skipPackages lists package paths whose frames are skipped during caller resolution.
+	skipPackages []string
+
+	// modifiers transform every record, in order, before it is handled.
+	modifiers []func(*handler.Record)
+
+	// handleTimeout bounds each Handle call; zero means no bound.
+	handleTimeout time.Duration
+
+	// maxAttrs caps the key-value pairs of each record; zero means no cap.
+	maxAttrs int
+
+	// deadlineKey is the key of the remaining context time; empty disables it.
+	deadlineKey string
+
+	// strict validates the key-value pairs of every call; strictPanic makes
+	// a violation panic instead of being reported to the fallback logger.
+	strict      bool
+	strictPanic bool
+
+	// levelFromCtx overrides the handler's level check when it returns ok.
+	levelFromCtx func(ctx context.Context) (LogLevel, bool)
+
+	// uniqueAttrs drops attributes With would add again with the same value.
+	uniqueAttrs bool
+}
+
+// WithExitFunc sets the function Fatal calls with exit code 1 after the record
+// has been logged. It allows running shutdown hooks before exiting and makes
+// Fatal testable in-process. If fn returns, Fatal returns too.
+// The default is os.Exit.
+func WithExitFunc(fn func(code int)) LoggerOption {
+	return func(o *loggerOptions) error {
+		if fn == nil {
+			return errors.New("exit function cannot be nil")
+		}
+		o.exitFunc = fn
+		return nil
+	}
+}
+
+// WithPanicFunc sets the function Panic calls with the message after the record
+// has been logged. If fn returns, Panic returns too.
+// The default panics with the message.
+func WithPanicFunc(fn func(msg string)) LoggerOption {
+	return func(o *loggerOptions) error {
+		if fn == nil {
+			return errors.New("panic function cannot be nil")
+		}
+		o.panicFunc = fn
+		return nil
+	}
+}
+
+// WithPanicValue sets the function that builds the value Panic panics with
+// from the message and the record's key-value pairs, so that a recover in
+// panic-recovery middleware gets structured data rather than a string:
+//
+//	unilog.WithPanicValue(func(msg string, kv []any) any {
+//	    return &LogPanic{Msg: msg, Fields: slices.Clone(kv)}
+//	})
+//
+// fn must not modify kv, which may belong to the caller; copy it to keep it.
+// It is ignored if WithPanicFunc is set. The default panics with the message.
+func WithPanicValue(fn func(msg string, kv []any) any) LoggerOption {
+	return func(o *loggerOptions) error {
+		if fn == nil {
+			return errors.New("panic value function cannot be nil")
+		}
+		o.panicValue = fn
+		return nil
+	}
+}
+
+// WithCallerSkipPackages makes caller resolution walk past any frame whose
+// function belongs to one of the given packages or their subpackages
+// (e.g., "myapp/logging" also matches "myapp/logging/internal").
+// It reports the real call site when unilog is wrapped behind an application
+// facade whose call depth varies, where a fixed skip count is not enough.
+// It only has an effect when caller reporting is enabled on the handler.
+func WithCallerSkipPackages(packages ...string) LoggerOption {
+	return func(o *loggerOptions) error {
+		for _, p := range packages {
+			if p == "" {
+				return errors.New("caller skip package cannot be empty")
+			}
+		}
+		o.skipPackages = append(slices.Clip(o.skipPackages), packages...)
+		return nil
+	}
+}
+
+// WithRecordModifier adds fn to the list of functions applied to every record
+// after normalization (odd key-value trimming, the per-record cap and caller
+// capture) and right before it is passed to the handler. Modifiers run in the
+// order they were added and cannot drop a record, which makes them suited to
+// simple, infallible transforms such as renaming keys or prefixing messages.
+//
+// The record is pooled: fn must not retain it, and it must copy KeyValues
+// before changing them in place because the slice may belong to the caller.
+// Changing the level does not affect Fatal and Panic termination.
+func WithRecordModifier(fn func(r *handler.Record)) LoggerOption {
+	return func(o *loggerOptions) error {
+		if fn == nil {
+			return errors.New("record modifier cannot be nil")
+		}
+		o.modifiers = append(slices.Clip(o.modifiers), fn)
+		return nil
+	}
+}
+
+// WithMaxAttrs keeps only the first n key-value pairs of each record and
+// appends a handler.TruncatedAttrsKey field holding the number of pairs
+// dropped. It guards against call sites passing untrusted or generated
+// attribute lists. Attributes added with With are not counted.
+// Unlike handler.SetMaxKeyValuesPerRecord, the limit applies to this logger
+// and the loggers derived from it only. Zero means no limit, the default.
+// Returns error if n is negative.
+func WithMaxAttrs(n int) LoggerOption {
+	return func(o *loggerOptions) error {
+		if n < 0 {
+			return errors.New("max attrs cannot be negative")
+		}
+		o.maxAttrs = n
+		return nil
+	}
+}
+
+// WithDeadlineField adds the time left before the deadline of the context
+// passed to each log call, in whole milliseconds, under key to every record,
+// e.g. "deadline_ms", 420. Records logged with a context that has no
+// deadline are left unchanged. It helps tell which step of a slow request
+// used up its time budget. Returns error if key is empty.
+func WithDeadlineField(key string) LoggerOption {
+	return func(o *loggerOptions) error {
+		if key == "" {
+			return errors.New("deadline field key cannot be empty")
+		}
+		o.deadlineKey = key
+		return nil
+	}
+}
+
+// WithStrictMode validates the key-value arguments of every log call, to
+// catch programming errors during development: a key that is not a string
+// or a key without a value is reported to the fallback logger, pointing at
+// the offending call site. The record itself is still logged, with keys
+// coerced and the dangling key dropped as without strict mode.
+// Use WithStrictPanic to fail loudly instead. The default is false.
+func WithStrictMode(enabled bool) LoggerOption {
+	return func(o *loggerOptions) error {
+		o.strict = enabled
+		return nil
+	}
+}
+
+// WithStrictPanic makes strict mode panic on the first invalid key-value
+// argument instead of reporting it, before the record is logged.
+// It has no effect unless WithStrictMode is enabled. The default is false.
+func WithStrictPanic(enabled bool) LoggerOption {
+	return func(o *loggerOptions) error {
+		o.strictPanic = enabled
+		return nil
+	}
+}
+
+// WithLevelFromContext sets fn to pick the minimum level of a single log
+// call from its context, e.g. from a debug header stored by a middleware.
+// When fn returns ok, records below the returned level are dropped and the
+// others passed to the handler, instead of consulting the handler's Enabled.
+// When it returns false, or the context is nil, the handler decides as usual.
+// fn runs on every log call, so it must be cheap.
+//
+// The level is passed on in the context given to Handle, as set with
+// handler.WithContextLevel, and the backends filter by it instead of their
+// own level, so it may be lower than the handler's. Raw payloads written
+// with LogRaw are still filtered at the handler's level.
+// Returns error if fn is nil.
+func WithLevelFromContext(fn func(ctx context.Context) (LogLevel, bool)) LoggerOption {
+	return func(o *loggerOptions) error {
+		if fn == nil {
+			return errors.New("level function cannot be nil")
+		}
+		o.levelFromCtx = fn
+		return nil
+	}
+}
+
+// WithUniqueConstantAttrs makes With skip a key-value pair that the logger
+// already carries with the same value in the same group, e.g. service=api
+// added again by a second middleware, so that backends that keep every
+// pair do not log it twice. A pair with a new value for the key is added as
+// before, and so are values that cannot be compared, such as slices.
+// Records' own key-value pairs are not affected. The default is false.
+func WithUniqueConstantAttrs(enabled bool) LoggerOption {
+	return func(o *loggerOptions) error {
+		o.uniqueAttrs = enabled
+		return nil
+	}
+}
+
+// WithHandleTimeout bounds every call to the handler's Handle method by d,
+// protecting callers from a sink that hangs (e.g. a file on a stalled network
+// mount). A call that does not complete in time is abandoned and reported to
+// the fallback logger with a "handle_timeout" attribute.
+//
+// Each call then runs in its own goroutine, which costs an allocation and a
+// goroutine switch per record. An abandoned call keeps running in the
+// background and may still write the record later; it keeps its goroutine
+// alive until the handler returns. Handlers that resolve the caller natively
+// from a skip count (handler.FeatNativeCaller) would report a frame of that
+// goroutine instead of the call site, so the logger constructors reject the
+// option for them. Returns error if d is not positive.
+func WithHandleTimeout(d time.Duration) LoggerOption {
+	return func(o *loggerOptions) error {
+		if d <= 0 {
+			return errors.New("handle timeout must be positive")
+		}
+		o.handleTimeout = d
+		return nil
+	}
+}
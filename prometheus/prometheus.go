@@ -0,0 +1,83 @@
+// Package prometheus provides a handler.MetricsProvider backed by
+// Prometheus counters. It lives in its own module so that the core
+// handler package stays free of the Prometheus dependency.
+package prometheus
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// metricsProvider counts handled records per level and handling errors.
+type metricsProvider struct {
+	handled *prometheus.CounterVec
+	errors  prometheus.Counter
+}
+
+// Ensure metricsProvider implements handler.MetricsProvider
+var _ handler.MetricsProvider = (*metricsProvider)(nil)
+
+// NewMetricsProvider creates the counters for a handler and registers them with reg.
+// The name is used as the metric namespace and must be a valid Prometheus
+// metric name prefix. The following metrics are exposed:
+//
+//   - <name>_records_handled_total{level="..."}: records written per level
+//   - <name>_errors_total: records the backend failed to handle
+//
+// If reg is nil, prometheus.DefaultRegisterer is used. If the counters are
+// already registered under the same name (e.g., several handlers sharing a
+// name), the existing collectors are reused. Any other registration error
+// causes a panic, matching prometheus.MustRegister.
+func NewMetricsProvider(reg prometheus.Registerer, name string) handler.MetricsProvider {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	handled := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: name,
+		Name:      "records_handled_total",
+		Help:      "Total number of log records handled, by level.",
+	}, []string{"level"})
+
+	errs := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: name,
+		Name:      "errors_total",
+		Help:      "Total number of log records the handler failed to handle.",
+	})
+
+	return &metricsProvider{
+		handled: register(reg, handled),
+		errors:  register(reg, errs),
+	}
+}
+
+// RecordHandled increments the handled counter for level.
+func (m *metricsProvider) RecordHandled(level handler.LogLevel) {
+	m.handled.WithLabelValues(level.String()).Inc()
+}
+
+// RecordError increments the error counter.
+func (m *metricsProvider) RecordError() {
+	m.errors.Inc()
+}
+
+// register registers c with reg, returning the already registered collector
+// if an identical one exists.
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) C {
+	err := reg.Register(c)
+	if err == nil {
+		return c
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(C); ok {
+			return existing
+		}
+	}
+
+	panic(err)
+}
@@ -0,0 +1,89 @@
+package prometheus_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/balinomad/go-unilog/handler"
+	unilogprom "github.com/balinomad/go-unilog/prometheus"
+)
+
+func TestNewMetricsProvider(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	m := unilogprom.NewMetricsProvider(reg, "app")
+
+	m.RecordHandled(handler.InfoLevel)
+	m.RecordHandled(handler.InfoLevel)
+	m.RecordHandled(handler.ErrorLevel)
+	m.RecordError()
+
+	if got := testutil.CollectAndCount(reg, "app_records_handled_total"); got != 2 {
+		t.Errorf("handled series = %d, want 2", got)
+	}
+	if got := counterValue(t, reg, "app_records_handled_total", "INFO"); got != 2 {
+		t.Errorf("INFO count = %v, want 2", got)
+	}
+	if got := counterValue(t, reg, "app_errors_total", ""); got != 1 {
+		t.Errorf("errors = %v, want 1", got)
+	}
+}
+
+func TestNewMetricsProvider_SharedName(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	a := unilogprom.NewMetricsProvider(reg, "app")
+	b := unilogprom.NewMetricsProvider(reg, "app")
+
+	a.RecordError()
+	b.RecordError()
+
+	if got := counterValue(t, reg, "app_errors_total", ""); got != 2 {
+		t.Errorf("errors = %v, want 2", got)
+	}
+}
+
+func TestNewMetricsProvider_Conflict(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: "app_errors_total"}))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewMetricsProvider() did not panic on conflicting registration")
+		}
+	}()
+	unilogprom.NewMetricsProvider(reg, "app")
+}
+
+// counterValue returns the value of the named counter, optionally filtered by level label.
+func counterValue(t *testing.T, reg *prometheus.Registry, name, level string) float64 {
+	t.Helper()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if level == "" {
+				return m.GetCounter().GetValue()
+			}
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "level" && l.GetValue() == level {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,140 @@
+package unilog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// selfTestKey is the attribute key attached to every self-test record.
+const selfTestKey = "unilog_selftest"
+
+// SelfTest checks that l behaves as configured, e.g. at startup or in CI to
+// confirm that the level and handler options were wired as intended. It
+// returns nil if no discrepancy was found, or an error joining one error per
+// discrepancy.
+//
+// For loggers created with NewLogger or NewAdvancedLogger, one record per
+// level is sent through the logger's own pipeline to a capture handler that
+// mirrors the real handler's Enabled and caller settings; nothing reaches
+// the real handler's output, and FATAL and PANIC do not terminate. It
+// checks that exactly the enabled levels are delivered, with their level
+// and attributes intact and caller information when caller reporting is
+// enabled. For all loggers, it checks that Level matches the lowest
+// enabled level, and that tracing, if enabled, applies to some enabled level.
+func SelfTest(l Logger) error {
+	if l == nil {
+		return errors.New("logger cannot be nil")
+	}
+
+	var errs []error
+
+	for level := handler.MinLevel; level <= handler.MaxLevel; level++ {
+		if !l.Enabled(level) {
+			continue
+		}
+		if got := l.Level(); got != level {
+			errs = append(errs, fmt.Errorf("minimum level is %s, but the lowest enabled level is %s", got, level))
+		}
+		break
+	}
+
+	ll, ok := l.(*logger)
+	if !ok {
+		return errors.Join(errs...)
+	}
+
+	state := ll.h.HandlerState()
+	if state.TraceEnabled() {
+		if s, ok := state.(interface{ TraceLevel() LogLevel }); ok {
+			traced := false
+			for level := s.TraceLevel(); level <= handler.MaxLevel; level++ {
+				traced = traced || l.Enabled(level)
+			}
+			if !traced {
+				errs = append(errs, fmt.Errorf("trace is enabled from %s, but no level at or above it is enabled", s.TraceLevel()))
+			}
+		}
+	}
+
+	return errors.Join(append(errs, ll.selfTestRecords()...)...)
+}
+
+// selfTestRecords logs one record per level through a copy of l whose
+// handler captures records instead of handling them, and reports every
+// record that was not delivered as configured.
+func (l *logger) selfTestRecords() []error {
+	l.mu.RLock()
+	capture := &captureHandler{Handler: l.h}
+	probe := &logger{
+		h:           capture,
+		state:       l.state,
+		skip:        l.skip,
+		needsPC:     l.needsPC,
+		needsSkip:   l.needsSkip,
+		callerLevel: l.callerLevel,
+		opts:        l.opts,
+	}
+	l.mu.RUnlock()
+
+	probe.opts.handleTimeout = 0
+	probe.opts.exitFunc = func(int) {}
+	probe.opts.panicFunc = func(string) {}
+	probe.opts.levelFromCtx = nil // Checked against the handler's levels
+
+	var errs []error
+	for level := handler.MinLevel; level <= handler.MaxLevel; level++ {
+		capture.records = capture.records[:0]
+		probe.Log(context.Background(), level, "unilog self-test", selfTestKey, level.String())
+
+		enabled := l.h.Enabled(level)
+		switch {
+		case !enabled && len(capture.records) > 0:
+			errs = append(errs, fmt.Errorf("%s: record delivered although the level is disabled", level))
+			continue
+		case !enabled:
+			continue
+		case len(capture.records) != 1:
+			errs = append(errs, fmt.Errorf("%s: %d records delivered, want 1", level, len(capture.records)))
+			continue
+		}
+
+		r := capture.records[0]
+		if r.Level != level {
+			errs = append(errs, fmt.Errorf("%s: record delivered at level %s", level, r.Level))
+		}
+		if i := slices.Index(r.KeyValues, any(selfTestKey)); i < 0 || i+1 >= len(r.KeyValues) || r.KeyValues[i+1] != level.String() {
+			errs = append(errs, fmt.Errorf("%s: attribute %q missing from record", level, selfTestKey))
+		}
+		if level < l.callerLevel {
+			continue // No caller expected below the handler's caller level
+		}
+		if l.needsPC && r.PC == 0 {
+			errs = append(errs, fmt.Errorf("%s: caller reporting is enabled, but the record has no caller", level))
+		}
+		if l.needsSkip && r.Skip == 0 {
+			errs = append(errs, fmt.Errorf("%s: caller reporting is enabled, but the record has no caller skip", level))
+		}
+	}
+
+	return errs
+}
+
+// captureHandler records copies of handled records instead of passing them
+// to the embedded handler, which still answers every other method.
+type captureHandler struct {
+	handler.Handler
+	records []handler.Record
+}
+
+// Handle stores a copy of r, detached from the logger's record pool.
+func (h *captureHandler) Handle(_ context.Context, r *handler.Record) error {
+	rec := *r
+	rec.KeyValues = slices.Clone(r.KeyValues)
+	h.records = append(h.records, rec)
+
+	return nil
+}
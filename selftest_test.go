@@ -0,0 +1,77 @@
+package unilog_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/balinomad/go-unilog"
+	"github.com/balinomad/go-unilog/handler"
+)
+
+// levelMismatchHandler reports DEBUG as its minimum level while only
+// enabling WARN and above.
+type levelMismatchHandler struct{ *handler.MemoryHandler }
+
+func (h levelMismatchHandler) HandlerState() handler.HandlerState { return h }
+func (h levelMismatchHandler) Level() handler.LogLevel            { return handler.DebugLevel }
+
+func TestSelfTest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("configured logger", func(t *testing.T) {
+		t.Parallel()
+		h, _ := handler.NewMemoryHandler(1024)
+		_ = h.SetLevel(handler.WarnLevel)
+		l, _ := unilog.NewLogger(h)
+
+		if err := unilog.SelfTest(l); err != nil {
+			t.Errorf("SelfTest() error = %v", err)
+		}
+		if out := h.Bytes(); len(out) != 0 {
+			t.Errorf("SelfTest() wrote %q to the handler", out)
+		}
+	})
+
+	t.Run("caller enabled", func(t *testing.T) {
+		t.Parallel()
+		h := newMockHandler()
+		h.state = &mockHandlerState{caller: true}
+		l, _ := unilog.NewLogger(h, unilog.WithExitFunc(func(int) { t.Error("exit called") }))
+
+		if err := unilog.SelfTest(l.Skip(1)); err != nil {
+			t.Errorf("SelfTest() error = %v", err)
+		}
+		if n := getMockHandler(t, l).CallCount(); n != 0 {
+			t.Errorf("SelfTest() handled %d records on the real handler", n)
+		}
+	})
+
+	t.Run("level mismatch", func(t *testing.T) {
+		t.Parallel()
+		mh, _ := handler.NewMemoryHandler(1024)
+		_ = mh.SetLevel(handler.WarnLevel)
+		l, _ := unilog.NewLogger(levelMismatchHandler{mh})
+
+		err := unilog.SelfTest(l)
+		if err == nil || !strings.Contains(err.Error(), "lowest enabled level is WARN") {
+			t.Errorf("SelfTest() error = %v, want level mismatch", err)
+		}
+	})
+
+	t.Run("fallback logger", func(t *testing.T) {
+		t.Parallel()
+		l, _ := unilog.XNewFallbackLogger(io.Discard, unilog.InfoLevel)
+
+		if err := unilog.SelfTest(l); err != nil {
+			t.Errorf("SelfTest() error = %v", err)
+		}
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		t.Parallel()
+		if err := unilog.SelfTest(nil); err == nil {
+			t.Error("SelfTest(nil) error = nil, want error")
+		}
+	})
+}
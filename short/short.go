@@ -0,0 +1,101 @@
+// Package short provides single-letter aliases for the common log levels,
+// for call sites where brevity matters more than readability:
+//
+//	short.I(ctx, "request served", "status", 200)
+//
+// The package-level functions log through the unilog default logger;
+// Wrap adds the same methods to any unilog.Logger. Caller reporting points
+// at the call site, not at this package.
+package short
+
+import (
+	"context"
+
+	"github.com/balinomad/go-unilog"
+)
+
+// Logger is a unilog.Logger with single-letter level methods.
+type Logger interface {
+	unilog.Logger
+
+	// D logs a message at the debug level.
+	D(ctx context.Context, msg string, keyValues ...any)
+
+	// I logs a message at the info level.
+	I(ctx context.Context, msg string, keyValues ...any)
+
+	// W logs a message at the warn level.
+	W(ctx context.Context, msg string, keyValues ...any)
+
+	// E logs a message at the error level.
+	E(ctx context.Context, msg string, keyValues ...any)
+}
+
+// shortLogger adds the single-letter methods to a unilog.Logger.
+type shortLogger struct {
+	unilog.Logger
+}
+
+// Ensure shortLogger implements Logger.
+var _ Logger = (*shortLogger)(nil)
+
+// Wrap returns l with single-letter level methods added.
+// If l already implements Logger, it is returned as is.
+func Wrap(l unilog.Logger) Logger {
+	if sl, ok := l.(Logger); ok {
+		return sl
+	}
+
+	return &shortLogger{Logger: l}
+}
+
+// D logs a message at the debug level.
+func (l *shortLogger) D(ctx context.Context, msg string, keyValues ...any) {
+	l.log(ctx, unilog.DebugLevel, msg, keyValues...)
+}
+
+// I logs a message at the info level.
+func (l *shortLogger) I(ctx context.Context, msg string, keyValues ...any) {
+	l.log(ctx, unilog.InfoLevel, msg, keyValues...)
+}
+
+// W logs a message at the warn level.
+func (l *shortLogger) W(ctx context.Context, msg string, keyValues ...any) {
+	l.log(ctx, unilog.WarnLevel, msg, keyValues...)
+}
+
+// E logs a message at the error level.
+func (l *shortLogger) E(ctx context.Context, msg string, keyValues ...any) {
+	l.log(ctx, unilog.ErrorLevel, msg, keyValues...)
+}
+
+// log forwards to the wrapped logger, skipping the frames of this package
+// when the logger supports it.
+func (l *shortLogger) log(ctx context.Context, level unilog.LogLevel, msg string, keyValues ...any) {
+	if adv, ok := l.Logger.(unilog.AdvancedLogger); ok {
+		// Skip the level method and this function
+		adv.LogWithSkip(ctx, level, msg, 2, keyValues...)
+		return
+	}
+	l.Logger.Log(ctx, level, msg, keyValues...)
+}
+
+// D logs a message at the debug level using the global default logger.
+func D(ctx context.Context, msg string, keyValues ...any) {
+	unilog.LogWithSkip(ctx, unilog.DebugLevel, msg, 1, keyValues...)
+}
+
+// I logs a message at the info level using the global default logger.
+func I(ctx context.Context, msg string, keyValues ...any) {
+	unilog.LogWithSkip(ctx, unilog.InfoLevel, msg, 1, keyValues...)
+}
+
+// W logs a message at the warn level using the global default logger.
+func W(ctx context.Context, msg string, keyValues ...any) {
+	unilog.LogWithSkip(ctx, unilog.WarnLevel, msg, 1, keyValues...)
+}
+
+// E logs a message at the error level using the global default logger.
+func E(ctx context.Context, msg string, keyValues ...any) {
+	unilog.LogWithSkip(ctx, unilog.ErrorLevel, msg, 1, keyValues...)
+}
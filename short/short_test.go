@@ -0,0 +1,100 @@
+package short_test
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/balinomad/go-unilog"
+	"github.com/balinomad/go-unilog/handler"
+	"github.com/balinomad/go-unilog/short"
+)
+
+// pcHandler records the level and caller of the last record.
+type pcHandler struct {
+	mu    sync.Mutex
+	level handler.LogLevel
+	pc    uintptr
+}
+
+func (h *pcHandler) Handle(_ context.Context, r *handler.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.level, h.pc = r.Level, r.PC
+	return nil
+}
+
+func (h *pcHandler) Enabled(handler.LogLevel) bool      { return true }
+func (h *pcHandler) HandlerState() handler.HandlerState { return h }
+func (h *pcHandler) Features() handler.HandlerFeatures  { return handler.HandlerFeatures{} }
+func (h *pcHandler) CallerEnabled() bool                { return true }
+func (h *pcHandler) TraceEnabled() bool                 { return false }
+func (h *pcHandler) CallerSkip() int                    { return 0 }
+
+// last returns the level and calling function of the last record.
+func (h *pcHandler) last() (handler.LogLevel, string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	frame, _ := runtime.CallersFrames([]uintptr{h.pc}).Next()
+	return h.level, frame.Function
+}
+
+type shortFunc func(ctx context.Context, msg string, keyValues ...any)
+
+func checkShorthands(t *testing.T, h *pcHandler, funcs map[handler.LogLevel]shortFunc) {
+	t.Helper()
+
+	for want, fn := range funcs {
+		fn(context.Background(), "msg")
+
+		level, caller := h.last()
+		if level != want {
+			t.Errorf("level = %v, want %v", level, want)
+		}
+		if !strings.HasPrefix(caller, "github.com/balinomad/go-unilog/short_test.") {
+			t.Errorf("%v: caller = %s, want a short_test frame", want, caller)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	h := &pcHandler{}
+	l, err := unilog.NewLogger(h)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	sl := short.Wrap(l)
+
+	if short.Wrap(sl) != sl {
+		t.Error("Wrap() of a short.Logger should return it unchanged")
+	}
+
+	checkShorthands(t, h, map[handler.LogLevel]shortFunc{
+		handler.DebugLevel: sl.D,
+		handler.InfoLevel:  sl.I,
+		handler.WarnLevel:  sl.W,
+		handler.ErrorLevel: sl.E,
+	})
+}
+
+func TestPackageFunctions(t *testing.T) {
+	h := &pcHandler{}
+	l, err := unilog.NewLogger(h)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	prev := unilog.Default()
+	unilog.SetDefault(l)
+	defer unilog.SetDefault(prev)
+
+	checkShorthands(t, h, map[handler.LogLevel]shortFunc{
+		handler.DebugLevel: short.D,
+		handler.InfoLevel:  short.I,
+		handler.WarnLevel:  short.W,
+		handler.ErrorLevel: short.E,
+	})
+}
@@ -0,0 +1,41 @@
+package unilog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StdCompatLogger offers the Print methods of the standard library's
+// *log.Logger on top of a Logger, logging at a fixed level.
+//
+// It is a migration aid, so that code written against the log package
+// compiles against unilog while it is being converted. New code should call
+// the Logger methods, which take a context and structured attributes.
+type StdCompatLogger struct {
+	l     Logger
+	level LogLevel
+}
+
+// StdCompat returns a StdCompatLogger that logs through l at level with a
+// background context. The reported caller is the code calling the shim's
+// methods, not the shim itself.
+func StdCompat(l Logger, level LogLevel) *StdCompatLogger {
+	return &StdCompatLogger{l: l.Skip(1), level: level}
+}
+
+// Print logs its arguments formatted as by fmt.Sprint.
+func (s *StdCompatLogger) Print(v ...any) {
+	s.l.Log(context.Background(), s.level, fmt.Sprint(v...))
+}
+
+// Printf logs its arguments formatted as by fmt.Sprintf.
+func (s *StdCompatLogger) Printf(format string, v ...any) {
+	s.l.Log(context.Background(), s.level, fmt.Sprintf(format, v...))
+}
+
+// Println logs its arguments formatted as by fmt.Sprintln, without the
+// trailing newline.
+func (s *StdCompatLogger) Println(v ...any) {
+	s.l.Log(context.Background(), s.level, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
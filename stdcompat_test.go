@@ -0,0 +1,43 @@
+package unilog_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/balinomad/go-unilog"
+)
+
+func TestStdCompat(t *testing.T) {
+	t.Parallel()
+
+	// The minimal handler has no CallerAdjuster, so every derived logger
+	// records to the same handler
+	h := newMockMinimalHandler()
+	h.target.state = &mockHandlerState{caller: true}
+	l, _ := unilog.NewLogger(h)
+	std := unilog.StdCompat(l, unilog.WarnLevel)
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{"Print", func() { std.Print("a", 1, 2, "b") }, "a1 2b"},
+		{"Printf", func() { std.Printf("user %s, attempt %d", "jane", 3) }, "user jane, attempt 3"},
+		{"Println", func() { std.Println("a", 1, "b") }, "a 1 b"},
+	}
+
+	for _, tt := range tests {
+		tt.log()
+
+		r := h.target.LastRecord()
+		if r.Message != tt.want || r.Level != unilog.WarnLevel {
+			t.Errorf("%s: record = %v %q, want %v %q", tt.name, r.Level, r.Message, unilog.WarnLevel, tt.want)
+		}
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		if !strings.HasSuffix(frame.File, "stdcompat_test.go") {
+			t.Errorf("%s: caller = %s:%d, want stdcompat_test.go", tt.name, frame.File, frame.Line)
+		}
+	}
+}
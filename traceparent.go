@@ -0,0 +1,99 @@
+package unilog
+
+import (
+	"context"
+	"strings"
+)
+
+// Keys of the fields returned by TraceParentFields, following the
+// OpenTelemetry log data model.
+const (
+	TraceIDKey    = "trace_id"
+	SpanIDKey     = "span_id"
+	TraceFlagsKey = "trace_flags"
+)
+
+// ctxTraceParentKey is the context key for the W3C traceparent value.
+type ctxTraceParentKey struct{}
+
+// WithTraceParent returns a new context carrying a W3C traceparent value,
+// typically the "traceparent" header of an incoming request:
+//
+//	ctx = unilog.WithTraceParent(ctx, r.Header.Get("traceparent"))
+//
+// The value is stored as is and validated by TraceParentFields.
+func WithTraceParent(ctx context.Context, traceparent string) context.Context {
+	return context.WithValue(ctx, ctxTraceParentKey{}, traceparent)
+}
+
+// TraceParentFields returns the trace ID, span ID and trace flags of the
+// traceparent stored in ctx with WithTraceParent, as key-value pairs ready
+// to pass to Logger.With or a log call:
+//
+//	logger.With(unilog.TraceParentFields(ctx)...).Info(ctx, "request served")
+//
+// It needs no tracing SDK, so it works with traces propagated by a proxy.
+// It returns nil if ctx carries no traceparent or the value is not a valid
+// W3C trace context (https://www.w3.org/TR/trace-context/#traceparent-header).
+func TraceParentFields(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+
+	v, _ := ctx.Value(ctxTraceParentKey{}).(string)
+	traceID, spanID, flags, ok := parseTraceParent(v)
+	if !ok {
+		return nil
+	}
+
+	return []any{TraceIDKey, traceID, SpanIDKey, spanID, TraceFlagsKey, flags}
+}
+
+// parseTraceParent splits a traceparent value into its trace ID, parent
+// span ID and trace flags. Versions other than 00 are accepted as long as
+// their first four fields have the version 00 layout, as the specification
+// requires of parsers.
+func parseTraceParent(v string) (traceID, spanID, flags string, ok bool) {
+	v = strings.TrimSpace(v)
+
+	// version(2) "-" trace-id(32) "-" parent-id(16) "-" flags(2)
+	const length = 55
+	if len(v) < length || (len(v) > length && v[length] != '-') {
+		return "", "", "", false
+	}
+
+	version := v[0:2]
+	if v[2] != '-' || v[35] != '-' || v[52] != '-' {
+		return "", "", "", false
+	}
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(v) != length) {
+		return "", "", "", false
+	}
+
+	traceID, spanID, flags = v[3:35], v[36:52], v[53:55]
+	if !isLowerHex(traceID) || !isLowerHex(spanID) || !isLowerHex(flags) {
+		return "", "", "", false
+	}
+	if isAllZeros(traceID) || isAllZeros(spanID) {
+		return "", "", "", false
+	}
+
+	return traceID, spanID, flags, true
+}
+
+// isLowerHex reports whether s consists of lowercase hexadecimal digits.
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isAllZeros reports whether s consists of '0' characters only.
+func isAllZeros(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
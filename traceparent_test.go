@@ -0,0 +1,71 @@
+package unilog_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/balinomad/go-unilog"
+)
+
+func TestTraceParentFields(t *testing.T) {
+	t.Parallel()
+
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name  string
+		value string
+		want  []any
+	}{
+		{
+			name:  "sampled",
+			value: "00-" + traceID + "-" + spanID + "-01",
+			want:  []any{"trace_id", traceID, "span_id", spanID, "trace_flags", "01"},
+		},
+		{
+			name:  "not sampled with surrounding spaces",
+			value: " 00-" + traceID + "-" + spanID + "-00 ",
+			want:  []any{"trace_id", traceID, "span_id", spanID, "trace_flags", "00"},
+		},
+		{
+			name:  "future version with extra fields",
+			value: "01-" + traceID + "-" + spanID + "-01-extra",
+			want:  []any{"trace_id", traceID, "span_id", spanID, "trace_flags", "01"},
+		},
+		{name: "empty", value: ""},
+		{name: "version 00 with extra fields", value: "00-" + traceID + "-" + spanID + "-01-extra"},
+		{name: "forbidden version", value: "ff-" + traceID + "-" + spanID + "-01"},
+		{name: "uppercase hex", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01"},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-" + spanID + "-01"},
+		{name: "zero span id", value: "00-" + traceID + "-0000000000000000-01"},
+		{name: "wrong separator", value: "00_" + traceID + "-" + spanID + "-01"},
+		{name: "truncated", value: "00-" + traceID + "-" + spanID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := unilog.WithTraceParent(context.Background(), tt.value)
+			got := unilog.TraceParentFields(ctx)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TraceParentFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("absent", func(t *testing.T) {
+		t.Parallel()
+
+		if got := unilog.TraceParentFields(context.Background()); got != nil {
+			t.Errorf("TraceParentFields() = %v, want nil", got)
+		}
+		if got := unilog.TraceParentFields(nil); got != nil {
+			t.Errorf("TraceParentFields(nil) = %v, want nil", got)
+		}
+	})
+}
@@ -47,12 +47,25 @@ type Logger interface {
 	// Enabled reports whether logging at the given level is currently enabled.
 	Enabled(level LogLevel) bool
 
+	// Level returns the current minimum level that is logged.
+	Level() LogLevel
+
 	// With returns a new Logger that always includes the given key-value pairs.
 	With(keyValues ...any) Logger
 
 	// WithGroup returns a new Logger that starts a key-value group.
 	WithGroup(name string) Logger
 
+	// WithGroupf is like WithGroup with the name formatted by fmt.Sprintf.
+	WithGroupf(format string, args ...any) Logger
+
+	// Skip returns a new Logger that reports the caller n frames further up
+	// the stack, for helpers that wrap logging calls. It adds to the current
+	// skip like AdvancedLogger.WithCallerSkipDelta, rather than replacing it
+	// like AdvancedLogger.WithCallerSkip. Loggers that cannot adjust caller
+	// reporting return themselves.
+	Skip(n int) Logger
+
 	// Trace is a convenience method that logs a message at the trace level.
 	Trace(ctx context.Context, msg string, keyValues ...any)
 
@@ -88,6 +101,11 @@ type AdvancedLogger interface {
 	// Use it when you need a single log entry with a different caller skip.
 	LogWithSkip(ctx context.Context, level LogLevel, msg string, delta int, keyValues ...any)
 
+	// LogRaw writes a pre-rendered line at the given level, bypassing attribute normalization
+	// and formatting, if the handler implements handler.RawWriter. Other handlers log the payload,
+	// without its trailing newline, as the message. Logging on Fatal and Panic levels will exit the process.
+	LogRaw(ctx context.Context, level LogLevel, payload []byte)
+
 	// WithCallerSkip returns a new AdvancedLogger with the caller skip set permanently.
 	// It returns the original logger if the skip value is unchanged.
 	WithCallerSkip(skip int) AdvancedLogger
@@ -118,6 +136,12 @@ type AdvancedLogger interface {
 	// Sync flushes buffered log entries if supported by the handler. Returns error on flush failure.
 	Sync() error
 
+	// Handler returns the handler the logger writes to, e.g. to pass it to another library
+	// or to call backend-specific methods after a type assertion. The handler already has
+	// the logger's attributes, groups and caller skip applied. Reconfiguring it directly
+	// bypasses the logger: derived loggers and the cached caller settings are not updated.
+	Handler() handler.Handler
+
 	/*
 		Future plans:
 